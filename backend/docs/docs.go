@@ -23,21 +23,21 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/categories": {
+        "/accounts": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get all categories for the authenticated user",
+                "description": "Get all accounts for the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "accounts"
                 ],
-                "summary": "List user categories",
+                "summary": "List accounts",
                 "parameters": [
                     {
                         "type": "string",
@@ -53,7 +53,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.UserCategory"
+                                "$ref": "#/definitions/models.Account"
                             }
                         }
                     },
@@ -64,7 +64,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to list categories",
+                        "description": "Failed to list accounts",
                         "schema": {
                             "type": "string"
                         }
@@ -77,7 +77,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add a new category for the authenticated user",
+                "description": "Add a bank account or card for the authenticated user. The currency defaults to the user's base currency.",
                 "consumes": [
                     "application/json"
                 ],
@@ -85,9 +85,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "accounts"
                 ],
-                "summary": "Add a new category",
+                "summary": "Add an account",
                 "parameters": [
                     {
                         "type": "string",
@@ -97,12 +97,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Category to add",
-                        "name": "category",
+                        "description": "Account to add",
+                        "name": "account",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserCategory"
+                            "$ref": "#/definitions/models.Account"
                         }
                     }
                 ],
@@ -110,10 +110,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
                     "400": {
@@ -129,7 +126,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to add category",
+                        "description": "Failed to create account",
                         "schema": {
                             "type": "string"
                         }
@@ -137,24 +134,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{id}": {
-            "put": {
+        "/accounts/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update an existing category for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get an account by its ID for the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "accounts"
                 ],
-                "summary": "Update a category",
+                "summary": "Get account by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -165,32 +159,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Category ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated category",
-                        "name": "category",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UserCategory"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Missing category ID or invalid request body",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
                     "401": {
@@ -199,8 +178,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Failed to update category",
+                    "404": {
+                        "description": "Account not found",
                         "schema": {
                             "type": "string"
                         }
@@ -213,14 +192,11 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete a category for the authenticated user",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Delete an account for the authenticated user. Accounts that still have transactions cannot be deleted.",
                 "tags": [
-                    "categories"
+                    "accounts"
                 ],
-                "summary": "Delete a category",
+                "summary": "Delete an account",
                 "parameters": [
                     {
                         "type": "string",
@@ -231,7 +207,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Category ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -244,74 +220,49 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Missing category ID",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Failed to delete category",
+                    "404": {
+                        "description": "Account not found",
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Returns the health status of the API",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Account still has transactions",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Failed to encode response",
+                        "description": "Failed to delete account",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/import": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Import transactions for the authenticated user from a CSV file",
+                "description": "Update an existing account for the authenticated user",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "import"
+                    "accounts"
                 ],
-                "summary": "Import transactions from CSV",
+                "summary": "Update an account",
                 "parameters": [
                     {
                         "type": "string",
@@ -321,25 +272,31 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "CSV file",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Account update data",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AccountUpdate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Transaction"
-                            }
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
                     "400": {
-                        "description": "Failed to read file",
+                        "description": "Invalid request body",
                         "schema": {
                             "type": "string"
                         }
@@ -350,8 +307,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to parse csv or save transactions",
+                        "description": "Failed to update account",
                         "schema": {
                             "type": "string"
                         }
@@ -359,21 +322,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions": {
+        "/accounts/{id}/balances": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List transactions for the authenticated user, optionally filtered",
+                "description": "Compute the balance after each transaction in an account, starting from its opening balance",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "List transactions",
+                "summary": "Running balances for an account",
                 "parameters": [
                     {
                         "type": "string",
@@ -384,8 +347,21 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Filters (key=value)",
-                        "name": "filters",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD)",
+                        "name": "to",
                         "in": "query"
                     }
                 ],
@@ -393,10 +369,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Transaction"
-                            }
+                            "$ref": "#/definitions/models.AccountBalances"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date range",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "401": {
@@ -405,31 +384,36 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to list transactions",
+                        "description": "Failed to compute balances",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/accounts/{id}/checkpoints": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new transaction for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the stated balances recorded for an account, oldest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "Create a transaction",
+                "summary": "List balance checkpoints",
                 "parameters": [
                     {
                         "type": "string",
@@ -439,51 +423,50 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Transaction to create",
-                        "name": "transaction",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Transaction"
-                        }
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BalanceCheckpoint"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Account not found",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Failed to create transaction",
+                        "description": "Failed to list balance checkpoints",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/transactions/bulk": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add multiple transactions for the authenticated user",
+                "description": "Record the balance a statement shows at the end of a date",
                 "consumes": [
                     "application/json"
                 ],
@@ -491,9 +474,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "Bulk add transactions",
+                "summary": "Record a statement balance",
                 "parameters": [
                     {
                         "type": "string",
@@ -503,15 +486,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Transactions to add",
-                        "name": "transactions",
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stated balance",
+                        "name": "checkpoint",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Transaction"
-                            }
+                            "$ref": "#/definitions/api.BalanceCheckpointRequest"
                         }
                     }
                 ],
@@ -519,10 +506,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Transaction"
-                            }
+                            "$ref": "#/definitions/models.BalanceCheckpoint"
                         }
                     },
                     "400": {
@@ -537,8 +521,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to bulk add transactions",
+                        "description": "Failed to add balance checkpoint",
                         "schema": {
                             "type": "string"
                         }
@@ -546,21 +536,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/{id}": {
-            "get": {
+        "/accounts/{id}/checkpoints/{checkpointId}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get a transaction by its ID for the authenticated user",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Delete a recorded statement balance",
                 "tags": [
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "Get transaction by ID",
+                "summary": "Delete a balance checkpoint",
                 "parameters": [
                     {
                         "type": "string",
@@ -571,21 +558,22 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Transaction ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Checkpoint ID",
+                        "name": "checkpointId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Transaction"
-                        }
-                    },
-                    "400": {
-                        "description": "Missing transaction ID",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
                             "type": "string"
                         }
@@ -596,37 +584,36 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Balance checkpoint not found",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Transaction not found",
+                    "500": {
+                        "description": "Failed to delete balance checkpoint",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/accounts/{id}/reconciliation": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update an existing transaction for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Compare the account's balance checkpoints with the balances computed from its transactions and flag the periods where they drift apart",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "Update a transaction",
+                "summary": "Reconcile an account",
                 "parameters": [
                     {
                         "type": "string",
@@ -637,18 +624,120 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Transaction ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReconciliationReport"
+                        }
                     },
-                    {
-                        "description": "Transaction update data",
-                        "name": "transaction",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compute balances",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all budgets for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "List budgets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Budget"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list budgets",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a spending limit for one of the authenticated user's categories, given by categoryId or category. The period defaults to monthly, the currency to the user's base currency and the start date to the current period.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Add a budget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Budget to add",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.TransactionUpdate"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     }
                 ],
@@ -656,11 +745,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
-                        "description": "Missing transaction ID or invalid request body",
+                        "description": "Invalid request body",
                         "schema": {
                             "type": "string"
                         }
@@ -671,20 +760,67 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "500": {
+                        "description": "Failed to create budget",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a budget by its ID for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Get budget by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Transaction not found",
+                        "description": "Budget not found",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Failed to update transaction",
+                        "description": "Failed to get budget",
                         "schema": {
                             "type": "string"
                         }
@@ -697,14 +833,71 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete a transaction for the authenticated user",
+                "description": "Delete a budget for the authenticated user",
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Delete a budget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete budget",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update an existing budget for the authenticated user. Set the category by categoryId or by name.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "budgets"
                 ],
-                "summary": "Delete a transaction",
+                "summary": "Update a budget",
                 "parameters": [
                     {
                         "type": "string",
@@ -715,21 +908,30 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Transaction ID",
+                        "description": "Budget ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Budget update data",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetUpdate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
-                        "description": "Missing transaction ID",
+                        "description": "Invalid request body",
                         "schema": {
                             "type": "string"
                         }
@@ -740,76 +942,4190 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "404": {
-                        "description": "Transaction not found",
+                        "description": "Budget not found",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete transaction",
+                        "description": "Failed to update budget",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Transaction": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "integer"
-                },
-                "bankReference": {
-                    "type": "string"
-                },
-                "category": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "insertedAt": {
-                    "type": "string"
-                },
-                "transactionDateTime": {
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string"
-                },
-                "updatedAt": {
-                    "type": "string"
-                },
-                "userId": {
-                    "type": "string"
-                }
-            }
         },
-        "models.TransactionUpdate": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "integer"
-                },
-                "category": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
+        "/budgets/{id}/progress": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Compute spending, what remains and the projected spending at the end of the budget period containing the given date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Budget progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date within the period to report on (YYYY-MM-DD), defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compute budget progress",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all categories for the authenticated user. Subcategories name their parent in parentId.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "List user categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserCategory"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list categories",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a new category for the authenticated user. Names must be unique; set parentId to make it a subcategory.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Add a new category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Category to add",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add category",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/mappings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the merchant to category mappings learned from the authenticated user's corrections",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "List learned categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategoryMapping"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list category mappings",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/mappings/{merchant}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stop giving a merchant the category learned from the authenticated user's corrections",
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Forget a learned category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Merchant key",
+                        "name": "merchant",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Category mapping not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete category mapping",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/packs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the category packs users can be seeded from, such as uk and us, with their categories and keywords",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "List category packs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategoryPack"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/packs/{name}/seed": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Merge a category pack into the authenticated user's categories. Categories are matched by name, ignoring case: missing ones are added and existing ones gain any keywords from the pack they lack, but keep their place in the hierarchy. Seeding a pack again only restores what has since been removed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Merge a category pack",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pack name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategorySeedResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown category pack",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to seed categories",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/suggestions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Group the authenticated user's spending left in Other by merchant and propose a category, with keywords, for each group. Suggestions are ranked by total spend in the user's base currency, and names that are already categories are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Suggest new categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Fewest transactions a suggestion must cover, defaults to 2",
+                        "name": "minCount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most suggestions to return, defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategorySuggestions"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to suggest categories",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/suggestions/accept": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a category from a suggestion, or an edited one, and move the transactions in Other that its keywords match into it. Set parentId to create it as a subcategory.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Accept a category suggestion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Category to create, with at least one keyword",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AcceptedSuggestion"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or category",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to accept category suggestion",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a category for the authenticated user, moving its transactions to the category reassignTo. Categories with subcategories cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Delete a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the category to move the transactions to",
+                        "name": "reassignTo",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryChangeResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing category ID or invalid reassignTo",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Category still has subcategories",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete category",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace an existing category for the authenticated user, including its parent. Renaming a category renames it on all of its transactions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Update a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated category",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryChangeResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing category ID or invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update category",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/envelopes/{month}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's envelopes and ready to assign amount for a month, in their base currency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "envelopes"
+                ],
+                "summary": "Envelopes for a month",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnvelopeMonth"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid month",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compute envelopes",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/envelopes/{month}/allocations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the assignments, moves and auto-funding recorded for a month, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "envelopes"
+                ],
+                "summary": "Allocation ledger for a month",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Allocation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid month",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list allocations",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/envelopes/{month}/assign": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Assign part of the month's ready to assign amount to a category's envelope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "envelopes"
+                ],
+                "summary": "Assign money to an envelope",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category and amount",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AssignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnvelopeMonth"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid month or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update envelopes",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/envelopes/{month}/autofund": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Top up each envelope to what was assigned to it (basis=assigned) or spent from it (basis=spent) in the previous month. Envelopes already funded to that level are left alone, so repeating the call is safe.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "envelopes"
+                ],
+                "summary": "Auto-fund envelopes from the previous month",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "assigned (default) or spent",
+                        "name": "basis",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnvelopeMonth"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid month or basis",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update envelopes",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/envelopes/{month}/move": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Move money from one envelope to another, for example to cover overspending",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "envelopes"
+                ],
+                "summary": "Move money between envelopes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Envelopes and amount",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.MoveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnvelopeMonth"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid month or request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update envelopes",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Returns the health status of the API",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to encode response",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchants/aliases": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the authenticated user's merchant aliases, which name the merchant for descriptions containing their pattern",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchants"
+                ],
+                "summary": "List merchant aliases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MerchantAlias"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list merchant aliases",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Name the merchant for descriptions containing pattern, in place of the built-in merchant table. The pattern matches whole words, ignoring case and punctuation, and replaces any alias with the same words. Existing transactions are renamed to follow it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchants"
+                ],
+                "summary": "Set a merchant alias",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Pattern and merchant name",
+                        "name": "alias",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MerchantAlias"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MerchantAliasResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to set merchant alias",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchants/aliases/{pattern}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete one of the authenticated user's merchant aliases. Transactions it named are renamed from the built-in merchant table or their description.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchants"
+                ],
+                "summary": "Delete a merchant alias",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alias pattern",
+                        "name": "pattern",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MerchantAliasResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Merchant alias not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete merchant alias",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's categorisation rules in the order they are applied",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "List categorisation rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategoryRule"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list rules",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a rule that sets the category, given by categoryId or category, of new transactions meeting all of its conditions. Rules run in ascending priority and the first match wins; without a priority the rule goes after the existing ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Add a categorisation rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule to add",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create rule",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a categorisation rule by its ID for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Get categorisation rule by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryRule"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get rule",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a categorisation rule for the authenticated user",
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Delete a categorisation rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete rule",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update a categorisation rule for the authenticated user. Set the category by categoryId or by name. Conditions, when given, replace the existing ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Update a categorisation rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule update data",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryRuleUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update rule",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List transactions for the authenticated user, optionally filtered, with amounts converted to their base currency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "List transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only transactions in this account",
+                        "name": "accountId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filters (key=value)",
+                        "name": "filters",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ConvertedTransaction"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list transactions",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new transaction for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Create a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction to create",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create transaction",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add multiple transactions for the authenticated user. Each may name its category by categoryId or category.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Bulk add transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Transactions to add",
+                        "name": "transactions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Transaction"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Transaction"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to bulk add transactions",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Export the authenticated user's transactions, oldest first, as a QIF file that desktop finance software can import. Categories are written with their parents, as in Food:Groceries, and split transactions as split lines. Give an account to export just its transactions under its account type.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Export transactions as QIF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account to export",
+                        "name": "accountId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Export format; only qif is supported",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How to write dates, such as MM/DD/YYYY; defaults to DD/MM/YYYY",
+                        "name": "dateFormat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QIF file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters or account",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export transactions",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Import transactions for the authenticated user from a bank's OFX, QFX, camt.053, MT940 or CSV export, or a QIF file from other finance software, chosen by the file's content. OFX transactions keep their FITID as the bank reference. camt.053 and MT940 transactions are dated by their booking date and keep their value date, with the counterparty as the description and the remittance information alongside. QIF categories and split lines are kept, adding any categories the user doesn't have. Excel .xlsx workbooks are read like CSV files, one worksheet at a time, with date cells and Excel serial dates read as dates. The layout of a CSV file or worksheet is detected from its header row using the user's saved import profiles and the built-in ones for Monzo, Starling, Barclays, HSBC, Nationwide, Lloyds and Amex, unless a profile is named or a column mapping given. When an account is given, transactions it already has from an earlier import are skipped and counted, matching OFX FITIDs and camt.053 servicer references and otherwise the date, amount, reference and description, and balances the file states, or its end-of-day balances from a balance column, are recorded as checkpoints for reconciliation.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Import transactions from a statement file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "OFX, QFX, camt.053, MT940, QIF, CSV or XLSX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account the statement belongs to",
+                        "name": "accountId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the statement, defaults to the account's currency or the user's base currency",
+                        "name": "currency",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Import profile to read a CSV file or workbook with, instead of detecting it",
+                        "name": "profile",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unsaved import profile as JSON, in the form the import profiles endpoint takes; the name is optional and the date format defaults to DD/MM/YYYY",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Worksheet to read from an Excel workbook; defaults to the first",
+                        "name": "sheet",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Row holding the column names, counting from 1; found automatically when unset",
+                        "name": "headerRow",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to read file, unknown import profile or unrecognised layout",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to parse csv or save transactions",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/import/profiles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the CSV layouts imports are detected from: the authenticated user's saved profiles followed by the built-in ones they don't replace",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "List import profiles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ImportProfile"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list import profiles",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Save a CSV layout for the authenticated user's imports, replacing their profile of the same name. A profile named after a built-in one replaces it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Save an import profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Column mapping, date format and sign convention",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid import profile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save import profile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/import/profiles/{name}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete one of the authenticated user's saved import profiles. A built-in profile it replaced is used again.",
+                "tags": [
+                    "import"
+                ],
+                "summary": "Delete an import profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Profile name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Import profile not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete import profile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/merchants": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Total the authenticated user's transactions by merchant name, converted to their base currency using the rate on each transaction date, biggest spend first. Transfers between their own accounts are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Spending by merchant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to report in, defaults to the user's base currency",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MerchantReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date range or currency",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to summarise merchants",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/recategorise": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Re-run the categoriser over the authenticated user's existing transactions, for example after changing rules or category keywords. Categories the user set themselves are never changed. Transactions categorised before the source of a category was recorded may have been categorised by hand, so they are left alone unless includeLegacy is set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Recategorise existing transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only transactions currently in Other",
+                        "name": "onlyOther",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also recategorise transactions whose category has no recorded source",
+                        "name": "includeLegacy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report the changes that would be made",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecategoriseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to recategorise transactions",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/summary": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Total the authenticated user's transactions by category, converted to their base currency using the rate on each transaction date. Rollups add subcategories into their parents.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Summarise transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to report in, defaults to the user's base currency",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date range or currency",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to summarise transactions",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a transaction by its ID for the authenticated user, including categoryMatch, which explains how it got its category",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get transaction by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing transaction ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update an existing transaction for the authenticated user. Set the category by categoryId or by name. A new category is remembered for the transaction's merchant and given to its future transactions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Update a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction update data",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing transaction ID or invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction is split",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update transaction",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a transaction for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Delete a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing transaction ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete transaction",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/splits": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Divide a transaction across categories, replacing any split it already has. Each line needs a non-zero amount and a category, given by categoryId or name, and the lines must sum to the transaction's amount. Summaries, budgets and envelopes then count the lines in place of the transaction's own category.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Split a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Split lines",
+                        "name": "splits",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Split"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or splits",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to split transaction",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove the split lines from a transaction, so that it counts wholly against its own category again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Remove a transaction's split",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to split transaction",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the linked transfers between the authenticated user's accounts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "List transfers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TransferPair"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list transfers",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mark two transactions with equal and opposite amounts as the two sides of a transfer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Link a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Transactions to link",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LinkTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or transactions cannot be linked",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to link transfer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/detect": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pair unlinked transactions in different accounts that have equal and opposite amounts within a date window, and link them unless dryRun is set",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Detect transfers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum days between the two sides, defaults to 3",
+                        "name": "windowDays",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report the pairs that would be linked",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TransferPair"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to link transfer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Unlink the transfer that a transaction belongs to, so both sides count as spending and income again",
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Unlink a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of either transaction in the transfer",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found or not part of a transfer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to unlink transfer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the profile of the authenticated user, including their base currency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get the user profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update the authenticated user's name or base currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update the user profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile update data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or currency",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.AssignRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.BalanceCheckpointRequest": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.LinkTransferRequest": {
+            "type": "object",
+            "properties": {
+                "transactionIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.MoveRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "fromId": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "toId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AcceptedSuggestion": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/models.UserCategory"
+                },
+                "recategorised": {
+                    "$ref": "#/definitions/models.RecategoriseResult"
+                }
+            }
+        },
+        "models.Account": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "openingBalance": {
+                    "description": "OpeningBalance is the balance, in minor units, before the account's\nfirst transaction.",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AccountBalances": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "string"
+                },
+                "closingBalance": {
+                    "description": "ClosingBalance is the balance after every transaction in the account,\nregardless of the requested range.",
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BalanceEntry"
+                    }
+                },
+                "excluded": {
+                    "description": "Excluded counts transactions in a currency other than the account's,\nwhich are left out of the running balance.",
+                    "type": "integer"
+                },
+                "openingBalance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AccountUpdate": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "openingBalance": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Allocation": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "month": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BalanceCheckpoint": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "string"
+                },
+                "balance": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BalanceEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "transactionDateTime": {
+                    "type": "string"
+                },
+                "transactionId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "rollover": {
+                    "type": "boolean"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BudgetProgress": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "budgetId": {
+                    "type": "string"
+                },
+                "budgeted": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "periodEnd": {
+                    "type": "string"
+                },
+                "periodStart": {
+                    "type": "string"
+                },
+                "projected": {
+                    "description": "Projected is the spending expected by the end of the period if it\ncontinues at the rate seen so far.",
+                    "type": "integer"
+                },
+                "projectedOver": {
+                    "type": "boolean"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "rolledOver": {
+                    "description": "RolledOver is the amount carried in from earlier periods, negative when\nthey were overspent. Always zero without rollover.",
+                    "type": "integer"
+                },
+                "spent": {
+                    "type": "integer"
+                },
+                "unconverted": {
+                    "description": "Unconverted holds spending that could not be converted for lack of a\nrate and is excluded from Spent.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotal"
+                    }
+                }
+            }
+        },
+        "models.BudgetUpdate": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "rollover": {
+                    "type": "boolean"
+                },
+                "startDate": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategoryChange": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "match": {
+                    "$ref": "#/definitions/models.CategoryMatch"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "transactionDateTime": {
+                    "type": "string"
+                },
+                "transactionId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategoryChangeResult": {
+            "type": "object",
+            "properties": {
+                "transactionsUpdated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CategoryMapping": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "merchant": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategoryMatch": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "keyword": {
+                    "description": "Keyword is the category keyword found in the description.",
+                    "type": "string"
+                },
+                "merchant": {
+                    "description": "Merchant is the merchant key a learned category was recorded against.",
+                    "type": "string"
+                },
+                "ruleId": {
+                    "type": "string"
+                },
+                "ruleName": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "span": {
+                    "description": "Span is the part of the description that matched, when the match was\non the description.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TextSpan"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.CategoryPack": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackCategory"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategoryRule": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "conditions": {
+                    "$ref": "#/definitions/models.RuleConditions"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategoryRuleUpdate": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "conditions": {
+                    "$ref": "#/definitions/models.RuleConditions"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CategorySeedResult": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pack": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CategorySuggestion": {
+            "type": "object",
+            "properties": {
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "transactionCount": {
+                    "type": "integer"
+                },
+                "transactionIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CategorySuggestions": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "type": "string"
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategorySuggestion"
+                    }
+                }
+            }
+        },
+        "models.CategorySummary": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "original": {
+                    "description": "Original holds the unconverted totals per transaction currency.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotal"
+                    }
+                },
+                "parent": {
+                    "description": "Parent is the name of the category's parent, if it is a subcategory.",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total is in the summary's base currency.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ConvertedAmount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ConvertedTransaction": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "bankReference": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "description": "CategoryID identifies the category; Category holds its name.",
+                    "type": "string"
+                },
+                "categoryMatch": {
+                    "description": "CategoryMatch records why the transaction has its category. It is set\nby the server and ignored on input.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CategoryMatch"
+                        }
+                    ]
+                },
+                "converted": {
+                    "$ref": "#/definitions/models.ConvertedAmount"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "insertedAt": {
+                    "type": "string"
+                },
+                "merchant": {
+                    "description": "Merchant is the clean merchant name derived from Description. It is\nset by the server and ignored on input.",
+                    "type": "string"
+                },
+                "remittance": {
+                    "description": "Remittance is the payment's remittance information, such as an\ninvoice number, for statements that keep it apart from the\ncounterparty's name in Description.",
+                    "type": "string"
+                },
+                "splits": {
+                    "description": "Splits divides the transaction across categories. The lines' amounts\nsum to Amount, and reports use them in place of Category. They are\nmanaged through the split endpoints and ignored on input elsewhere.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Split"
+                    }
+                },
+                "transactionDateTime": {
+                    "type": "string"
+                },
+                "transferId": {
+                    "description": "TransferID links the two sides of a transfer between the user's own\naccounts. Linked transactions are left out of spending and income.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "valueDate": {
+                    "description": "ValueDate is when the bank counted the money as moved, for statements\nthat give it apart from the booking date in TransactionDateTime.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CurrencyTotal": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Envelope": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "type": "integer"
+                },
+                "assigned": {
+                    "type": "integer"
+                },
+                "available": {
+                    "type": "integer"
+                },
+                "carriedOver": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EnvelopeMonth": {
+            "type": "object",
+            "properties": {
+                "assigned": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "envelopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Envelope"
+                    }
+                },
+                "income": {
+                    "description": "Income is the month's Credit transactions, excluding transfers.",
+                    "type": "integer"
+                },
+                "month": {
+                    "type": "string"
+                },
+                "readyToAssign": {
+                    "description": "ReadyToAssign is income not yet given to an envelope, including any\nleft over from earlier months. Negative when more has been assigned\nthan has come in.",
+                    "type": "integer"
+                },
+                "unconverted": {
+                    "description": "Unconverted holds amounts that could not be converted for lack of a\nrate and are excluded from the figures above.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotal"
+                    }
+                }
+            }
+        },
+        "models.ImportColumns": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "balance": {
+                    "type": "string"
+                },
+                "credit": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "debit": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportProfile": {
+            "type": "object",
+            "properties": {
+                "builtIn": {
+                    "type": "boolean"
+                },
+                "columns": {
+                    "$ref": "#/definitions/models.ImportColumns"
+                },
+                "dateFormat": {
+                    "description": "DateFormat is how dates are written, using DD, D, MM, M, MMM, YYYY and\nYY, such as \"DD/MM/YYYY\" or \"DD MMM YYYY\".",
+                    "type": "string"
+                },
+                "header": {
+                    "description": "Header names the columns of exports that have no header row. Such\nfiles are recognised by their column count and date format.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "identifiers": {
+                    "description": "Identifiers are further header names that must be present for the\nprofile to be detected, to tell it apart from profiles with similar\ncolumns.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "invertSign": {
+                    "description": "InvertSign is set for exports that show spending as positive amounts,\nsuch as credit card statements.",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "Name identifies the profile. It is stored lowercase.",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportResult": {
+            "type": "object",
+            "properties": {
+                "skipped": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                }
+            }
+        },
+        "models.MerchantAlias": {
+            "type": "object",
+            "properties": {
+                "merchant": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MerchantAliasResult": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "$ref": "#/definitions/models.MerchantAlias"
+                },
+                "transactionsUpdated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MerchantReport": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "merchants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MerchantSummary"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "unconverted": {
+                    "description": "Unconverted holds totals that could not be converted for lack of a rate\nand are excluded from the base currency figures.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotal"
+                    }
+                }
+            }
+        },
+        "models.MerchantSummary": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "merchant": {
+                    "type": "string"
+                },
+                "original": {
+                    "description": "Original holds the unconverted totals per transaction currency.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotal"
+                    }
+                },
+                "total": {
+                    "description": "Total is in the report's base currency.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PackCategory": {
+            "type": "object",
+            "properties": {
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "subcategories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackCategory"
+                    }
+                }
+            }
+        },
+        "models.RecategoriseResult": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryChange"
+                    }
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "examined": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReconciliationEntry": {
+            "type": "object",
+            "properties": {
+                "checkpointId": {
+                    "type": "string"
+                },
+                "computedBalance": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "difference": {
+                    "description": "Difference is StatedBalance minus ComputedBalance.",
+                    "type": "integer"
+                },
+                "flagged": {
+                    "type": "boolean"
+                },
+                "gap": {
+                    "description": "Gap is the part of Difference that appeared since the previous\ncheckpoint, i.e. the total of transactions missing from that period.",
+                    "type": "integer"
+                },
+                "periodStart": {
+                    "description": "PeriodStart is the date of the previous checkpoint, nil for the first.",
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "statedBalance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReconciliationReport": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "string"
+                },
+                "checkpoints": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReconciliationEntry"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "excluded": {
+                    "type": "integer"
+                },
+                "openingBalance": {
+                    "type": "integer"
+                },
+                "reconciled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.RuleConditions": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "string"
+                },
+                "dateFrom": {
+                    "description": "DateFrom and DateTo bound the transaction date, inclusive of both days.",
+                    "type": "string"
+                },
+                "dateTo": {
+                    "type": "string"
+                },
+                "descriptionContains": {
+                    "type": "string"
+                },
+                "descriptionExact": {
+                    "type": "string"
+                },
+                "descriptionRegex": {
+                    "type": "string"
+                },
+                "maxAmount": {
+                    "type": "integer"
+                },
+                "minAmount": {
+                    "description": "MinAmount and MaxAmount bound the size of the amount in minor units,\ninclusive, whichever direction the money moved. Use Type to tell\nspending from income.",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Type is \"Debit\" or \"Credit\".",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Split": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TextSpan": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Transaction": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "bankReference": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "description": "CategoryID identifies the category; Category holds its name.",
+                    "type": "string"
+                },
+                "categoryMatch": {
+                    "description": "CategoryMatch records why the transaction has its category. It is set\nby the server and ignored on input.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CategoryMatch"
+                        }
+                    ]
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "insertedAt": {
+                    "type": "string"
+                },
+                "merchant": {
+                    "description": "Merchant is the clean merchant name derived from Description. It is\nset by the server and ignored on input.",
+                    "type": "string"
+                },
+                "remittance": {
+                    "description": "Remittance is the payment's remittance information, such as an\ninvoice number, for statements that keep it apart from the\ncounterparty's name in Description.",
+                    "type": "string"
+                },
+                "splits": {
+                    "description": "Splits divides the transaction across categories. The lines' amounts\nsum to Amount, and reports use them in place of Category. They are\nmanaged through the split endpoints and ignored on input elsewhere.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Split"
+                    }
+                },
+                "transactionDateTime": {
+                    "type": "string"
+                },
+                "transferId": {
+                    "description": "TransferID links the two sides of a transfer between the user's own\naccounts. Linked transactions are left out of spending and income.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "valueDate": {
+                    "description": "ValueDate is when the bank counted the money as moved, for statements\nthat give it apart from the booking date in TransactionDateTime.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransactionSummary": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategorySummary"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "income": {
+                    "type": "integer"
+                },
+                "net": {
+                    "type": "integer"
+                },
+                "rollups": {
+                    "description": "Rollups totals each category together with its subcategories, so\nGroceries and Dining both count towards Food. It includes every\ncategory in Categories and every parent of one.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategorySummary"
+                    }
+                },
+                "spending": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "unconverted": {
+                    "description": "Unconverted holds totals that could not be converted for lack of a rate\nand are excluded from the base currency figures.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotal"
+                    }
+                }
+            }
+        },
+        "models.TransactionUpdate": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "categoryId": {
+                    "description": "CategoryID, when given, takes precedence over Category.",
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
                 "type": {
                     "type": "string"
                 },
@@ -818,9 +5134,49 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TransferPair": {
+            "type": "object",
+            "properties": {
+                "credit": {
+                    "$ref": "#/definitions/models.Transaction"
+                },
+                "debit": {
+                    "$ref": "#/definitions/models.Transaction"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "firstName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UserCategory": {
             "type": "object",
             "properties": {
+                "id": {
+                    "type": "string"
+                },
                 "keywords": {
                     "type": "array",
                     "items": {
@@ -829,6 +5185,23 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserUpdate": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "type": "string"
+                },
+                "firstName": {
+                    "type": "string"
+                },
+                "lastName": {
+                    "type": "string"
                 }
             }
         }
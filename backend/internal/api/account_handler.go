@@ -0,0 +1,255 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/exceptions"
+	"backend/internal/models"
+)
+
+// ListAccountsHandler godoc
+// @Summary List accounts
+// @Description Get all accounts for the authenticated user
+// @Tags accounts
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Success 200 {array} models.Account
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to list accounts"
+// @Router /accounts [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListAccountsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+
+	accounts, err := deps.Repo.ListAccounts(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing accounts: %v", err)
+		http.Error(w, exceptions.FailedToListAccountsMessage, http.StatusInternalServerError)
+		return
+	}
+	if accounts == nil {
+		accounts = []models.Account{}
+	}
+
+	EncodeJSONResponse(w, accounts)
+}
+
+// AddAccountHandler godoc
+// @Summary Add an account
+// @Description Add a bank account or card for the authenticated user. The currency defaults to the user's base currency.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param account body models.Account true "Account to add"
+// @Success 200 {object} models.Account
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to create account"
+// @Router /accounts [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) AddAccountHandler(w http.ResponseWriter, r *http.Request) {
+	var account models.Account
+	if err := json.NewDecoder(r.Body).Decode(&account); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	baseCurrency, err := deps.baseCurrency(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting base currency: %v", err)
+		http.Error(w, exceptions.FailedToCreateAccountMessage, http.StatusInternalServerError)
+		return
+	}
+	account.Name = strings.TrimSpace(account.Name)
+	account.Institution = strings.TrimSpace(account.Institution)
+	if err := validateAccount(account.Name, account.Type); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	currency, err := normaliseCurrency(account.Currency, baseCurrency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidCurrencyMessage, err), http.StatusBadRequest)
+		return
+	}
+	account.Currency = currency
+	account.CreatedAt = time.Now()
+	account.UpdatedAt = time.Now()
+
+	accountID, err := deps.Repo.AddAccount(r.Context(), userID, account)
+	if err != nil {
+		log.Printf("Error adding account: %v", err)
+		http.Error(w, exceptions.FailedToCreateAccountMessage, http.StatusInternalServerError)
+		return
+	}
+
+	account.ID = accountID
+	EncodeJSONResponse(w, account)
+}
+
+// GetAccountHandler godoc
+// @Summary Get account by ID
+// @Description Get an account by its ID for the authenticated user
+// @Tags accounts
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Account ID"
+// @Success 200 {object} models.Account
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Account not found"
+// @Router /accounts/{id} [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) GetAccountHandler(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	account, err := deps.Repo.GetAccount(r.Context(), userID, accountID)
+	if err != nil {
+		var notFoundErr *exceptions.AccountNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			log.Printf("Error getting account: %v", err)
+		}
+		http.Error(w, exceptions.AccountNotFoundMessage, http.StatusNotFound)
+		return
+	}
+
+	EncodeJSONResponse(w, account)
+}
+
+// UpdateAccountHandler godoc
+// @Summary Update an account
+// @Description Update an existing account for the authenticated user
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Account ID"
+// @Param account body models.AccountUpdate true "Account update data"
+// @Success 200 {object} models.Account
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Account not found"
+// @Failure 500 {string} string "Failed to update account"
+// @Router /accounts/{id} [patch]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) UpdateAccountHandler(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	var updateData models.AccountUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	if updateData.Name != nil {
+		name := strings.TrimSpace(*updateData.Name)
+		updateData.Name = &name
+		if name == "" {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, "name is required"), http.StatusBadRequest)
+			return
+		}
+	}
+	if updateData.Type != nil && !slices.Contains(models.AccountTypes, *updateData.Type) {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, invalidAccountTypeError(*updateData.Type)), http.StatusBadRequest)
+		return
+	}
+	if updateData.Currency != nil {
+		currency, err := normaliseCurrency(*updateData.Currency, "")
+		if err != nil || currency == "" {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidCurrencyMessage, *updateData.Currency), http.StatusBadRequest)
+			return
+		}
+		updateData.Currency = &currency
+	}
+
+	account, err := deps.Repo.UpdateAccount(r.Context(), userID, accountID, updateData)
+	if err != nil {
+		var notFoundErr *exceptions.AccountNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.AccountNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error updating account: %v", err)
+		http.Error(w, exceptions.FailedToUpdateAccountMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, account)
+}
+
+// DeleteAccountHandler godoc
+// @Summary Delete an account
+// @Description Delete an account for the authenticated user. Accounts that still have transactions cannot be deleted.
+// @Tags accounts
+// @Param user-id header string true "User ID"
+// @Param id path string true "Account ID"
+// @Success 204 {string} string "No Content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Account not found"
+// @Failure 409 {string} string "Account still has transactions"
+// @Failure 500 {string} string "Failed to delete account"
+// @Router /accounts/{id} [delete]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, map[string]string{"accountId": accountID})
+	if err != nil {
+		log.Printf("Error listing account transactions: %v", err)
+		http.Error(w, exceptions.FailedToDeleteAccountMessage, http.StatusInternalServerError)
+		return
+	}
+	if len(transactions) > 0 {
+		http.Error(w, exceptions.AccountHasTransactionsMessage, http.StatusConflict)
+		return
+	}
+
+	if err := deps.Repo.DeleteAccount(r.Context(), userID, accountID); err != nil {
+		var notFoundErr *exceptions.AccountNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.AccountNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting account: %v", err)
+		http.Error(w, exceptions.FailedToDeleteAccountMessage, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resolveAccount loads the account a transaction is being assigned to. An
+// empty accountID leaves the transaction unassigned and returns nil.
+func (deps *RouterDeps) resolveAccount(ctx context.Context, userID, accountID string) (*models.Account, error) {
+	if accountID == "" {
+		return nil, nil
+	}
+	return deps.Repo.GetAccount(ctx, userID, accountID)
+}
+
+func validateAccount(name, accountType string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if !slices.Contains(models.AccountTypes, accountType) {
+		return invalidAccountTypeError(accountType)
+	}
+	return nil
+}
+
+func invalidAccountTypeError(accountType string) error {
+	return fmt.Errorf("type %q must be one of %s", accountType, strings.Join(models.AccountTypes, ", "))
+}
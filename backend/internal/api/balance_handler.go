@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"backend/internal/reports"
+)
+
+// BalanceCheckpointRequest records the balance a statement shows at the end
+// of Date (YYYY-MM-DD), in minor units of the account's currency.
+type BalanceCheckpointRequest struct {
+	Date    string `json:"date"`
+	Balance int64  `json:"balance"`
+}
+
+// AccountBalancesHandler godoc
+// @Summary Running balances for an account
+// @Description Compute the balance after each transaction in an account, starting from its opening balance
+// @Tags accounts
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Account ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Success 200 {object} models.AccountBalances
+// @Failure 400 {string} string "Invalid date range"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Account not found"
+// @Failure 500 {string} string "Failed to compute balances"
+// @Router /accounts/{id}/balances [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) AccountBalancesHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	account, transactions, ok := deps.loadAccountTransactions(w, r)
+	if !ok {
+		return
+	}
+
+	EncodeJSONResponse(w, reports.RunningBalances(*account, transactions, from, to))
+}
+
+// ReconciliationHandler godoc
+// @Summary Reconcile an account
+// @Description Compare the account's balance checkpoints with the balances computed from its transactions and flag the periods where they drift apart
+// @Tags accounts
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Account ID"
+// @Success 200 {object} models.ReconciliationReport
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Account not found"
+// @Failure 500 {string} string "Failed to compute balances"
+// @Router /accounts/{id}/reconciliation [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ReconciliationHandler(w http.ResponseWriter, r *http.Request) {
+	account, transactions, ok := deps.loadAccountTransactions(w, r)
+	if !ok {
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	checkpoints, err := deps.Repo.ListBalanceCheckpoints(r.Context(), userID, account.ID)
+	if err != nil {
+		log.Printf("Error listing balance checkpoints: %v", err)
+		http.Error(w, exceptions.FailedToComputeBalancesMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, reports.Reconcile(*account, transactions, checkpoints))
+}
+
+// ListBalanceCheckpointsHandler godoc
+// @Summary List balance checkpoints
+// @Description List the stated balances recorded for an account, oldest first
+// @Tags accounts
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Account ID"
+// @Success 200 {array} models.BalanceCheckpoint
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Account not found"
+// @Failure 500 {string} string "Failed to list balance checkpoints"
+// @Router /accounts/{id}/checkpoints [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListBalanceCheckpointsHandler(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	if _, err := deps.Repo.GetAccount(r.Context(), userID, accountID); err != nil {
+		writeAccountError(w, err, exceptions.FailedToComputeBalancesMessage)
+		return
+	}
+	checkpoints, err := deps.Repo.ListBalanceCheckpoints(r.Context(), userID, accountID)
+	if err != nil {
+		log.Printf("Error listing balance checkpoints: %v", err)
+		http.Error(w, "Failed to list balance checkpoints", http.StatusInternalServerError)
+		return
+	}
+	if checkpoints == nil {
+		checkpoints = []models.BalanceCheckpoint{}
+	}
+
+	EncodeJSONResponse(w, checkpoints)
+}
+
+// AddBalanceCheckpointHandler godoc
+// @Summary Record a statement balance
+// @Description Record the balance a statement shows at the end of a date
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Account ID"
+// @Param checkpoint body BalanceCheckpointRequest true "Stated balance"
+// @Success 200 {object} models.BalanceCheckpoint
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Account not found"
+// @Failure 500 {string} string "Failed to add balance checkpoint"
+// @Router /accounts/{id}/checkpoints [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) AddBalanceCheckpointHandler(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	var request BalanceCheckpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	date, err := time.Parse(dateParamLayout, request.Date)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", request.Date)), http.StatusBadRequest)
+		return
+	}
+
+	checkpoint := models.BalanceCheckpoint{
+		AccountID: accountID,
+		Date:      date,
+		Balance:   request.Balance,
+		Source:    models.BalanceSourceManual,
+		CreatedAt: time.Now(),
+	}
+	checkpointID, err := deps.Repo.AddBalanceCheckpoint(r.Context(), userID, accountID, checkpoint)
+	if err != nil {
+		writeAccountError(w, err, "Failed to add balance checkpoint")
+		return
+	}
+
+	checkpoint.ID = checkpointID
+	EncodeJSONResponse(w, checkpoint)
+}
+
+// DeleteBalanceCheckpointHandler godoc
+// @Summary Delete a balance checkpoint
+// @Description Delete a recorded statement balance
+// @Tags accounts
+// @Param user-id header string true "User ID"
+// @Param id path string true "Account ID"
+// @Param checkpointId path string true "Checkpoint ID"
+// @Success 204 {string} string "No Content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Balance checkpoint not found"
+// @Failure 500 {string} string "Failed to delete balance checkpoint"
+// @Router /accounts/{id}/checkpoints/{checkpointId} [delete]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) DeleteBalanceCheckpointHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Context().Value(userIDKey).(string)
+
+	if err := deps.Repo.DeleteBalanceCheckpoint(r.Context(), userID, vars["id"], vars["checkpointId"]); err != nil {
+		var notFoundErr *exceptions.BalanceCheckpointNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.BalanceCheckpointNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting balance checkpoint: %v", err)
+		http.Error(w, "Failed to delete balance checkpoint", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadAccountTransactions fetches the account named in the path and all of
+// its transactions, writing the error response itself when either fails.
+func (deps *RouterDeps) loadAccountTransactions(w http.ResponseWriter, r *http.Request) (*models.Account, []models.Transaction, bool) {
+	accountID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	account, err := deps.Repo.GetAccount(r.Context(), userID, accountID)
+	if err != nil {
+		writeAccountError(w, err, exceptions.FailedToComputeBalancesMessage)
+		return nil, nil, false
+	}
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, map[string]string{"accountId": accountID})
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToComputeBalancesMessage, http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	return account, transactions, true
+}
+
+// addStatementCheckpoints records balances read from an imported statement,
+// skipping dates that already have a checkpoint so re-importing a file does
+// not duplicate them.
+func (deps *RouterDeps) addStatementCheckpoints(ctx context.Context, userID, accountID string, checkpoints []models.BalanceCheckpoint) error {
+	if len(checkpoints) == 0 {
+		return nil
+	}
+	existing, err := deps.Repo.ListBalanceCheckpoints(ctx, userID, accountID)
+	if err != nil {
+		return err
+	}
+	recorded := make(map[string]bool, len(existing))
+	for _, checkpoint := range existing {
+		recorded[checkpoint.Date.UTC().Format(dateParamLayout)] = true
+	}
+	for _, checkpoint := range checkpoints {
+		day := checkpoint.Date.UTC().Format(dateParamLayout)
+		if recorded[day] {
+			continue
+		}
+		if _, err := deps.Repo.AddBalanceCheckpoint(ctx, userID, accountID, checkpoint); err != nil {
+			return err
+		}
+		recorded[day] = true
+	}
+	return nil
+}
+
+func writeAccountError(w http.ResponseWriter, err error, failedMessage string) {
+	var notFoundErr *exceptions.AccountNotFoundError
+	if errors.As(err, &notFoundErr) {
+		http.Error(w, exceptions.AccountNotFoundMessage, http.StatusNotFound)
+		return
+	}
+	log.Printf("Error getting account: %v", err)
+	http.Error(w, failedMessage, http.StatusInternalServerError)
+}
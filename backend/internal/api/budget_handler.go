@@ -0,0 +1,371 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/categories"
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"backend/internal/reports"
+)
+
+// ListBudgetsHandler godoc
+// @Summary List budgets
+// @Description Get all budgets for the authenticated user
+// @Tags budgets
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Success 200 {array} models.Budget
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to list budgets"
+// @Router /budgets [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListBudgetsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+
+	budgets, err := deps.Repo.ListBudgets(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing budgets: %v", err)
+		http.Error(w, exceptions.FailedToListBudgetsMessage, http.StatusInternalServerError)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToListBudgetsMessage, http.StatusInternalServerError)
+		return
+	}
+	for i := range budgets {
+		budgets[i].CategoryID, budgets[i].Category = tree.Resolve(budgets[i].CategoryID, budgets[i].Category)
+	}
+	if budgets == nil {
+		budgets = []models.Budget{}
+	}
+
+	EncodeJSONResponse(w, budgets)
+}
+
+// AddBudgetHandler godoc
+// @Summary Add a budget
+// @Description Add a spending limit for one of the authenticated user's categories, given by categoryId or category. The period defaults to monthly, the currency to the user's base currency and the start date to the current period.
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param budget body models.Budget true "Budget to add"
+// @Success 200 {object} models.Budget
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to create budget"
+// @Router /budgets [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) AddBudgetHandler(w http.ResponseWriter, r *http.Request) {
+	var budget models.Budget
+	if err := json.NewDecoder(r.Body).Decode(&budget); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	baseCurrency, err := deps.baseCurrency(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting base currency: %v", err)
+		http.Error(w, exceptions.FailedToCreateBudgetMessage, http.StatusInternalServerError)
+		return
+	}
+	if budget.Period == "" {
+		budget.Period = models.BudgetPeriodMonthly
+	}
+	currency, err := normaliseCurrency(budget.Currency, baseCurrency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidCurrencyMessage, err), http.StatusBadRequest)
+		return
+	}
+	budget.Currency = currency
+	if budget.StartDate.IsZero() {
+		budget.StartDate = time.Now()
+	}
+	budget.StartDate, _ = reports.PeriodBounds(budget.Period, budget.StartDate)
+
+	if err := validateBudget(budget.Period, budget.Amount); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	category, err := deps.resolveCategory(r.Context(), userID, budget.CategoryID, budget.Category)
+	if err != nil {
+		if errors.Is(err, categories.ErrUnknownCategory) {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToCreateBudgetMessage, http.StatusInternalServerError)
+		return
+	}
+	budget.CategoryID, budget.Category = category.ID, category.Name
+
+	budget.CreatedAt = time.Now()
+	budget.UpdatedAt = time.Now()
+	budgetID, err := deps.Repo.AddBudget(r.Context(), userID, budget)
+	if err != nil {
+		log.Printf("Error adding budget: %v", err)
+		http.Error(w, exceptions.FailedToCreateBudgetMessage, http.StatusInternalServerError)
+		return
+	}
+
+	budget.ID = budgetID
+	EncodeJSONResponse(w, budget)
+}
+
+// GetBudgetHandler godoc
+// @Summary Get budget by ID
+// @Description Get a budget by its ID for the authenticated user
+// @Tags budgets
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Budget ID"
+// @Success 200 {object} models.Budget
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Budget not found"
+// @Failure 500 {string} string "Failed to get budget"
+// @Router /budgets/{id} [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) GetBudgetHandler(w http.ResponseWriter, r *http.Request) {
+	budgetID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	budget, err := deps.Repo.GetBudget(r.Context(), userID, budgetID)
+	if err != nil {
+		writeBudgetError(w, err, exceptions.BudgetNotFoundMessage)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToGetBudgetMessage, http.StatusInternalServerError)
+		return
+	}
+	budget.CategoryID, budget.Category = tree.Resolve(budget.CategoryID, budget.Category)
+
+	EncodeJSONResponse(w, budget)
+}
+
+// UpdateBudgetHandler godoc
+// @Summary Update a budget
+// @Description Update an existing budget for the authenticated user. Set the category by categoryId or by name.
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Budget ID"
+// @Param budget body models.BudgetUpdate true "Budget update data"
+// @Success 200 {object} models.Budget
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Budget not found"
+// @Failure 500 {string} string "Failed to update budget"
+// @Router /budgets/{id} [patch]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) UpdateBudgetHandler(w http.ResponseWriter, r *http.Request) {
+	budgetID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	var updateData models.BudgetUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+
+	budget, err := deps.Repo.GetBudget(r.Context(), userID, budgetID)
+	if err != nil {
+		writeBudgetError(w, err, exceptions.FailedToUpdateBudgetMessage)
+		return
+	}
+	period, amount := budget.Period, budget.Amount
+	if updateData.Period != nil {
+		period = *updateData.Period
+	}
+	if updateData.Amount != nil {
+		amount = *updateData.Amount
+	}
+	if err := validateBudget(period, amount); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	if updateData.Currency != nil {
+		currency, err := normaliseCurrency(*updateData.Currency, "")
+		if err != nil || currency == "" {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidCurrencyMessage, *updateData.Currency), http.StatusBadRequest)
+			return
+		}
+		updateData.Currency = &currency
+	}
+	if updateData.StartDate != nil || updateData.Period != nil {
+		startDate := budget.StartDate
+		if updateData.StartDate != nil {
+			startDate = *updateData.StartDate
+		}
+		startDate, _ = reports.PeriodBounds(period, startDate)
+		updateData.StartDate = &startDate
+	}
+	if updateData.Category != nil || updateData.CategoryID != nil {
+		// A category ID takes precedence over a name.
+		var id, name string
+		if updateData.CategoryID != nil {
+			id = *updateData.CategoryID
+		} else {
+			name = *updateData.Category
+		}
+		category, err := deps.resolveCategory(r.Context(), userID, id, name)
+		if err != nil {
+			if errors.Is(err, categories.ErrUnknownCategory) {
+				http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error listing categories: %v", err)
+			http.Error(w, exceptions.FailedToUpdateBudgetMessage, http.StatusInternalServerError)
+			return
+		}
+		updateData.Category, updateData.CategoryID = &category.Name, &category.ID
+	}
+
+	updated, err := deps.Repo.UpdateBudget(r.Context(), userID, budgetID, updateData)
+	if err != nil {
+		writeBudgetError(w, err, exceptions.FailedToUpdateBudgetMessage)
+		return
+	}
+
+	EncodeJSONResponse(w, updated)
+}
+
+// DeleteBudgetHandler godoc
+// @Summary Delete a budget
+// @Description Delete a budget for the authenticated user
+// @Tags budgets
+// @Param user-id header string true "User ID"
+// @Param id path string true "Budget ID"
+// @Success 204 {string} string "No Content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Budget not found"
+// @Failure 500 {string} string "Failed to delete budget"
+// @Router /budgets/{id} [delete]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) DeleteBudgetHandler(w http.ResponseWriter, r *http.Request) {
+	budgetID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	if err := deps.Repo.DeleteBudget(r.Context(), userID, budgetID); err != nil {
+		writeBudgetError(w, err, exceptions.FailedToDeleteBudgetMessage)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BudgetProgressHandler godoc
+// @Summary Budget progress
+// @Description Compute spending, what remains and the projected spending at the end of the budget period containing the given date
+// @Tags budgets
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Budget ID"
+// @Param date query string false "Date within the period to report on (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.BudgetProgress
+// @Failure 400 {string} string "Invalid date"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Budget not found"
+// @Failure 500 {string} string "Failed to compute budget progress"
+// @Router /budgets/{id}/progress [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) BudgetProgressHandler(w http.ResponseWriter, r *http.Request) {
+	on := time.Now()
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := time.Parse(dateParamLayout, value)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", value), http.StatusBadRequest)
+			return
+		}
+		on = parsed
+	}
+
+	budgetID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	budget, err := deps.Repo.GetBudget(r.Context(), userID, budgetID)
+	if err != nil {
+		writeBudgetError(w, err, exceptions.FailedToComputeBudgetMessage)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToComputeBudgetMessage, http.StatusInternalServerError)
+		return
+	}
+	budget.CategoryID, budget.Category = tree.Resolve(budget.CategoryID, budget.Category)
+	// Split lines can be in the budget's category when their transaction
+	// is not, so this can't filter by category.
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, nil)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToComputeBudgetMessage, http.StatusInternalServerError)
+		return
+	}
+
+	progress, err := reports.BudgetProgress(r.Context(), deps.Rates, *budget, transactions, on)
+	if err != nil {
+		log.Printf("Error computing budget progress: %v", err)
+		http.Error(w, exceptions.FailedToComputeBudgetMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, progress)
+}
+
+// resolveCategory looks a category up among the user's by ID or, when id is
+// empty, by name, ignoring case as the category tree does. What is stored
+// then carries the category's ID and its own spelling of the name.
+func (deps *RouterDeps) resolveCategory(ctx context.Context, userID, id, name string) (models.UserCategory, error) {
+	tree, err := deps.categoryTree(ctx, userID)
+	if err != nil {
+		return models.UserCategory{}, err
+	}
+	category, ok := tree.Lookup(id, name)
+	if !ok {
+		if id != "" {
+			name = id
+		}
+		return models.UserCategory{}, fmt.Errorf("%w %q", categories.ErrUnknownCategory, name)
+	}
+	return category, nil
+}
+
+func validateBudget(period string, amount int64) error {
+	if !slices.Contains(models.BudgetPeriods, period) {
+		return fmt.Errorf("period %q must be one of %s", period, strings.Join(models.BudgetPeriods, ", "))
+	}
+	if amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+	return nil
+}
+
+func writeBudgetError(w http.ResponseWriter, err error, failedMessage string) {
+	var notFoundErr *exceptions.BudgetNotFoundError
+	if errors.As(err, &notFoundErr) {
+		http.Error(w, exceptions.BudgetNotFoundMessage, http.StatusNotFound)
+		return
+	}
+	log.Printf("Error accessing budget: %v", err)
+	http.Error(w, failedMessage, http.StatusInternalServerError)
+}
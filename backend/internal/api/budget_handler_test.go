@@ -0,0 +1,57 @@
+package api
+
+import (
+	"backend/internal/models"
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestBudgetCategoryIgnoresCase(t *testing.T) {
+	deps, repo := newTestDeps(t)
+	addCategory(t, repo, "Groceries")
+	addCategory(t, repo, "Eating out")
+
+	var budget models.Budget
+	decode(t, serveJSON(t, deps.AddBudgetHandler, http.MethodPost, "/budgets", models.Budget{Category: "groceries", Amount: 30000}, nil), &budget)
+	if budget.Category != "Groceries" {
+		t.Errorf("Category = %q, want the category's own spelling", budget.Category)
+	}
+
+	category := "EATING OUT"
+	var updated models.Budget
+	vars := map[string]string{"id": budget.ID}
+	decode(t, serveJSON(t, deps.UpdateBudgetHandler, http.MethodPatch, "/budgets/"+budget.ID, models.BudgetUpdate{Category: &category}, vars), &updated)
+	if updated.Category != "Eating out" {
+		t.Errorf("updated Category = %q, want the category's own spelling", updated.Category)
+	}
+
+	if w := serveJSON(t, deps.AddBudgetHandler, http.MethodPost, "/budgets", models.Budget{Category: "Pets", Amount: 1000}, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("budget for an unknown category = %d, want 400", w.Code)
+	}
+}
+
+func TestBudgetCategoryByID(t *testing.T) {
+	deps, repo := newTestDeps(t)
+	groceries := addCategory(t, repo, "Groceries")
+
+	var budget models.Budget
+	decode(t, serveJSON(t, deps.AddBudgetHandler, http.MethodPost, "/budgets", models.Budget{CategoryID: groceries.ID, Amount: 30000}, nil), &budget)
+	if budget.Category != "Groceries" || budget.CategoryID != groceries.ID {
+		t.Fatalf("budget = %+v, want the category's name and ID", budget)
+	}
+
+	// A budget stored before the rename is reported under the new name.
+	if _, err := repo.UpdateUserCategory(context.Background(), testUserID, groceries.ID, models.UserCategory{Name: "Food shopping"}); err != nil {
+		t.Fatal(err)
+	}
+	var got models.Budget
+	decode(t, serve(deps.GetBudgetHandler, http.MethodGet, "/budgets/"+budget.ID, nil, map[string]string{"id": budget.ID}), &got)
+	if got.Category != "Food shopping" || got.CategoryID != groceries.ID {
+		t.Errorf("budget after rename = %+v, want Food shopping", got)
+	}
+
+	if w := serveJSON(t, deps.AddBudgetHandler, http.MethodPost, "/budgets", models.Budget{CategoryID: "missing", Amount: 1000}, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("budget for an unknown category ID = %d, want 400", w.Code)
+	}
+}
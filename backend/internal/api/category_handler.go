@@ -1,8 +1,14 @@
 package api
 
 import (
+	"backend/internal/categories"
+	"backend/internal/exceptions"
 	"backend/internal/models"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -10,7 +16,7 @@ import (
 
 // ListCategoriesHandler godoc
 // @Summary List user categories
-// @Description Get all categories for the authenticated user
+// @Description Get all categories for the authenticated user. Subcategories name their parent in parentId.
 // @Tags categories
 // @Produce json
 // @Param user-id header string true "User ID"
@@ -33,7 +39,7 @@ func (deps *RouterDeps) ListCategoriesHandler(w http.ResponseWriter, r *http.Req
 
 // AddCategoryHandler godoc
 // @Summary Add a new category
-// @Description Add a new category for the authenticated user
+// @Description Add a new category for the authenticated user. Names must be unique; set parentId to make it a subcategory.
 // @Tags categories
 // @Accept json
 // @Produce json
@@ -54,6 +60,11 @@ func (deps *RouterDeps) AddCategoryHandler(w http.ResponseWriter, r *http.Reques
 
 	userID := r.Context().Value(userIDKey).(string)
 
+	category.ID = ""
+	if !deps.checkCategory(r.Context(), w, userID, category, "Failed to add category") {
+		return
+	}
+
 	categoryID, err := deps.Repo.AddUserCategory(r.Context(), userID, category)
 	if err != nil {
 		http.Error(w, "Failed to add category", http.StatusInternalServerError)
@@ -66,16 +77,17 @@ func (deps *RouterDeps) AddCategoryHandler(w http.ResponseWriter, r *http.Reques
 
 // UpdateCategoryHandler godoc
 // @Summary Update a category
-// @Description Update an existing category for the authenticated user
+// @Description Replace an existing category for the authenticated user, including its parent. Renaming a category renames it on all of its transactions.
 // @Tags categories
 // @Accept json
 // @Produce json
 // @Param user-id header string true "User ID"
 // @Param id path string true "Category ID"
 // @Param category body models.UserCategory true "Updated category"
-// @Success 204 {string} string "No Content"
+// @Success 200 {object} models.CategoryChangeResult
 // @Failure 400 {string} string "Missing category ID or invalid request body"
 // @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Category not found"
 // @Failure 500 {string} string "Failed to update category"
 // @Router /categories/{id} [patch]
 // @Security ApiKeyAuth
@@ -95,24 +107,39 @@ func (deps *RouterDeps) UpdateCategoryHandler(w http.ResponseWriter, r *http.Req
 
 	userID := r.Context().Value(userIDKey).(string)
 
-	if err := deps.Repo.UpdateUserCategory(r.Context(), userID, categoryID, category); err != nil {
+	category.ID = categoryID
+	if !deps.checkCategory(r.Context(), w, userID, category, "Failed to update category") {
+		return
+	}
+
+	updated, err := deps.Repo.UpdateUserCategory(r.Context(), userID, categoryID, category)
+	if err != nil {
+		var notFoundErr *exceptions.CategoryNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.CategoryNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error updating category: %v", err)
 		http.Error(w, "Failed to update category", http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	EncodeJSONResponse(w, models.CategoryChangeResult{TransactionsUpdated: updated})
 }
 
 // DeleteCategoryHandler godoc
 // @Summary Delete a category
-// @Description Delete a category for the authenticated user
+// @Description Delete a category for the authenticated user, moving its transactions to the category reassignTo. Categories with subcategories cannot be deleted.
 // @Tags categories
 // @Produce json
 // @Param user-id header string true "User ID"
 // @Param id path string true "Category ID"
-// @Success 204 {string} string "No Content"
-// @Failure 400 {string} string "Missing category ID"
+// @Param reassignTo query string true "ID of the category to move the transactions to"
+// @Success 200 {object} models.CategoryChangeResult
+// @Failure 400 {string} string "Missing category ID or invalid reassignTo"
 // @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Category not found"
+// @Failure 409 {string} string "Category still has subcategories"
 // @Failure 500 {string} string "Failed to delete category"
 // @Router /categories/{id} [delete]
 // @Security ApiKeyAuth
@@ -124,12 +151,89 @@ func (deps *RouterDeps) DeleteCategoryHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	reassignTo := r.URL.Query().Get("reassignTo")
+	if reassignTo == "" {
+		http.Error(w, "Missing reassignTo category ID", http.StatusBadRequest)
+		return
+	}
+
 	userID := r.Context().Value(userIDKey).(string)
 
-	if err := deps.Repo.DeleteUserCategory(r.Context(), userID, categoryID); err != nil {
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
 		http.Error(w, "Failed to delete category", http.StatusInternalServerError)
 		return
 	}
+	if _, ok := tree.ByID(categoryID); !ok {
+		http.Error(w, exceptions.CategoryNotFoundMessage, http.StatusNotFound)
+		return
+	}
+	if _, ok := tree.ByID(reassignTo); !ok || reassignTo == categoryID {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidCategoryMessage, "reassignTo must be another of your categories"), http.StatusBadRequest)
+		return
+	}
+	if len(tree.Children(categoryID)) > 0 {
+		http.Error(w, exceptions.CategoryHasSubcategoriesMessage, http.StatusConflict)
+		return
+	}
 
-	w.WriteHeader(http.StatusNoContent)
+	moved, err := deps.Repo.DeleteUserCategory(r.Context(), userID, categoryID, reassignTo)
+	if err != nil {
+		var notFoundErr *exceptions.CategoryNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.CategoryNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting category: %v", err)
+		http.Error(w, "Failed to delete category", http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, models.CategoryChangeResult{TransactionsUpdated: moved})
+}
+
+// categoryTree loads the user's categories for looking them up by name or ID.
+func (deps *RouterDeps) categoryTree(ctx context.Context, userID string) (*categories.Tree, error) {
+	userCategories, err := deps.Repo.ListUserCategories(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return categories.NewTree(userCategories), nil
+}
+
+// checkCategory writes an error response unless category can be saved
+// alongside the user's other categories.
+func (deps *RouterDeps) checkCategory(ctx context.Context, w http.ResponseWriter, userID string, category models.UserCategory, failedMessage string) bool {
+	tree, err := deps.categoryTree(ctx, userID)
+	if err != nil {
+		http.Error(w, failedMessage, http.StatusInternalServerError)
+		return false
+	}
+	if category.ID != "" {
+		if _, ok := tree.ByID(category.ID); !ok {
+			http.Error(w, exceptions.CategoryNotFoundMessage, http.StatusNotFound)
+			return false
+		}
+	}
+	if err := tree.Validate(category); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidCategoryMessage, err), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// resolveCategories fills in each transaction's category ID from its name,
+// or its name from its ID when one was given.
+func (deps *RouterDeps) resolveCategories(ctx context.Context, userID string, transactions []models.Transaction) error {
+	tree, err := deps.categoryTree(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for i := range transactions {
+		if err := tree.Assign(&transactions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"backend/internal/models"
+	"net/http"
+	"testing"
+)
+
+func TestDeleteCategoryHandler(t *testing.T) {
+	deps, repo := newTestDeps(t)
+	eatingOut := addCategory(t, repo, "Eating out")
+	food := addCategory(t, repo, "Food")
+	lunch := addTransaction(t, repo, models.Transaction{Description: "PRET", Amount: -450, Category: "Eating out", CategoryID: eatingOut.ID})
+	legacy := addTransaction(t, repo, models.Transaction{Description: "NANDOS", Amount: -1800, Category: "Eating out"})
+
+	vars := map[string]string{"id": eatingOut.ID}
+	if w := serve(deps.DeleteCategoryHandler, http.MethodDelete, "/categories/"+eatingOut.ID, nil, vars); w.Code != http.StatusBadRequest {
+		t.Errorf("delete without reassignTo = %d, want 400", w.Code)
+	}
+	if w := serve(deps.DeleteCategoryHandler, http.MethodDelete, "/categories/"+eatingOut.ID+"?reassignTo="+eatingOut.ID, nil, vars); w.Code != http.StatusBadRequest {
+		t.Errorf("delete reassigning to itself = %d, want 400", w.Code)
+	}
+	if w := serve(deps.DeleteCategoryHandler, http.MethodDelete, "/categories/missing?reassignTo="+food.ID, nil, map[string]string{"id": "missing"}); w.Code != http.StatusNotFound {
+		t.Errorf("delete of a missing category = %d, want 404", w.Code)
+	}
+
+	var result models.CategoryChangeResult
+	decode(t, serve(deps.DeleteCategoryHandler, http.MethodDelete, "/categories/"+eatingOut.ID+"?reassignTo="+food.ID, nil, vars), &result)
+	if result.TransactionsUpdated != 2 {
+		t.Errorf("TransactionsUpdated = %d, want 2", result.TransactionsUpdated)
+	}
+	for _, id := range []string{lunch.ID, legacy.ID} {
+		if moved := getTransaction(t, repo, id); moved.Category != "Food" || moved.CategoryID != food.ID {
+			t.Errorf("transaction %s = %+v, want it moved to Food", id, moved)
+		}
+	}
+	tree, err := deps.categoryTree(t.Context(), testUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tree.ByID(eatingOut.ID); ok {
+		t.Error("deleted category is still listed")
+	}
+}
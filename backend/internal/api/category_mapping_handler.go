@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/categoriser"
+	"backend/internal/exceptions"
+	"backend/internal/models"
+)
+
+// ListCategoryMappingsHandler godoc
+// @Summary List learned categories
+// @Description List the merchant to category mappings learned from the authenticated user's corrections
+// @Tags categories
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Success 200 {array} models.CategoryMapping
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to list category mappings"
+// @Router /categories/mappings [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListCategoryMappingsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+
+	mappings, err := deps.Repo.ListCategoryMappings(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing category mappings: %v", err)
+		http.Error(w, exceptions.FailedToListCategoryMappingsMessage, http.StatusInternalServerError)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToListCategoryMappingsMessage, http.StatusInternalServerError)
+		return
+	}
+	for i := range mappings {
+		mappings[i].CategoryID, mappings[i].Category = tree.Resolve(mappings[i].CategoryID, mappings[i].Category)
+	}
+	if mappings == nil {
+		mappings = []models.CategoryMapping{}
+	}
+
+	EncodeJSONResponse(w, mappings)
+}
+
+// DeleteCategoryMappingHandler godoc
+// @Summary Forget a learned category
+// @Description Stop giving a merchant the category learned from the authenticated user's corrections
+// @Tags categories
+// @Param user-id header string true "User ID"
+// @Param merchant path string true "Merchant key"
+// @Success 204 {string} string "No Content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Category mapping not found"
+// @Failure 500 {string} string "Failed to delete category mapping"
+// @Router /categories/mappings/{merchant} [delete]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) DeleteCategoryMappingHandler(w http.ResponseWriter, r *http.Request) {
+	merchant := mux.Vars(r)["merchant"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	if err := deps.Repo.DeleteCategoryMapping(r.Context(), userID, merchant); err != nil {
+		var notFoundErr *exceptions.CategoryMappingNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.CategoryMappingNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting category mapping: %v", err)
+		http.Error(w, exceptions.FailedToDeleteCategoryMappingMessage, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// learnCategory remembers the category the user gave transaction for its
+// merchant. The correction itself is already saved, so failing to learn from
+// it is only logged.
+func (deps *RouterDeps) learnCategory(ctx context.Context, userID string, transaction models.Transaction) {
+	merchant := categoriser.LearnedKey(transaction)
+	if merchant == "" || transaction.Category == "" {
+		return
+	}
+	mapping := models.CategoryMapping{Merchant: merchant, Category: transaction.Category, CategoryID: transaction.CategoryID, UpdatedAt: time.Now()}
+	if err := deps.Repo.SetCategoryMapping(ctx, userID, mapping); err != nil {
+		log.Printf("Error saving category mapping: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/categories"
+	"backend/internal/exceptions"
+)
+
+// ListCategoryPacksHandler godoc
+// @Summary List category packs
+// @Description List the category packs users can be seeded from, such as uk and us, with their categories and keywords
+// @Tags categories
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Success 200 {array} models.CategoryPack
+// @Failure 401 {string} string "Unauthorized"
+// @Router /categories/packs [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListCategoryPacksHandler(w http.ResponseWriter, r *http.Request) {
+	EncodeJSONResponse(w, deps.Packs.List())
+}
+
+// SeedCategoryPackHandler godoc
+// @Summary Merge a category pack
+// @Description Merge a category pack into the authenticated user's categories. Categories are matched by name, ignoring case: missing ones are added and existing ones gain any keywords from the pack they lack, but keep their place in the hierarchy. Seeding a pack again only restores what has since been removed.
+// @Tags categories
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param name path string true "Pack name"
+// @Success 200 {object} models.CategorySeedResult
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Unknown category pack"
+// @Failure 500 {string} string "Failed to seed categories"
+// @Router /categories/packs/{name}/seed [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) SeedCategoryPackHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	pack, ok := deps.Packs.Get(name)
+	if !ok {
+		http.Error(w, fmt.Sprintf(exceptions.UnknownCategoryPackMessage, name), http.StatusNotFound)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	result, err := categories.Seed(r.Context(), deps.Repo, userID, pack)
+	if err != nil {
+		log.Printf("Error seeding categories: %v", err)
+		http.Error(w, exceptions.FailedToSeedCategoriesMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, result)
+}
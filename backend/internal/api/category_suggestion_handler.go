@@ -0,0 +1,183 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"backend/internal/categoriser"
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"backend/internal/reports"
+)
+
+// defaultSuggestionLimit is how many suggestions are returned when no limit
+// is given.
+const defaultSuggestionLimit = 10
+
+// CategorySuggestionsHandler godoc
+// @Summary Suggest new categories
+// @Description Group the authenticated user's spending left in Other by merchant and propose a category, with keywords, for each group. Suggestions are ranked by total spend in the user's base currency, and names that are already categories are left out.
+// @Tags categories
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param minCount query int false "Fewest transactions a suggestion must cover, defaults to 2"
+// @Param limit query int false "Most suggestions to return, defaults to 10"
+// @Success 200 {object} models.CategorySuggestions
+// @Failure 400 {string} string "Invalid query parameters"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to suggest categories"
+// @Router /categories/suggestions [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) CategorySuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+	minCount, ok := parsePositiveIntParam(w, r, "minCount", categoriser.DefaultMinSuggestionCount)
+	if !ok {
+		return
+	}
+	limit, ok := parsePositiveIntParam(w, r, "limit", defaultSuggestionLimit)
+	if !ok {
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	baseCurrency, err := deps.baseCurrency(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting base currency: %v", err)
+		http.Error(w, exceptions.FailedToSuggestCategoriesMessage, http.StatusInternalServerError)
+		return
+	}
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, nil)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToSuggestCategoriesMessage, http.StatusInternalServerError)
+		return
+	}
+	if err := deps.fillMerchants(r.Context(), userID, transactions); err != nil {
+		log.Printf("Error listing merchant aliases: %v", err)
+		http.Error(w, exceptions.FailedToSuggestCategoriesMessage, http.StatusInternalServerError)
+		return
+	}
+	converted, err := reports.ConvertTransactions(r.Context(), deps.Rates, baseCurrency, transactions)
+	if err != nil {
+		log.Printf("Error converting transactions: %v", err)
+		http.Error(w, exceptions.FailedToSuggestCategoriesMessage, http.StatusInternalServerError)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToSuggestCategoriesMessage, http.StatusInternalServerError)
+		return
+	}
+
+	suggestions := []models.CategorySuggestion{}
+	for _, suggestion := range categoriser.Suggest(converted, minCount) {
+		if _, exists := tree.ByName(suggestion.Name); exists {
+			continue
+		}
+		suggestions = append(suggestions, suggestion)
+		if len(suggestions) == limit {
+			break
+		}
+	}
+
+	EncodeJSONResponse(w, models.CategorySuggestions{BaseCurrency: baseCurrency, Suggestions: suggestions})
+}
+
+// AcceptCategorySuggestionHandler godoc
+// @Summary Accept a category suggestion
+// @Description Create a category from a suggestion, or an edited one, and move the transactions in Other that its keywords match into it. Set parentId to create it as a subcategory.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param category body models.UserCategory true "Category to create, with at least one keyword"
+// @Success 200 {object} models.AcceptedSuggestion
+// @Failure 400 {string} string "Invalid request body or category"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to accept category suggestion"
+// @Router /categories/suggestions/accept [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) AcceptCategorySuggestionHandler(w http.ResponseWriter, r *http.Request) {
+	var category models.UserCategory
+	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	if len(category.Keywords) == 0 {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, "keywords are required"), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	category.ID = ""
+	if !deps.checkCategory(r.Context(), w, userID, category, exceptions.FailedToAcceptSuggestionMessage) {
+		return
+	}
+	categoryID, err := deps.Repo.AddUserCategory(r.Context(), userID, category)
+	if err != nil {
+		log.Printf("Error adding category: %v", err)
+		http.Error(w, exceptions.FailedToAcceptSuggestionMessage, http.StatusInternalServerError)
+		return
+	}
+	category.ID = categoryID
+
+	transactionCategoriser, err := categoriser.Load(r.Context(), deps.Repo, userID)
+	if err != nil {
+		log.Printf("Error loading categoriser: %v", err)
+		http.Error(w, exceptions.FailedToAcceptSuggestionMessage, http.StatusInternalServerError)
+		return
+	}
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, nil)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToAcceptSuggestionMessage, http.StatusInternalServerError)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToAcceptSuggestionMessage, http.StatusInternalServerError)
+		return
+	}
+
+	// Only the new category's transactions move; anything else the
+	// categoriser would now change is left for a recategorise run. These are
+	// all uncategorised, so older ones are included as Suggest includes them.
+	examined, all := transactionCategoriser.Recategorise(transactions, categoriser.RecategoriseOptions{OnlyOther: true, IncludeLegacy: true})
+	changes := []models.CategoryChange{}
+	for _, change := range all {
+		if change.To == category.Name {
+			changes = append(changes, change)
+		}
+	}
+	if err := deps.applyCategoryChanges(r.Context(), userID, tree, changes); err != nil {
+		log.Printf("Error recategorising transactions: %v", err)
+		http.Error(w, exceptions.FailedToAcceptSuggestionMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, models.AcceptedSuggestion{
+		Category:      category,
+		Recategorised: models.RecategoriseResult{Examined: examined, Changes: changes},
+	})
+}
+
+// parsePositiveIntParam reads an optional positive integer query parameter,
+// writing an error response if it is invalid.
+func parsePositiveIntParam(w http.ResponseWriter, r *http.Request, name string, fallback int) (int, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		http.Error(w, fmt.Sprintf("invalid %s %q", name, value), http.StatusBadRequest)
+		return 0, false
+	}
+	return parsed, true
+}
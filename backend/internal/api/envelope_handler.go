@@ -0,0 +1,346 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/categories"
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"backend/internal/reports"
+)
+
+const (
+	autoFundBasisAssigned = "assigned"
+	autoFundBasisSpent    = "spent"
+)
+
+// AssignRequest assigns Amount to a category's envelope, given by CategoryID
+// or by name. A negative amount returns money to ready to assign.
+type AssignRequest struct {
+	Category   string `json:"category"`
+	CategoryID string `json:"categoryId,omitempty"`
+	Amount     int64  `json:"amount"`
+}
+
+// MoveRequest moves Amount from one envelope to another. Each envelope is
+// given by category ID or by name.
+type MoveRequest struct {
+	From   string `json:"from"`
+	FromID string `json:"fromId,omitempty"`
+	To     string `json:"to"`
+	ToID   string `json:"toId,omitempty"`
+	Amount int64  `json:"amount"`
+}
+
+// GetEnvelopesHandler godoc
+// @Summary Envelopes for a month
+// @Description Get the authenticated user's envelopes and ready to assign amount for a month, in their base currency
+// @Tags envelopes
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param month path string true "Month (YYYY-MM)"
+// @Success 200 {object} models.EnvelopeMonth
+// @Failure 400 {string} string "Invalid month"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to compute envelopes"
+// @Router /envelopes/{month} [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) GetEnvelopesHandler(w http.ResponseWriter, r *http.Request) {
+	month, ok := parseMonthParam(w, r)
+	if !ok {
+		return
+	}
+	userID := r.Context().Value(userIDKey).(string)
+
+	envelopes, err := deps.envelopeMonth(r.Context(), userID, month)
+	if err != nil {
+		log.Printf("Error computing envelopes: %v", err)
+		http.Error(w, exceptions.FailedToComputeEnvelopesMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, envelopes)
+}
+
+// ListAllocationsHandler godoc
+// @Summary Allocation ledger for a month
+// @Description List the assignments, moves and auto-funding recorded for a month, oldest first
+// @Tags envelopes
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param month path string true "Month (YYYY-MM)"
+// @Success 200 {array} models.Allocation
+// @Failure 400 {string} string "Invalid month"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to list allocations"
+// @Router /envelopes/{month}/allocations [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListAllocationsHandler(w http.ResponseWriter, r *http.Request) {
+	month, ok := parseMonthParam(w, r)
+	if !ok {
+		return
+	}
+	userID := r.Context().Value(userIDKey).(string)
+
+	allocations, err := deps.Repo.ListAllocations(r.Context(), userID, month)
+	if err != nil {
+		log.Printf("Error listing allocations: %v", err)
+		http.Error(w, exceptions.FailedToListAllocationsMessage, http.StatusInternalServerError)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToListAllocationsMessage, http.StatusInternalServerError)
+		return
+	}
+	inMonth := []models.Allocation{}
+	for _, allocation := range allocations {
+		if allocation.Month == month {
+			allocation.CategoryID, allocation.Category = tree.Resolve(allocation.CategoryID, allocation.Category)
+			inMonth = append(inMonth, allocation)
+		}
+	}
+
+	EncodeJSONResponse(w, inMonth)
+}
+
+// AssignHandler godoc
+// @Summary Assign money to an envelope
+// @Description Assign part of the month's ready to assign amount to a category's envelope
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param month path string true "Month (YYYY-MM)"
+// @Param assignment body AssignRequest true "Category and amount"
+// @Success 200 {object} models.EnvelopeMonth
+// @Failure 400 {string} string "Invalid month or request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to update envelopes"
+// @Router /envelopes/{month}/assign [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) AssignHandler(w http.ResponseWriter, r *http.Request) {
+	month, ok := parseMonthParam(w, r)
+	if !ok {
+		return
+	}
+	var request AssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	if request.Amount == 0 {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, "amount must not be zero"), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	allocations := []models.Allocation{
+		{Month: month, Category: request.Category, CategoryID: request.CategoryID, Amount: request.Amount, Kind: models.AllocationKindAssign, CreatedAt: time.Now()},
+	}
+	if !deps.checkEnvelopeCategories(r.Context(), w, userID, allocations) {
+		return
+	}
+	deps.addAllocations(r.Context(), w, userID, month, allocations)
+}
+
+// MoveHandler godoc
+// @Summary Move money between envelopes
+// @Description Move money from one envelope to another, for example to cover overspending
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param month path string true "Month (YYYY-MM)"
+// @Param move body MoveRequest true "Envelopes and amount"
+// @Success 200 {object} models.EnvelopeMonth
+// @Failure 400 {string} string "Invalid month or request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to update envelopes"
+// @Router /envelopes/{month}/move [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) MoveHandler(w http.ResponseWriter, r *http.Request) {
+	month, ok := parseMonthParam(w, r)
+	if !ok {
+		return
+	}
+	var request MoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	if request.Amount <= 0 {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, "amount must be positive"), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	now := time.Now()
+	allocations := []models.Allocation{
+		{Month: month, Category: request.From, CategoryID: request.FromID, Amount: -request.Amount, Kind: models.AllocationKindMove, CreatedAt: now},
+		{Month: month, Category: request.To, CategoryID: request.ToID, Amount: request.Amount, Kind: models.AllocationKindMove, CreatedAt: now},
+	}
+	if !deps.checkEnvelopeCategories(r.Context(), w, userID, allocations) {
+		return
+	}
+	if allocations[0].CategoryID == allocations[1].CategoryID {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, "from and to must be different envelopes"), http.StatusBadRequest)
+		return
+	}
+	deps.addAllocations(r.Context(), w, userID, month, allocations)
+}
+
+// AutoFundHandler godoc
+// @Summary Auto-fund envelopes from the previous month
+// @Description Top up each envelope to what was assigned to it (basis=assigned) or spent from it (basis=spent) in the previous month. Envelopes already funded to that level are left alone, so repeating the call is safe.
+// @Tags envelopes
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param month path string true "Month (YYYY-MM)"
+// @Param basis query string false "assigned (default) or spent"
+// @Success 200 {object} models.EnvelopeMonth
+// @Failure 400 {string} string "Invalid month or basis"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to update envelopes"
+// @Router /envelopes/{month}/autofund [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) AutoFundHandler(w http.ResponseWriter, r *http.Request) {
+	month, ok := parseMonthParam(w, r)
+	if !ok {
+		return
+	}
+	basis := r.URL.Query().Get("basis")
+	if basis == "" {
+		basis = autoFundBasisAssigned
+	}
+	if basis != autoFundBasisAssigned && basis != autoFundBasisSpent {
+		http.Error(w, fmt.Sprintf("invalid basis %q, expected %s or %s", basis, autoFundBasisAssigned, autoFundBasisSpent), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	monthStart, _ := time.Parse(reports.MonthLayout, month)
+	previous, err := deps.envelopeMonth(r.Context(), userID, monthStart.AddDate(0, -1, 0).Format(reports.MonthLayout))
+	if err != nil {
+		log.Printf("Error computing envelopes: %v", err)
+		http.Error(w, exceptions.FailedToUpdateEnvelopesMessage, http.StatusInternalServerError)
+		return
+	}
+	current, err := deps.envelopeMonth(r.Context(), userID, month)
+	if err != nil {
+		log.Printf("Error computing envelopes: %v", err)
+		http.Error(w, exceptions.FailedToUpdateEnvelopesMessage, http.StatusInternalServerError)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToUpdateEnvelopesMessage, http.StatusInternalServerError)
+		return
+	}
+	alreadyAssigned := make(map[string]int64, len(current.Envelopes))
+	for _, envelope := range current.Envelopes {
+		alreadyAssigned[envelope.Category] = envelope.Assigned
+	}
+
+	now := time.Now()
+	var allocations []models.Allocation
+	for _, envelope := range previous.Envelopes {
+		target := envelope.Assigned
+		if basis == autoFundBasisSpent {
+			target = -envelope.Activity
+		}
+		if topUp := target - alreadyAssigned[envelope.Category]; topUp > 0 {
+			allocation := models.Allocation{Month: month, Amount: topUp, Kind: models.AllocationKindAutoFund, CreatedAt: now}
+			allocation.CategoryID, allocation.Category = tree.Resolve("", envelope.Category)
+			allocations = append(allocations, allocation)
+		}
+	}
+	if len(allocations) == 0 {
+		EncodeJSONResponse(w, current)
+		return
+	}
+	deps.addAllocations(r.Context(), w, userID, month, allocations)
+}
+
+// envelopeMonth loads everything needed to report on month.
+func (deps *RouterDeps) envelopeMonth(ctx context.Context, userID, month string) (*models.EnvelopeMonth, error) {
+	baseCurrency, err := deps.baseCurrency(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	allocations, err := deps.Repo.ListAllocations(ctx, userID, month)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := deps.categoryTree(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Envelopes are kept by name, so allocations use their category's
+	// current one.
+	for i := range allocations {
+		allocations[i].CategoryID, allocations[i].Category = tree.Resolve(allocations[i].CategoryID, allocations[i].Category)
+	}
+	transactions, err := deps.Repo.ListTransactions(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return reports.EnvelopeMonth(ctx, deps.Rates, baseCurrency, month, allocations, transactions)
+}
+
+// addAllocations records ledger entries and responds with the updated month.
+func (deps *RouterDeps) addAllocations(ctx context.Context, w http.ResponseWriter, userID, month string, allocations []models.Allocation) {
+	if _, err := deps.Repo.AddAllocations(ctx, userID, allocations); err != nil {
+		log.Printf("Error adding allocations: %v", err)
+		http.Error(w, exceptions.FailedToUpdateEnvelopesMessage, http.StatusInternalServerError)
+		return
+	}
+	envelopes, err := deps.envelopeMonth(ctx, userID, month)
+	if err != nil {
+		log.Printf("Error computing envelopes: %v", err)
+		http.Error(w, exceptions.FailedToComputeEnvelopesMessage, http.StatusInternalServerError)
+		return
+	}
+	EncodeJSONResponse(w, envelopes)
+}
+
+// checkEnvelopeCategories writes a 400 response unless every allocation's
+// category is one of the user's, filling in each one's ID and name.
+func (deps *RouterDeps) checkEnvelopeCategories(ctx context.Context, w http.ResponseWriter, userID string, allocations []models.Allocation) bool {
+	for i := range allocations {
+		category, err := deps.resolveCategory(ctx, userID, allocations[i].CategoryID, allocations[i].Category)
+		if err != nil {
+			if errors.Is(err, categories.ErrUnknownCategory) {
+				http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+				return false
+			}
+			log.Printf("Error listing categories: %v", err)
+			http.Error(w, exceptions.FailedToUpdateEnvelopesMessage, http.StatusInternalServerError)
+			return false
+		}
+		allocations[i].CategoryID, allocations[i].Category = category.ID, category.Name
+	}
+	return true
+}
+
+func parseMonthParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	month := mux.Vars(r)["month"]
+	if _, err := time.Parse(reports.MonthLayout, month); err != nil {
+		http.Error(w, fmt.Sprintf("invalid month %q, expected YYYY-MM", month), http.StatusBadRequest)
+		return "", false
+	}
+	return month, true
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"backend/internal/exceptions"
+	"backend/internal/importer"
+	"backend/internal/models"
+)
+
+// defaultExportDateFormat is how exported dates are written unless the
+// caller asks otherwise.
+const defaultExportDateFormat = "DD/MM/YYYY"
+
+// ExportTransactionsHandler godoc
+// @Summary Export transactions as QIF
+// @Description Export the authenticated user's transactions, oldest first, as a QIF file that desktop finance software can import. Categories are written with their parents, as in Food:Groceries, and split transactions as split lines. Give an account to export just its transactions under its account type.
+// @Tags import
+// @Produce plain
+// @Param user-id header string true "User ID"
+// @Param accountId query string false "Account to export"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param format query string false "Export format; only qif is supported"
+// @Param dateFormat query string false "How to write dates, such as MM/DD/YYYY; defaults to DD/MM/YYYY"
+// @Success 200 {string} string "QIF file"
+// @Failure 400 {string} string "Invalid query parameters or account"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to export transactions"
+// @Router /transactions/export [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ExportTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format := r.URL.Query().Get("format"); format != "" && format != "qif" {
+		http.Error(w, fmt.Sprintf("unsupported export format %q", format), http.StatusBadRequest)
+		return
+	}
+	dateFormat := r.URL.Query().Get("dateFormat")
+	if dateFormat == "" {
+		dateFormat = defaultExportDateFormat
+	}
+	if err := importer.ValidateDateFormat(dateFormat); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	accountID := r.URL.Query().Get("accountId")
+	account, err := deps.resolveAccount(r.Context(), userID, accountID)
+	if err != nil {
+		var notFoundErr *exceptions.AccountNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidAccountMessage, err), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Error getting account: %v", err)
+		http.Error(w, exceptions.FailedToExportTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+	var filters map[string]string
+	accountType := ""
+	if account != nil {
+		filters = map[string]string{"accountId": account.ID}
+		accountType = account.Type
+	}
+
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, filters)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToExportTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToExportTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+
+	var exported []models.Transaction
+	for _, transaction := range transactions {
+		date := transaction.TransactionDateTime
+		if (from != nil && date.Before(*from)) || (to != nil && date.After(*to)) {
+			continue
+		}
+		exported = append(exported, transaction)
+	}
+	sort.SliceStable(exported, func(i, j int) bool {
+		return exported[i].TransactionDateTime.Before(exported[j].TransactionDateTime)
+	})
+
+	w.Header().Set("Content-Type", "application/qif")
+	w.Header().Set("Content-Disposition", `attachment; filename="transactions.qif"`)
+	if err := importer.WriteQIF(w, accountType, exported, tree.ParentNames(), dateFormat); err != nil {
+		log.Printf("Error writing QIF: %v", err)
+	}
+}
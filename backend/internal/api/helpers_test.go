@@ -0,0 +1,117 @@
+package api
+
+import (
+	"backend/internal/db"
+	"backend/internal/fx"
+	"backend/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const testUserID = "user"
+
+// newTestDeps returns handler dependencies backed by an empty in-memory
+// repository.
+func newTestDeps(t *testing.T) (*RouterDeps, *db.MemoryRepository) {
+	t.Helper()
+	repo := db.NewMemoryRepository()
+	if err := repo.SeedNewUser(context.Background(), testUserID, map[string]interface{}{"baseCurrency": "GBP"}); err != nil {
+		t.Fatal(err)
+	}
+	return &RouterDeps{Repo: repo, Rates: fx.NewTable()}, repo
+}
+
+// serve calls handler as testUserID with the given path variables.
+func serve(handler http.HandlerFunc, method, target string, body io.Reader, vars map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, body)
+	if vars != nil {
+		r = mux.SetURLVars(r, vars)
+	}
+	return serveRequest(handler, r)
+}
+
+// serveRequest calls handler with r as testUserID, as the auth middleware
+// would.
+func serveRequest(handler http.HandlerFunc, r *http.Request) *httptest.ResponseRecorder {
+	r = r.WithContext(context.WithValue(r.Context(), userIDKey, testUserID))
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+// serveJSON calls handler with v encoded as the request body.
+func serveJSON(t *testing.T, handler http.HandlerFunc, method, target string, v any, vars map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return serve(handler, method, target, bytes.NewReader(body), vars)
+}
+
+// decode reads a successful JSON response into v.
+func decode(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func addCategory(t *testing.T, repo db.Repository, name string, keywords ...string) models.UserCategory {
+	t.Helper()
+	category := models.UserCategory{Name: name, Keywords: append([]string{}, keywords...)}
+	id, err := repo.AddUserCategory(context.Background(), testUserID, category)
+	if err != nil {
+		t.Fatal(err)
+	}
+	category.ID = id
+	return category
+}
+
+func addAccount(t *testing.T, repo db.Repository, name string) models.Account {
+	t.Helper()
+	account := models.Account{Name: name, Type: models.AccountTypeCurrent, Currency: "GBP"}
+	id, err := repo.AddAccount(context.Background(), testUserID, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account.ID = id
+	return account
+}
+
+func addTransaction(t *testing.T, repo db.Repository, transaction models.Transaction) models.Transaction {
+	t.Helper()
+	transaction.UserID = testUserID
+	if transaction.TransactionDateTime.IsZero() {
+		transaction.TransactionDateTime = time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC)
+	}
+	if transaction.Currency == "" {
+		transaction.Currency = "GBP"
+	}
+	id, err := repo.AddTransaction(context.Background(), testUserID, transaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transaction.ID = id
+	return transaction
+}
+
+func getTransaction(t *testing.T, repo db.Repository, id string) *models.Transaction {
+	t.Helper()
+	transaction, err := repo.GetTransactionByID(context.Background(), testUserID, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transaction
+}
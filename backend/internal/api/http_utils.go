@@ -2,12 +2,52 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 )
 
+const dateParamLayout = "2006-01-02"
+
 func EncodeJSONResponse(w http.ResponseWriter, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
+
+// parseDateRange reads optional from and to query parameters in YYYY-MM-DD
+// form. The to date is inclusive of the whole day.
+func parseDateRange(r *http.Request) (from, to *time.Time, err error) {
+	if value := r.URL.Query().Get("from"); value != "" {
+		parsed, err := time.Parse(dateParamLayout, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid from date %q, expected YYYY-MM-DD", value)
+		}
+		from = &parsed
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := time.Parse(dateParamLayout, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", value)
+		}
+		endOfDay := parsed.Add(24*time.Hour - time.Nanosecond)
+		to = &endOfDay
+	}
+	return from, to, nil
+}
+
+// parseBoolParam reads an optional boolean query parameter, which is false
+// when absent.
+func parseBoolParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/exceptions"
+	"backend/internal/importer"
+	"backend/internal/models"
+)
+
+// ListImportProfilesHandler godoc
+// @Summary List import profiles
+// @Description List the CSV layouts imports are detected from: the authenticated user's saved profiles followed by the built-in ones they don't replace
+// @Tags import
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Success 200 {array} models.ImportProfile
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to list import profiles"
+// @Router /transactions/import/profiles [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListImportProfilesHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+
+	profiles, err := deps.Repo.ListImportProfiles(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing import profiles: %v", err)
+		http.Error(w, exceptions.FailedToListImportProfilesMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, importer.Merge(profiles))
+}
+
+// SetImportProfileHandler godoc
+// @Summary Save an import profile
+// @Description Save a CSV layout for the authenticated user's imports, replacing their profile of the same name. A profile named after a built-in one replaces it.
+// @Tags import
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param profile body models.ImportProfile true "Column mapping, date format and sign convention"
+// @Success 200 {object} models.ImportProfile
+// @Failure 400 {string} string "Invalid import profile"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to save import profile"
+// @Router /transactions/import/profiles [put]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) SetImportProfileHandler(w http.ResponseWriter, r *http.Request) {
+	var profile models.ImportProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	if err := importer.ValidateProfile(&profile); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidImportProfileMessage, err), http.StatusBadRequest)
+		return
+	}
+	profile.BuiltIn = false
+	profile.UpdatedAt = time.Now()
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	if err := deps.Repo.SetImportProfile(r.Context(), userID, profile); err != nil {
+		log.Printf("Error saving import profile: %v", err)
+		http.Error(w, exceptions.FailedToSaveImportProfileMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, profile)
+}
+
+// DeleteImportProfileHandler godoc
+// @Summary Delete an import profile
+// @Description Delete one of the authenticated user's saved import profiles. A built-in profile it replaced is used again.
+// @Tags import
+// @Param user-id header string true "User ID"
+// @Param name path string true "Profile name"
+// @Success 200
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Import profile not found"
+// @Failure 500 {string} string "Failed to delete import profile"
+// @Router /transactions/import/profiles/{name} [delete]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) DeleteImportProfileHandler(w http.ResponseWriter, r *http.Request) {
+	name := importer.ProfileName(mux.Vars(r)["name"])
+	userID := r.Context().Value(userIDKey).(string)
+
+	if err := deps.Repo.DeleteImportProfile(r.Context(), userID, name); err != nil {
+		var notFoundErr *exceptions.ImportProfileNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.ImportProfileNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting import profile: %v", err)
+		http.Error(w, exceptions.FailedToDeleteImportProfileMessage, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/exceptions"
+	"backend/internal/merchants"
+	"backend/internal/models"
+	"backend/internal/money"
+	"backend/internal/reports"
+)
+
+// ListMerchantAliasesHandler godoc
+// @Summary List merchant aliases
+// @Description List the authenticated user's merchant aliases, which name the merchant for descriptions containing their pattern
+// @Tags merchants
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Success 200 {array} models.MerchantAlias
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to list merchant aliases"
+// @Router /merchants/aliases [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListMerchantAliasesHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+
+	aliases, err := deps.Repo.ListMerchantAliases(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing merchant aliases: %v", err)
+		http.Error(w, exceptions.FailedToListMerchantAliasesMessage, http.StatusInternalServerError)
+		return
+	}
+	if aliases == nil {
+		aliases = []models.MerchantAlias{}
+	}
+
+	EncodeJSONResponse(w, aliases)
+}
+
+// SetMerchantAliasHandler godoc
+// @Summary Set a merchant alias
+// @Description Name the merchant for descriptions containing pattern, in place of the built-in merchant table. The pattern matches whole words, ignoring case and punctuation, and replaces any alias with the same words. Existing transactions are renamed to follow it.
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param alias body models.MerchantAlias true "Pattern and merchant name"
+// @Success 200 {object} models.MerchantAliasResult
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to set merchant alias"
+// @Router /merchants/aliases [put]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) SetMerchantAliasHandler(w http.ResponseWriter, r *http.Request) {
+	var alias models.MerchantAlias
+	if err := json.NewDecoder(r.Body).Decode(&alias); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	alias.Pattern = merchants.Key(alias.Pattern)
+	if alias.Pattern == "" {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, "pattern must contain letters or digits"), http.StatusBadRequest)
+		return
+	}
+	if alias.Merchant == "" {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, "merchant is required"), http.StatusBadRequest)
+		return
+	}
+	alias.UpdatedAt = time.Now()
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	if err := deps.Repo.SetMerchantAlias(r.Context(), userID, alias); err != nil {
+		log.Printf("Error setting merchant alias: %v", err)
+		http.Error(w, exceptions.FailedToSetMerchantAliasMessage, http.StatusInternalServerError)
+		return
+	}
+	updated, err := deps.renameMerchants(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error renaming merchants: %v", err)
+		http.Error(w, exceptions.FailedToSetMerchantAliasMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, models.MerchantAliasResult{Alias: &alias, TransactionsUpdated: updated})
+}
+
+// DeleteMerchantAliasHandler godoc
+// @Summary Delete a merchant alias
+// @Description Delete one of the authenticated user's merchant aliases. Transactions it named are renamed from the built-in merchant table or their description.
+// @Tags merchants
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param pattern path string true "Alias pattern"
+// @Success 200 {object} models.MerchantAliasResult
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Merchant alias not found"
+// @Failure 500 {string} string "Failed to delete merchant alias"
+// @Router /merchants/aliases/{pattern} [delete]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) DeleteMerchantAliasHandler(w http.ResponseWriter, r *http.Request) {
+	pattern := merchants.Key(mux.Vars(r)["pattern"])
+	userID := r.Context().Value(userIDKey).(string)
+
+	if err := deps.Repo.DeleteMerchantAlias(r.Context(), userID, pattern); err != nil {
+		var notFoundErr *exceptions.MerchantAliasNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.MerchantAliasNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting merchant alias: %v", err)
+		http.Error(w, exceptions.FailedToDeleteMerchantAliasMessage, http.StatusInternalServerError)
+		return
+	}
+	updated, err := deps.renameMerchants(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error renaming merchants: %v", err)
+		http.Error(w, exceptions.FailedToDeleteMerchantAliasMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, models.MerchantAliasResult{TransactionsUpdated: updated})
+}
+
+// MerchantReportHandler godoc
+// @Summary Spending by merchant
+// @Description Total the authenticated user's transactions by merchant name, converted to their base currency using the rate on each transaction date, biggest spend first. Transfers between their own accounts are left out.
+// @Tags transactions
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param currency query string false "Currency to report in, defaults to the user's base currency"
+// @Success 200 {object} models.MerchantReport
+// @Failure 400 {string} string "Invalid date range or currency"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to summarise merchants"
+// @Router /transactions/merchants [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) MerchantReportHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	baseCurrency, err := deps.baseCurrency(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting base currency: %v", err)
+		http.Error(w, exceptions.FailedToSummariseMerchantsMessage, http.StatusInternalServerError)
+		return
+	}
+	if requested := r.URL.Query().Get("currency"); requested != "" {
+		baseCurrency, err = money.NormaliseCurrency(requested)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, nil)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToSummariseMerchantsMessage, http.StatusInternalServerError)
+		return
+	}
+	if err := deps.fillMerchants(r.Context(), userID, transactions); err != nil {
+		log.Printf("Error listing merchant aliases: %v", err)
+		http.Error(w, exceptions.FailedToSummariseMerchantsMessage, http.StatusInternalServerError)
+		return
+	}
+
+	report, err := reports.SummariseMerchants(r.Context(), deps.Rates, baseCurrency, transactions, from, to)
+	if err != nil {
+		log.Printf("Error summarising merchants: %v", err)
+		http.Error(w, exceptions.FailedToSummariseMerchantsMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, report)
+}
+
+// merchantNormaliser loads the user's merchant aliases.
+func (deps *RouterDeps) merchantNormaliser(ctx context.Context, userID string) (*merchants.Normaliser, error) {
+	aliases, err := deps.Repo.ListMerchantAliases(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return merchants.New(aliases), nil
+}
+
+// fillMerchants names the merchant of transactions stored before merchant
+// names were kept.
+func (deps *RouterDeps) fillMerchants(ctx context.Context, userID string, transactions []models.Transaction) error {
+	normaliser, err := deps.merchantNormaliser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for i := range transactions {
+		if transactions[i].Merchant == "" {
+			transactions[i].Merchant = normaliser.Normalise(transactions[i].Description)
+		}
+	}
+	return nil
+}
+
+// renameMerchants brings every transaction's merchant name up to date with
+// the user's aliases and returns how many changed.
+func (deps *RouterDeps) renameMerchants(ctx context.Context, userID string) (int, error) {
+	normaliser, err := deps.merchantNormaliser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	transactions, err := deps.Repo.ListTransactions(ctx, userID, nil)
+	if err != nil {
+		return 0, err
+	}
+	updated := 0
+	for _, transaction := range transactions {
+		merchant := normaliser.Normalise(transaction.Description)
+		if merchant == transaction.Merchant {
+			continue
+		}
+		if _, err := deps.Repo.UpdateTransaction(ctx, userID, transaction.ID, models.TransactionUpdate{Merchant: &merchant}); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
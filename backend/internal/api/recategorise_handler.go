@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"backend/internal/categories"
+	"backend/internal/categoriser"
+	"backend/internal/exceptions"
+	"backend/internal/models"
+)
+
+// RecategoriseTransactionsHandler godoc
+// @Summary Recategorise existing transactions
+// @Description Re-run the categoriser over the authenticated user's existing transactions, for example after changing rules or category keywords. Categories the user set themselves are never changed. Transactions categorised before the source of a category was recorded may have been categorised by hand, so they are left alone unless includeLegacy is set.
+// @Tags transactions
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param onlyOther query bool false "Only transactions currently in Other"
+// @Param includeLegacy query bool false "Also recategorise transactions whose category has no recorded source"
+// @Param dryRun query bool false "Only report the changes that would be made"
+// @Success 200 {object} models.RecategoriseResult
+// @Failure 400 {string} string "Invalid query parameters"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to recategorise transactions"
+// @Router /transactions/recategorise [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) RecategoriseTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts := categoriser.RecategoriseOptions{From: from, To: to}
+	if opts.OnlyOther, err = parseBoolParam(r, "onlyOther"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if opts.IncludeLegacy, err = parseBoolParam(r, "includeLegacy"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dryRun, err := parseBoolParam(r, "dryRun")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	transactionCategoriser, err := categoriser.Load(r.Context(), deps.Repo, userID)
+	if err != nil {
+		log.Printf("Error loading categoriser: %v", err)
+		http.Error(w, exceptions.FailedToRecategoriseMessage, http.StatusInternalServerError)
+		return
+	}
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, nil)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToRecategoriseMessage, http.StatusInternalServerError)
+		return
+	}
+
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToRecategoriseMessage, http.StatusInternalServerError)
+		return
+	}
+
+	examined, changes := transactionCategoriser.Recategorise(transactions, opts)
+	if !dryRun {
+		if err := deps.applyCategoryChanges(r.Context(), userID, tree, changes); err != nil {
+			log.Printf("Error recategorising transactions: %v", err)
+			http.Error(w, exceptions.FailedToRecategoriseMessage, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	EncodeJSONResponse(w, models.RecategoriseResult{DryRun: dryRun, Examined: examined, Changes: changes})
+}
+
+// applyCategoryChanges stores the categories the categoriser gave
+// transactions, along with what decided them.
+func (deps *RouterDeps) applyCategoryChanges(ctx context.Context, userID string, tree *categories.Tree, changes []models.CategoryChange) error {
+	for _, change := range changes {
+		match := change.Match
+		category, _ := tree.ByName(change.To)
+		update := models.TransactionUpdate{Category: &change.To, CategoryID: &category.ID, CategoryMatch: &match}
+		if _, err := deps.Repo.UpdateTransaction(ctx, userID, change.TransactionID, update); err != nil {
+			return fmt.Errorf("transaction %s: %w", change.TransactionID, err)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"strings"
 
@@ -9,7 +10,9 @@ import (
 	"github.com/rs/cors"
 	"google.golang.org/api/option"
 
+	"backend/internal/categories"
 	"backend/internal/db"
+	"backend/internal/fx"
 	config "backend/internal/setup"
 
 	_ "backend/docs"
@@ -22,6 +25,8 @@ import (
 
 type RouterDeps struct {
 	Repo   db.Repository
+	Rates  fx.RateProvider
+	Packs  *categories.Packs
 	Config *config.AppConfig
 }
 
@@ -49,12 +54,39 @@ func FirebaseAuthMiddleware(client *auth.Client) func(http.Handler) http.Handler
 	}
 }
 
-func NewRouter(repo db.Repository, cfg *config.AppConfig) http.Handler {
+// HeaderAuthMiddleware trusts the user-id header without verification. It is
+// only intended for offline local development.
+func HeaderAuthMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := r.Header.Get(HeaderUserID)
+			if userID == "" {
+				http.Error(w, "Missing user-id header", http.StatusUnauthorized)
+				return
+			}
+			ctx := context.WithValue(r.Context(), userIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func newAuthMiddleware(cfg *config.AppConfig) func(http.Handler) http.Handler {
+	if cfg.AuthProvider == config.AuthProviderHeader {
+		log.Printf("Using %s auth, requests are trusted without verification", config.AuthProviderHeader)
+		return HeaderAuthMiddleware()
+	}
+
 	var opts []option.ClientOption
 	if cfg.LocalCredentialsPath != "" {
 		opts = append(opts, option.WithCredentialsFile(cfg.LocalCredentialsPath))
 	}
-	app, err := firebase.NewApp(context.Background(), nil, opts...)
+	// Without a project ID Firebase finds one from the credentials or the
+	// environment, so deployments not storing data on GCP can still use it.
+	var firebaseConfig *firebase.Config
+	if cfg.ProjectID != "" {
+		firebaseConfig = &firebase.Config{ProjectID: cfg.ProjectID}
+	}
+	app, err := firebase.NewApp(context.Background(), firebaseConfig, opts...)
 	if err != nil {
 		panic("Failed to initialize Firebase app: " + err.Error())
 	}
@@ -62,9 +94,16 @@ func NewRouter(repo db.Repository, cfg *config.AppConfig) http.Handler {
 	if err != nil {
 		panic("Failed to initialize Firebase Auth client: " + err.Error())
 	}
+	return FirebaseAuthMiddleware(authClient)
+}
+
+func NewRouter(repo db.Repository, rates fx.RateProvider, packs *categories.Packs, cfg *config.AppConfig) http.Handler {
+	authMiddleware := newAuthMiddleware(cfg)
 	r := mux.NewRouter()
 	deps := &RouterDeps{
 		Repo:   repo,
+		Rates:  rates,
+		Packs:  packs,
 		Config: cfg,
 	}
 
@@ -74,29 +113,93 @@ func NewRouter(repo db.Repository, cfg *config.AppConfig) http.Handler {
 	r.HandleFunc("/health", deps.HealthCheckHandler).Methods("GET")
 
 	// User profile setup (no user-id required)
-	userProfileDeps := &SetupUserProfileDeps{Repo: repo}
+	userProfileDeps := &SetupUserProfileDeps{Repo: repo, Packs: packs, DefaultPack: cfg.CategoryPack}
 	r.HandleFunc("/setupUserProfile", userProfileDeps.SetupUserProfileHandler).Methods("POST")
 
+	// User profile handlers (require user-id)
+	r.Handle("/user", authMiddleware(http.HandlerFunc(deps.GetUserHandler))).Methods("GET")
+	r.Handle("/user", authMiddleware(http.HandlerFunc(deps.UpdateUserHandler))).Methods("PATCH")
+
 	// Transaction handlers (require user-id)
-	r.Handle("/transactions/{id}", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.GetTransactionByIDHandler))).Methods("GET")
-	r.Handle("/transactions", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.ListTransactionsHandler))).Methods("GET")
-	r.Handle("/transactions/{id}", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.UpdateTransactionHandler))).Methods("PATCH")
-	r.Handle("/transactions/{id}", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.DeleteTransactionHandler))).Methods("DELETE")
+	r.Handle("/transactions/summary", authMiddleware(http.HandlerFunc(deps.TransactionSummaryHandler))).Methods("GET")
+	r.Handle("/transactions/merchants", authMiddleware(http.HandlerFunc(deps.MerchantReportHandler))).Methods("GET")
+	r.Handle("/transactions/export", authMiddleware(http.HandlerFunc(deps.ExportTransactionsHandler))).Methods("GET")
+	r.Handle("/transactions/recategorise", authMiddleware(http.HandlerFunc(deps.RecategoriseTransactionsHandler))).Methods("POST")
+	r.Handle("/transactions/{id}", authMiddleware(http.HandlerFunc(deps.GetTransactionByIDHandler))).Methods("GET")
+	r.Handle("/transactions", authMiddleware(http.HandlerFunc(deps.ListTransactionsHandler))).Methods("GET")
+	r.Handle("/transactions/{id}", authMiddleware(http.HandlerFunc(deps.UpdateTransactionHandler))).Methods("PATCH")
+	r.Handle("/transactions/{id}", authMiddleware(http.HandlerFunc(deps.DeleteTransactionHandler))).Methods("DELETE")
+	r.Handle("/transactions/{id}/splits", authMiddleware(http.HandlerFunc(deps.SplitTransactionHandler))).Methods("PUT")
+	r.Handle("/transactions/{id}/splits", authMiddleware(http.HandlerFunc(deps.DeleteSplitsHandler))).Methods("DELETE")
 
 	// Import handlers (require user-id)
-	r.Handle("/transactions", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.CreateTransactionHandler))).Methods("POST")
-	r.Handle("/transactions/bulk", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.BulkAddTransactionsHandler))).Methods("POST")
-	r.Handle("/transactions/import", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.ImportTransactionsHandler))).Methods("POST")
+	r.Handle("/transactions", authMiddleware(http.HandlerFunc(deps.CreateTransactionHandler))).Methods("POST")
+	r.Handle("/transactions/bulk", authMiddleware(http.HandlerFunc(deps.BulkAddTransactionsHandler))).Methods("POST")
+	r.Handle("/transactions/import", authMiddleware(http.HandlerFunc(deps.ImportTransactionsHandler))).Methods("POST")
+	r.Handle("/transactions/import/profiles", authMiddleware(http.HandlerFunc(deps.ListImportProfilesHandler))).Methods("GET")
+	r.Handle("/transactions/import/profiles", authMiddleware(http.HandlerFunc(deps.SetImportProfileHandler))).Methods("PUT")
+	r.Handle("/transactions/import/profiles/{name}", authMiddleware(http.HandlerFunc(deps.DeleteImportProfileHandler))).Methods("DELETE")
+
+	// Account handlers (require user-id)
+	r.Handle("/accounts", authMiddleware(http.HandlerFunc(deps.ListAccountsHandler))).Methods("GET")
+	r.Handle("/accounts", authMiddleware(http.HandlerFunc(deps.AddAccountHandler))).Methods("POST")
+	r.Handle("/accounts/{id}", authMiddleware(http.HandlerFunc(deps.GetAccountHandler))).Methods("GET")
+	r.Handle("/accounts/{id}", authMiddleware(http.HandlerFunc(deps.UpdateAccountHandler))).Methods("PATCH")
+	r.Handle("/accounts/{id}", authMiddleware(http.HandlerFunc(deps.DeleteAccountHandler))).Methods("DELETE")
+	r.Handle("/accounts/{id}/balances", authMiddleware(http.HandlerFunc(deps.AccountBalancesHandler))).Methods("GET")
+	r.Handle("/accounts/{id}/reconciliation", authMiddleware(http.HandlerFunc(deps.ReconciliationHandler))).Methods("GET")
+	r.Handle("/accounts/{id}/checkpoints", authMiddleware(http.HandlerFunc(deps.ListBalanceCheckpointsHandler))).Methods("GET")
+	r.Handle("/accounts/{id}/checkpoints", authMiddleware(http.HandlerFunc(deps.AddBalanceCheckpointHandler))).Methods("POST")
+	r.Handle("/accounts/{id}/checkpoints/{checkpointId}", authMiddleware(http.HandlerFunc(deps.DeleteBalanceCheckpointHandler))).Methods("DELETE")
+
+	// Transfer handlers (require user-id)
+	r.Handle("/transfers", authMiddleware(http.HandlerFunc(deps.ListTransfersHandler))).Methods("GET")
+	r.Handle("/transfers", authMiddleware(http.HandlerFunc(deps.LinkTransferHandler))).Methods("POST")
+	r.Handle("/transfers/detect", authMiddleware(http.HandlerFunc(deps.DetectTransfersHandler))).Methods("POST")
+	r.Handle("/transfers/{id}", authMiddleware(http.HandlerFunc(deps.UnlinkTransferHandler))).Methods("DELETE")
+
+	// Categorisation rule handlers (require user-id)
+	r.Handle("/rules", authMiddleware(http.HandlerFunc(deps.ListRulesHandler))).Methods("GET")
+	r.Handle("/rules", authMiddleware(http.HandlerFunc(deps.AddRuleHandler))).Methods("POST")
+	r.Handle("/rules/{id}", authMiddleware(http.HandlerFunc(deps.GetRuleHandler))).Methods("GET")
+	r.Handle("/rules/{id}", authMiddleware(http.HandlerFunc(deps.UpdateRuleHandler))).Methods("PATCH")
+	r.Handle("/rules/{id}", authMiddleware(http.HandlerFunc(deps.DeleteRuleHandler))).Methods("DELETE")
+
+	// Budget handlers (require user-id)
+	r.Handle("/budgets", authMiddleware(http.HandlerFunc(deps.ListBudgetsHandler))).Methods("GET")
+	r.Handle("/budgets", authMiddleware(http.HandlerFunc(deps.AddBudgetHandler))).Methods("POST")
+	r.Handle("/budgets/{id}", authMiddleware(http.HandlerFunc(deps.GetBudgetHandler))).Methods("GET")
+	r.Handle("/budgets/{id}", authMiddleware(http.HandlerFunc(deps.UpdateBudgetHandler))).Methods("PATCH")
+	r.Handle("/budgets/{id}", authMiddleware(http.HandlerFunc(deps.DeleteBudgetHandler))).Methods("DELETE")
+	r.Handle("/budgets/{id}/progress", authMiddleware(http.HandlerFunc(deps.BudgetProgressHandler))).Methods("GET")
+
+	// Envelope handlers (require user-id)
+	r.Handle("/envelopes/{month}", authMiddleware(http.HandlerFunc(deps.GetEnvelopesHandler))).Methods("GET")
+	r.Handle("/envelopes/{month}/allocations", authMiddleware(http.HandlerFunc(deps.ListAllocationsHandler))).Methods("GET")
+	r.Handle("/envelopes/{month}/assign", authMiddleware(http.HandlerFunc(deps.AssignHandler))).Methods("POST")
+	r.Handle("/envelopes/{month}/move", authMiddleware(http.HandlerFunc(deps.MoveHandler))).Methods("POST")
+	r.Handle("/envelopes/{month}/autofund", authMiddleware(http.HandlerFunc(deps.AutoFundHandler))).Methods("POST")
 
 	// Category handlers (require user-id)
-	r.Handle("/categories", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.ListCategoriesHandler))).Methods("GET")
-	r.Handle("/categories", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.AddCategoryHandler))).Methods("POST")
-	r.Handle("/categories/{id}", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.UpdateCategoryHandler))).Methods("PATCH")
-	r.Handle("/categories/{id}", FirebaseAuthMiddleware(authClient)(http.HandlerFunc(deps.DeleteCategoryHandler))).Methods("DELETE")
+	r.Handle("/categories", authMiddleware(http.HandlerFunc(deps.ListCategoriesHandler))).Methods("GET")
+	r.Handle("/categories", authMiddleware(http.HandlerFunc(deps.AddCategoryHandler))).Methods("POST")
+	r.Handle("/categories/packs", authMiddleware(http.HandlerFunc(deps.ListCategoryPacksHandler))).Methods("GET")
+	r.Handle("/categories/packs/{name}/seed", authMiddleware(http.HandlerFunc(deps.SeedCategoryPackHandler))).Methods("POST")
+	r.Handle("/categories/suggestions", authMiddleware(http.HandlerFunc(deps.CategorySuggestionsHandler))).Methods("GET")
+	r.Handle("/categories/suggestions/accept", authMiddleware(http.HandlerFunc(deps.AcceptCategorySuggestionHandler))).Methods("POST")
+	r.Handle("/categories/mappings", authMiddleware(http.HandlerFunc(deps.ListCategoryMappingsHandler))).Methods("GET")
+	r.Handle("/categories/mappings/{merchant}", authMiddleware(http.HandlerFunc(deps.DeleteCategoryMappingHandler))).Methods("DELETE")
+	r.Handle("/categories/{id}", authMiddleware(http.HandlerFunc(deps.UpdateCategoryHandler))).Methods("PATCH")
+	r.Handle("/categories/{id}", authMiddleware(http.HandlerFunc(deps.DeleteCategoryHandler))).Methods("DELETE")
+
+	// Merchant alias handlers (require user-id)
+	r.Handle("/merchants/aliases", authMiddleware(http.HandlerFunc(deps.ListMerchantAliasesHandler))).Methods("GET")
+	r.Handle("/merchants/aliases", authMiddleware(http.HandlerFunc(deps.SetMerchantAliasHandler))).Methods("PUT")
+	r.Handle("/merchants/aliases/{pattern}", authMiddleware(http.HandlerFunc(deps.DeleteMerchantAliasHandler))).Methods("DELETE")
 
 	c := cors.New(cors.Options{
 		AllowedOrigins:   cfg.CorsAllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "Authorization", HeaderUserID},
 		AllowCredentials: true,
 		Debug:            true,
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderAuthMiddleware(t *testing.T) {
+	var userID string
+	handler := HeaderAuthMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID = r.Context().Value(userIDKey).(string)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/transactions", nil)
+	r.Header.Set(HeaderUserID, "dev-user")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || userID != "dev-user" {
+		t.Errorf("with a user-id header = %d as %q, want 200 as dev-user", w.Code, userID)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/transactions", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("without a user-id header = %d, want 401", w.Code)
+	}
+}
@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/categories"
+	"backend/internal/categoriser"
+	"backend/internal/exceptions"
+	"backend/internal/models"
+)
+
+// ListRulesHandler godoc
+// @Summary List categorisation rules
+// @Description Get the authenticated user's categorisation rules in the order they are applied
+// @Tags rules
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Success 200 {array} models.CategoryRule
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to list rules"
+// @Router /rules [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListRulesHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+
+	rules, err := deps.Repo.ListRules(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing rules: %v", err)
+		http.Error(w, exceptions.FailedToListRulesMessage, http.StatusInternalServerError)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToListRulesMessage, http.StatusInternalServerError)
+		return
+	}
+	for i := range rules {
+		rules[i].CategoryID, rules[i].Category = tree.Resolve(rules[i].CategoryID, rules[i].Category)
+	}
+	if rules == nil {
+		rules = []models.CategoryRule{}
+	}
+
+	EncodeJSONResponse(w, rules)
+}
+
+// AddRuleHandler godoc
+// @Summary Add a categorisation rule
+// @Description Add a rule that sets the category, given by categoryId or category, of new transactions meeting all of its conditions. Rules run in ascending priority and the first match wins; without a priority the rule goes after the existing ones.
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param rule body models.CategoryRule true "Rule to add"
+// @Success 200 {object} models.CategoryRule
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to create rule"
+// @Router /rules [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) AddRuleHandler(w http.ResponseWriter, r *http.Request) {
+	var rule models.CategoryRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	if rule.Priority == 0 {
+		rules, err := deps.Repo.ListRules(r.Context(), userID)
+		if err != nil {
+			log.Printf("Error listing rules: %v", err)
+			http.Error(w, exceptions.FailedToCreateRuleMessage, http.StatusInternalServerError)
+			return
+		}
+		rule.Priority = 1
+		if len(rules) > 0 {
+			rule.Priority = rules[len(rules)-1].Priority + 1
+		}
+	}
+	if !deps.checkRule(r.Context(), w, userID, &rule, exceptions.FailedToCreateRuleMessage) {
+		return
+	}
+
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+	ruleID, err := deps.Repo.AddRule(r.Context(), userID, rule)
+	if err != nil {
+		log.Printf("Error adding rule: %v", err)
+		http.Error(w, exceptions.FailedToCreateRuleMessage, http.StatusInternalServerError)
+		return
+	}
+
+	rule.ID = ruleID
+	EncodeJSONResponse(w, rule)
+}
+
+// GetRuleHandler godoc
+// @Summary Get categorisation rule by ID
+// @Description Get a categorisation rule by its ID for the authenticated user
+// @Tags rules
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Rule ID"
+// @Success 200 {object} models.CategoryRule
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Rule not found"
+// @Failure 500 {string} string "Failed to get rule"
+// @Router /rules/{id} [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) GetRuleHandler(w http.ResponseWriter, r *http.Request) {
+	ruleID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	rule, err := deps.Repo.GetRule(r.Context(), userID, ruleID)
+	if err != nil {
+		writeRuleError(w, err, exceptions.RuleNotFoundMessage)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToGetRuleMessage, http.StatusInternalServerError)
+		return
+	}
+	rule.CategoryID, rule.Category = tree.Resolve(rule.CategoryID, rule.Category)
+
+	EncodeJSONResponse(w, rule)
+}
+
+// UpdateRuleHandler godoc
+// @Summary Update a categorisation rule
+// @Description Update a categorisation rule for the authenticated user. Set the category by categoryId or by name. Conditions, when given, replace the existing ones.
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Rule ID"
+// @Param rule body models.CategoryRuleUpdate true "Rule update data"
+// @Success 200 {object} models.CategoryRule
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Rule not found"
+// @Failure 500 {string} string "Failed to update rule"
+// @Router /rules/{id} [patch]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) UpdateRuleHandler(w http.ResponseWriter, r *http.Request) {
+	ruleID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	var updateData models.CategoryRuleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+
+	rule, err := deps.Repo.GetRule(r.Context(), userID, ruleID)
+	if err != nil {
+		writeRuleError(w, err, exceptions.FailedToUpdateRuleMessage)
+		return
+	}
+	if updateData.Name != nil {
+		rule.Name = *updateData.Name
+	}
+	if updateData.Priority != nil {
+		rule.Priority = *updateData.Priority
+	}
+	// A category ID takes precedence over a name.
+	if updateData.CategoryID != nil {
+		rule.CategoryID = *updateData.CategoryID
+	} else if updateData.Category != nil {
+		rule.Category, rule.CategoryID = *updateData.Category, ""
+	}
+	if updateData.Conditions != nil {
+		rule.Conditions = *updateData.Conditions
+	}
+	if !deps.checkRule(r.Context(), w, userID, rule, exceptions.FailedToUpdateRuleMessage) {
+		return
+	}
+	if updateData.Category != nil || updateData.CategoryID != nil {
+		updateData.Category, updateData.CategoryID = &rule.Category, &rule.CategoryID
+	}
+
+	updated, err := deps.Repo.UpdateRule(r.Context(), userID, ruleID, updateData)
+	if err != nil {
+		writeRuleError(w, err, exceptions.FailedToUpdateRuleMessage)
+		return
+	}
+
+	EncodeJSONResponse(w, updated)
+}
+
+// DeleteRuleHandler godoc
+// @Summary Delete a categorisation rule
+// @Description Delete a categorisation rule for the authenticated user
+// @Tags rules
+// @Param user-id header string true "User ID"
+// @Param id path string true "Rule ID"
+// @Success 204 {string} string "No Content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Rule not found"
+// @Failure 500 {string} string "Failed to delete rule"
+// @Router /rules/{id} [delete]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) DeleteRuleHandler(w http.ResponseWriter, r *http.Request) {
+	ruleID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	if err := deps.Repo.DeleteRule(r.Context(), userID, ruleID); err != nil {
+		writeRuleError(w, err, exceptions.FailedToDeleteRuleMessage)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkRule writes an error response unless rule is well formed and refers to
+// one of the user's categories and, if it names one, accounts. The rule's
+// category ID and name are filled in from the category, by ID or by name.
+func (deps *RouterDeps) checkRule(ctx context.Context, w http.ResponseWriter, userID string, rule *models.CategoryRule, failedMessage string) bool {
+	// Without either, compiling reports the missing category.
+	if rule.CategoryID != "" || rule.Category != "" {
+		category, err := deps.resolveCategory(ctx, userID, rule.CategoryID, rule.Category)
+		if err != nil {
+			if errors.Is(err, categories.ErrUnknownCategory) {
+				http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+				return false
+			}
+			log.Printf("Error listing categories: %v", err)
+			http.Error(w, failedMessage, http.StatusInternalServerError)
+			return false
+		}
+		rule.CategoryID, rule.Category = category.ID, category.Name
+	}
+	if _, err := categoriser.CompileRule(*rule); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return false
+	}
+	if _, err := deps.resolveAccount(ctx, userID, rule.Conditions.AccountID); err != nil {
+		var notFoundErr *exceptions.AccountNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidAccountMessage, err), http.StatusBadRequest)
+			return false
+		}
+		log.Printf("Error getting account: %v", err)
+		http.Error(w, failedMessage, http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+func writeRuleError(w http.ResponseWriter, err error, failedMessage string) {
+	var notFoundErr *exceptions.RuleNotFoundError
+	if errors.As(err, &notFoundErr) {
+		http.Error(w, exceptions.RuleNotFoundMessage, http.StatusNotFound)
+		return
+	}
+	log.Printf("Error accessing rule: %v", err)
+	http.Error(w, failedMessage, http.StatusInternalServerError)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/categories"
+	"backend/internal/exceptions"
+	"backend/internal/models"
+)
+
+// SplitTransactionHandler godoc
+// @Summary Split a transaction
+// @Description Divide a transaction across categories, replacing any split it already has. Each line needs a non-zero amount and a category, given by categoryId or name, and the lines must sum to the transaction's amount. Summaries, budgets and envelopes then count the lines in place of the transaction's own category.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Transaction ID"
+// @Param splits body []models.Split true "Split lines"
+// @Success 200 {object} models.Transaction
+// @Failure 400 {string} string "Invalid request body or splits"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Transaction not found"
+// @Failure 500 {string} string "Failed to split transaction"
+// @Router /transactions/{id}/splits [put]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) SplitTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	transactionID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	var splits []models.Split
+	if err := json.NewDecoder(r.Body).Decode(&splits); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := deps.Repo.GetTransactionByID(r.Context(), userID, transactionID)
+	if err != nil {
+		writeTransactionError(w, err, userID, transactionID, exceptions.FailedToSplitTransactionMessage)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToSplitTransactionMessage, http.StatusInternalServerError)
+		return
+	}
+	if err := resolveSplits(tree, transaction.Amount, splits); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidSplitsMessage, err), http.StatusBadRequest)
+		return
+	}
+
+	deps.updateSplits(w, r, userID, transactionID, splits)
+}
+
+// DeleteSplitsHandler godoc
+// @Summary Remove a transaction's split
+// @Description Remove the split lines from a transaction, so that it counts wholly against its own category again
+// @Tags transactions
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} models.Transaction
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Transaction not found"
+// @Failure 500 {string} string "Failed to split transaction"
+// @Router /transactions/{id}/splits [delete]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) DeleteSplitsHandler(w http.ResponseWriter, r *http.Request) {
+	transactionID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	deps.updateSplits(w, r, userID, transactionID, []models.Split{})
+}
+
+// updateSplits saves a transaction's split lines and responds with the
+// updated transaction.
+func (deps *RouterDeps) updateSplits(w http.ResponseWriter, r *http.Request, userID, transactionID string, splits []models.Split) {
+	transaction, err := deps.Repo.UpdateTransaction(r.Context(), userID, transactionID, models.TransactionUpdate{Splits: &splits})
+	if err != nil {
+		writeTransactionError(w, err, userID, transactionID, exceptions.FailedToSplitTransactionMessage)
+		return
+	}
+	EncodeJSONResponse(w, transaction)
+}
+
+// resolveSplits checks that splits divide amount between known categories,
+// filling in each line's category name and ID.
+func resolveSplits(tree *categories.Tree, amount int64, splits []models.Split) error {
+	if len(splits) < 2 {
+		return errors.New("a split needs at least two lines")
+	}
+	var total int64
+	for i := range splits {
+		line := &splits[i]
+		if line.Amount == 0 {
+			return fmt.Errorf("line %d has no amount", i+1)
+		}
+		if line.CategoryID == "" && line.Category == "" {
+			return fmt.Errorf("line %d has no category", i+1)
+		}
+		category, ok := tree.Lookup(line.CategoryID, line.Category)
+		if !ok {
+			given := line.CategoryID
+			if given == "" {
+				given = line.Category
+			}
+			return fmt.Errorf("line %d: %w %q", i+1, categories.ErrUnknownCategory, given)
+		}
+		line.Category, line.CategoryID = category.Name, category.ID
+		total += line.Amount
+	}
+	if total != amount {
+		return fmt.Errorf("lines sum to %d but the transaction amount is %d", total, amount)
+	}
+	return nil
+}
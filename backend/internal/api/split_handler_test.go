@@ -0,0 +1,49 @@
+package api
+
+import (
+	"backend/internal/models"
+	"net/http"
+	"testing"
+)
+
+func TestSplitTransactionHandler(t *testing.T) {
+	deps, repo := newTestDeps(t)
+	groceries := addCategory(t, repo, "Groceries")
+	household := addCategory(t, repo, "Household")
+	shop := addTransaction(t, repo, models.Transaction{Description: "SAINSBURYS", Amount: -6000, Category: "Groceries", CategoryID: groceries.ID})
+	vars := map[string]string{"id": shop.ID}
+
+	splits := []models.Split{
+		{Amount: -4500, CategoryID: groceries.ID},
+		{Amount: -1500, Category: "household", Note: "Bin bags"},
+	}
+	var split models.Transaction
+	decode(t, serveJSON(t, deps.SplitTransactionHandler, http.MethodPut, "/transactions/"+shop.ID+"/splits", splits, vars), &split)
+	want := []models.Split{
+		{Amount: -4500, Category: "Groceries", CategoryID: groceries.ID},
+		{Amount: -1500, Category: "Household", CategoryID: household.ID, Note: "Bin bags"},
+	}
+	saved := getTransaction(t, repo, shop.ID)
+	if len(saved.Splits) != 2 || saved.Splits[0] != want[0] || saved.Splits[1] != want[1] {
+		t.Errorf("saved splits = %+v, want %+v", saved.Splits, want)
+	}
+
+	for name, splits := range map[string][]models.Split{
+		"wrong total":      {{Amount: -4500, CategoryID: groceries.ID}, {Amount: -1000, CategoryID: household.ID}},
+		"unknown category": {{Amount: -4500, CategoryID: groceries.ID}, {Amount: -1500, Category: "Pets"}},
+		"one line":         {{Amount: -6000, CategoryID: groceries.ID}},
+	} {
+		if w := serveJSON(t, deps.SplitTransactionHandler, http.MethodPut, "/transactions/"+shop.ID+"/splits", splits, vars); w.Code != http.StatusBadRequest {
+			t.Errorf("split with %s = %d, want 400", name, w.Code)
+		}
+	}
+	if w := serveJSON(t, deps.SplitTransactionHandler, http.MethodPut, "/transactions/missing/splits", splits, map[string]string{"id": "missing"}); w.Code != http.StatusNotFound {
+		t.Errorf("split of a missing transaction = %d, want 404", w.Code)
+	}
+
+	var unsplit models.Transaction
+	decode(t, serve(deps.DeleteSplitsHandler, http.MethodDelete, "/transactions/"+shop.ID+"/splits", nil, vars), &unsplit)
+	if saved := getTransaction(t, repo, shop.ID); len(saved.Splits) != 0 || saved.CategoryID != groceries.ID {
+		t.Errorf("transaction after removing its split = %+v, want it whole in Groceries", saved)
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"backend/internal/importer"
+	"backend/internal/models"
+	"sort"
+	"time"
+)
+
+// statementCheckpoints turns per-row statement balances into one end-of-day
+// checkpoint per date. Banks list same-day rows in either order, so the
+// closing row is the one whose balance no other row of that day starts
+// from. Days where that is ambiguous are skipped rather than guessed.
+func statementCheckpoints(rows []importer.StatementBalance) []models.BalanceCheckpoint {
+	byDay := make(map[time.Time][]importer.StatementBalance)
+	for _, row := range rows {
+		day := time.Date(row.Date.Year(), row.Date.Month(), row.Date.Day(), 0, 0, 0, 0, time.UTC)
+		byDay[day] = append(byDay[day], row)
+	}
+
+	var checkpoints []models.BalanceCheckpoint
+	for day, dayRows := range byDay {
+		openings := make(map[int64]int, len(dayRows))
+		for _, row := range dayRows {
+			openings[row.Balance-row.Amount]++
+		}
+		var closing []int64
+		for _, row := range dayRows {
+			if openings[row.Balance] == 0 {
+				closing = append(closing, row.Balance)
+			}
+		}
+		if len(closing) != 1 {
+			continue
+		}
+		checkpoints = append(checkpoints, models.BalanceCheckpoint{
+			Date:      day,
+			Balance:   closing[0],
+			Source:    models.BalanceSourceStatement,
+			CreatedAt: time.Now(),
+		})
+	}
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i].Date.Before(checkpoints[j].Date) })
+	return checkpoints
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"backend/internal/importer"
+	"backend/internal/models"
+	"testing"
+	"time"
+)
+
+func TestStatementCheckpoints(t *testing.T) {
+	june := func(day, hour int) time.Time { return time.Date(2025, time.June, day, hour, 0, 0, 0, time.UTC) }
+	row := func(date time.Time, amount, balance int64) importer.StatementBalance {
+		return importer.StatementBalance{Date: date, Amount: amount, Balance: balance}
+	}
+	tests := []struct {
+		name string
+		rows []importer.StatementBalance
+		want map[time.Time]int64
+	}{
+		{
+			name: "one row a day",
+			rows: []importer.StatementBalance{row(june(2, 0), -500, 9500), row(june(3, 0), 2000, 11500)},
+			want: map[time.Time]int64{june(2, 0): 9500, june(3, 0): 11500},
+		},
+		{
+			name: "same-day rows oldest first",
+			rows: []importer.StatementBalance{row(june(2, 9), -500, 9500), row(june(2, 12), -1000, 8500), row(june(2, 18), 200, 8700)},
+			want: map[time.Time]int64{june(2, 0): 8700},
+		},
+		{
+			name: "same-day rows newest first",
+			rows: []importer.StatementBalance{row(june(2, 0), 200, 8700), row(june(2, 0), -1000, 8500), row(june(2, 0), -500, 9500)},
+			want: map[time.Time]int64{june(2, 0): 8700},
+		},
+		{
+			name: "rows of a day split by other days",
+			rows: []importer.StatementBalance{row(june(3, 0), 100, 8800), row(june(2, 0), -1000, 8500), row(june(2, 0), 200, 8700), row(june(2, 0), -500, 9500)},
+			want: map[time.Time]int64{june(2, 0): 8700, june(3, 0): 8800},
+		},
+		{
+			name: "payment and refund back to the opening balance",
+			rows: []importer.StatementBalance{row(june(2, 0), -500, 9500), row(june(2, 0), 500, 10000), row(june(3, 0), -100, 9900)},
+			want: map[time.Time]int64{june(3, 0): 9900},
+		},
+		{
+			name: "rows that don't chain",
+			rows: []importer.StatementBalance{row(june(2, 0), -500, 9500), row(june(2, 0), -300, 4000)},
+			want: map[time.Time]int64{},
+		},
+		{
+			name: "a zero-amount row alone",
+			rows: []importer.StatementBalance{row(june(2, 0), 0, 10000)},
+			want: map[time.Time]int64{},
+		},
+		{
+			name: "no rows",
+			want: map[time.Time]int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpoints := statementCheckpoints(tt.rows)
+			got := make(map[time.Time]int64, len(checkpoints))
+			for i, checkpoint := range checkpoints {
+				if i > 0 && !checkpoints[i-1].Date.Before(checkpoint.Date) {
+					t.Errorf("checkpoints out of date order: %+v", checkpoints)
+				}
+				if checkpoint.Source != models.BalanceSourceStatement {
+					t.Errorf("Source = %q, want %q", checkpoint.Source, models.BalanceSourceStatement)
+				}
+				got[checkpoint.Date] = checkpoint.Balance
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("checkpoints = %v, want %v", got, tt.want)
+			}
+			for day, balance := range tt.want {
+				if got[day] != balance {
+					t.Errorf("checkpoint on %s = %d, want %d", day.Format(time.DateOnly), got[day], balance)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/money"
+	"backend/internal/reports"
+	"log"
+	"net/http"
+)
+
+// TransactionSummaryHandler godoc
+// @Summary Summarise transactions
+// @Description Total the authenticated user's transactions by category, converted to their base currency using the rate on each transaction date. Rollups add subcategories into their parents.
+// @Tags transactions
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param currency query string false "Currency to report in, defaults to the user's base currency"
+// @Success 200 {object} models.TransactionSummary
+// @Failure 400 {string} string "Invalid date range or currency"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to summarise transactions"
+// @Router /transactions/summary [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) TransactionSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	baseCurrency, err := deps.baseCurrency(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting base currency: %v", err)
+		http.Error(w, exceptions.FailedToSummariseMessage, http.StatusInternalServerError)
+		return
+	}
+	if requested := r.URL.Query().Get("currency"); requested != "" {
+		baseCurrency, err = money.NormaliseCurrency(requested)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, nil)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToSummariseMessage, http.StatusInternalServerError)
+		return
+	}
+
+	summary, err := reports.Summarise(r.Context(), deps.Rates, baseCurrency, transactions, from, to)
+	if err != nil {
+		log.Printf("Error summarising transactions: %v", err)
+		http.Error(w, exceptions.FailedToSummariseMessage, http.StatusInternalServerError)
+		return
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToSummariseMessage, http.StatusInternalServerError)
+		return
+	}
+	reports.RollUp(summary, tree.ParentNames())
+
+	EncodeJSONResponse(w, summary)
+}
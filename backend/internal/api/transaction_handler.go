@@ -2,23 +2,23 @@ package api
 
 import (
 	"context"
-	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 
+	"backend/internal/categories"
 	"backend/internal/categoriser"
-	"backend/internal/db"
 	"backend/internal/exceptions"
+	"backend/internal/importer"
 	"backend/internal/models"
+	"backend/internal/money"
+	"backend/internal/reports"
 )
 
 // CreateTransactionHandler godoc
@@ -44,16 +44,64 @@ func (deps *RouterDeps) CreateTransactionHandler(w http.ResponseWriter, r *http.
 
 	userID := r.Context().Value(userIDKey).(string)
 
+	baseCurrency, err := deps.baseCurrency(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting base currency: %v", err)
+		http.Error(w, exceptions.FailedToCreateTransactionMessage, http.StatusInternalServerError)
+		return
+	}
+	account, err := deps.resolveAccount(r.Context(), userID, transaction.AccountID)
+	if err != nil {
+		var notFoundErr *exceptions.AccountNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidAccountMessage, err), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Error getting account: %v", err)
+		http.Error(w, exceptions.FailedToCreateTransactionMessage, http.StatusInternalServerError)
+		return
+	}
+	if account != nil {
+		baseCurrency = account.Currency
+	}
+	currency, err := normaliseCurrency(transaction.Currency, baseCurrency)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidCurrencyMessage, err), http.StatusBadRequest)
+		return
+	}
+
 	transaction.UserID = userID
+	transaction.Currency = currency
+	transaction.TransferID = ""
+	transaction.Splits = nil
 	transaction.InsertedAt = time.Now()
 	transaction.UpdatedAt = time.Now()
 
-	category, err := categoriser.CategoriseTransaction(r.Context(), deps.Repo, userID, transaction.Description, transaction.Category)
+	normaliser, err := deps.merchantNormaliser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing merchant aliases: %v", err)
+		http.Error(w, exceptions.FailedToCreateTransactionMessage, http.StatusInternalServerError)
+		return
+	}
+	transaction.Merchant = normaliser.Normalise(transaction.Description)
+
+	transactionCategoriser, err := categoriser.Load(r.Context(), deps.Repo, userID)
+	if err != nil {
+		log.Printf("Error loading categoriser: %v", err)
+		http.Error(w, exceptions.FailedToCategoriseMessage, http.StatusInternalServerError)
+		return
+	}
+	match := transactionCategoriser.Explain(transaction)
+	transaction.Category = match.Category
+	transaction.CategoryID = ""
+	transaction.CategoryMatch = &match
+	tree, err := deps.categoryTree(r.Context(), userID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(exceptions.FailedToCategoriseMessage, err), http.StatusInternalServerError)
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToCreateTransactionMessage, http.StatusInternalServerError)
 		return
 	}
-	transaction.Category = category
+	tree.FillID(&transaction)
 
 	transactionID, err := deps.Repo.AddTransaction(context.Background(), userID, transaction)
 	if err != nil {
@@ -69,7 +117,7 @@ func (deps *RouterDeps) CreateTransactionHandler(w http.ResponseWriter, r *http.
 
 // GetTransactionByIDHandler godoc
 // @Summary Get transaction by ID
-// @Description Get a transaction by its ID for the authenticated user
+// @Description Get a transaction by its ID for the authenticated user, including categoryMatch, which explains how it got its category
 // @Tags transactions
 // @Produce json
 // @Param user-id header string true "User ID"
@@ -109,18 +157,26 @@ func (deps *RouterDeps) GetTransactionByIDHandler(w http.ResponseWriter, r *http
 		http.Error(w, exceptions.TransactionNotFoundMessage, http.StatusNotFound)
 		return
 	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToGetTransactionMessage, http.StatusInternalServerError)
+		return
+	}
+	tree.FillID(transaction)
 
 	EncodeJSONResponse(w, transaction)
 }
 
 // ListTransactionsHandler godoc
 // @Summary List transactions
-// @Description List transactions for the authenticated user, optionally filtered
+// @Description List transactions for the authenticated user, optionally filtered, with amounts converted to their base currency
 // @Tags transactions
 // @Produce json
 // @Param user-id header string true "User ID"
+// @Param accountId query string false "Only transactions in this account"
 // @Param filters query string false "Filters (key=value)"
-// @Success 200 {array} models.Transaction
+// @Success 200 {array} models.ConvertedTransaction
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 500 {string} string "Failed to list transactions"
 // @Router /transactions [get]
@@ -142,12 +198,35 @@ func (deps *RouterDeps) ListTransactionsHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	EncodeJSONResponse(w, transactions)
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToListTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+	for i := range transactions {
+		tree.FillID(&transactions[i])
+	}
+
+	baseCurrency, err := deps.baseCurrency(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting base currency: %v", err)
+		http.Error(w, exceptions.FailedToListTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+	converted, err := reports.ConvertTransactions(r.Context(), deps.Rates, baseCurrency, transactions)
+	if err != nil {
+		log.Printf("Error converting transactions: %v", err)
+		http.Error(w, exceptions.FailedToListTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, converted)
 }
 
 // BulkAddTransactionsHandler godoc
 // @Summary Bulk add transactions
-// @Description Add multiple transactions for the authenticated user
+// @Description Add multiple transactions for the authenticated user. Each may name its category by categoryId or category.
 // @Tags transactions
 // @Accept json
 // @Produce json
@@ -168,13 +247,71 @@ func (deps *RouterDeps) BulkAddTransactionsHandler(w http.ResponseWriter, r *htt
 
 	userID := r.Context().Value(userIDKey).(string)
 
+	baseCurrency, err := deps.baseCurrency(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting base currency: %v", err)
+		http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+
+	if err := deps.resolveCategories(r.Context(), userID, transactions); err != nil {
+		if errors.Is(err, categories.ErrUnknownCategory) {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidCategoryMessage, err), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+
+	normaliser, err := deps.merchantNormaliser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing merchant aliases: %v", err)
+		http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+
+	accounts := make(map[string]*models.Account)
 	for i := range transactions {
+		accountID := transactions[i].AccountID
+		account, ok := accounts[accountID]
+		if !ok {
+			account, err = deps.resolveAccount(r.Context(), userID, accountID)
+			if err != nil {
+				var notFoundErr *exceptions.AccountNotFoundError
+				if errors.As(err, &notFoundErr) {
+					http.Error(w, fmt.Sprintf(exceptions.InvalidAccountMessage, err), http.StatusBadRequest)
+					return
+				}
+				log.Printf("Error getting account: %v", err)
+				http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
+				return
+			}
+			accounts[accountID] = account
+		}
+		fallback := baseCurrency
+		if account != nil {
+			fallback = account.Currency
+		}
+		currency, err := normaliseCurrency(transactions[i].Currency, fallback)
+		if err != nil {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidCurrencyMessage, err), http.StatusBadRequest)
+			return
+		}
+		transactions[i].Currency = currency
 		transactions[i].UserID = userID
+		transactions[i].Merchant = normaliser.Normalise(transactions[i].Description)
+		transactions[i].TransferID = ""
+		transactions[i].Splits = nil
+		transactions[i].CategoryMatch = nil
+		if transactions[i].Category != "" {
+			transactions[i].CategoryMatch = &models.CategoryMatch{Source: models.CategorySourceManual, Category: transactions[i].Category}
+		}
 		transactions[i].InsertedAt = time.Now()
 		transactions[i].UpdatedAt = time.Now()
 	}
 
-	transactions, err := deps.Repo.BulkAddTransactions(context.Background(), userID, transactions)
+	transactions, err = deps.Repo.BulkAddTransactions(context.Background(), userID, transactions)
 	if err != nil {
 		log.Printf("Error bulk adding transactions: %v", err)
 		http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
@@ -186,7 +323,7 @@ func (deps *RouterDeps) BulkAddTransactionsHandler(w http.ResponseWriter, r *htt
 
 // UpdateTransactionHandler godoc
 // @Summary Update a transaction
-// @Description Update an existing transaction for the authenticated user
+// @Description Update an existing transaction for the authenticated user. Set the category by categoryId or by name. A new category is remembered for the transaction's merchant and given to its future transactions.
 // @Tags transactions
 // @Accept json
 // @Produce json
@@ -198,6 +335,7 @@ func (deps *RouterDeps) BulkAddTransactionsHandler(w http.ResponseWriter, r *htt
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 403 {string} string "Forbidden"
 // @Failure 404 {string} string "Transaction not found"
+// @Failure 409 {string} string "Transaction is split"
 // @Failure 500 {string} string "Failed to update transaction"
 // @Router /transactions/{id} [put]
 // @Security ApiKeyAuth
@@ -216,6 +354,68 @@ func (deps *RouterDeps) UpdateTransactionHandler(w http.ResponseWriter, r *http.
 		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
 		return
 	}
+	if updateData.Currency != nil {
+		currency, err := money.NormaliseCurrency(*updateData.Currency)
+		if err != nil {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidCurrencyMessage, err), http.StatusBadRequest)
+			return
+		}
+		updateData.Currency = &currency
+	}
+	if updateData.Description != nil {
+		normaliser, err := deps.merchantNormaliser(r.Context(), userID)
+		if err != nil {
+			log.Printf("Error listing merchant aliases: %v", err)
+			http.Error(w, exceptions.FailedToUpdateTransactionMessage, http.StatusInternalServerError)
+			return
+		}
+		merchant := normaliser.Normalise(*updateData.Description)
+		updateData.Merchant = &merchant
+	}
+	if updateData.Category != nil || updateData.CategoryID != nil {
+		// A category ID takes precedence over a name.
+		category := []models.Transaction{{}}
+		if updateData.CategoryID != nil {
+			category[0].CategoryID = *updateData.CategoryID
+		} else {
+			category[0].Category = *updateData.Category
+		}
+		if err := deps.resolveCategories(r.Context(), userID, category); err != nil {
+			if errors.Is(err, categories.ErrUnknownCategory) {
+				http.Error(w, fmt.Sprintf(exceptions.InvalidCategoryMessage, err), http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error listing categories: %v", err)
+			http.Error(w, exceptions.FailedToUpdateTransactionMessage, http.StatusInternalServerError)
+			return
+		}
+		updateData.Category = &category[0].Category
+		updateData.CategoryID = &category[0].CategoryID
+		updateData.CategoryMatch = &models.CategoryMatch{Source: models.CategorySourceManual, Category: category[0].Category}
+	}
+	if updateData.Amount != nil {
+		existing, err := deps.Repo.GetTransactionByID(r.Context(), userID, transactionID)
+		if err != nil {
+			writeTransactionError(w, err, userID, transactionID, exceptions.FailedToUpdateTransactionMessage)
+			return
+		}
+		if len(existing.Splits) > 0 && existing.Amount != *updateData.Amount {
+			http.Error(w, exceptions.TransactionIsSplitMessage, http.StatusConflict)
+			return
+		}
+	}
+	if updateData.AccountID != nil {
+		if _, err := deps.resolveAccount(r.Context(), userID, *updateData.AccountID); err != nil {
+			var notFoundErr *exceptions.AccountNotFoundError
+			if errors.As(err, &notFoundErr) {
+				http.Error(w, fmt.Sprintf(exceptions.InvalidAccountMessage, err), http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error getting account: %v", err)
+			http.Error(w, exceptions.FailedToUpdateTransactionMessage, http.StatusInternalServerError)
+			return
+		}
+	}
 
 	transaction, err := deps.Repo.UpdateTransaction(context.Background(), userID, transactionID, updateData)
 	if err != nil {
@@ -232,9 +432,12 @@ func (deps *RouterDeps) UpdateTransactionHandler(w http.ResponseWriter, r *http.
 			return
 		}
 		log.Printf("Error updating transaction: %v", err)
-		http.Error(w, fmt.Sprintf(exceptions.FailedToUpdateTransactionMessage, transaction.ID), http.StatusInternalServerError)
+		http.Error(w, exceptions.FailedToUpdateTransactionMessage, http.StatusInternalServerError)
 		return
 	}
+	if updateData.Category != nil {
+		deps.learnCategory(r.Context(), userID, *transaction)
+	}
 
 	EncodeJSONResponse(w, transaction)
 }
@@ -264,6 +467,12 @@ func (deps *RouterDeps) DeleteTransactionHandler(w http.ResponseWriter, r *http.
 
 	userID := r.Context().Value(userIDKey).(string)
 
+	transaction, err := deps.Repo.GetTransactionByID(r.Context(), userID, transactionID)
+	if err != nil {
+		writeTransactionError(w, err, userID, transactionID, exceptions.FailedToDeleteTransactionMessage)
+		return
+	}
+
 	if err := deps.Repo.DeleteTransaction(context.Background(), userID, transactionID); err != nil {
 		var forbiddenErr *exceptions.UserForbiddenError
 		if errors.As(err, &forbiddenErr) {
@@ -278,23 +487,42 @@ func (deps *RouterDeps) DeleteTransactionHandler(w http.ResponseWriter, r *http.
 			return
 		}
 		log.Printf("Error deleting transaction: %v", err)
-		http.Error(w, fmt.Sprintf(exceptions.FailedToDeleteTransactionMessage, transactionID), http.StatusInternalServerError)
+		http.Error(w, exceptions.FailedToDeleteTransactionMessage, http.StatusInternalServerError)
 		return
 	}
+	if transaction.TransferID != "" {
+		// The other side is no longer part of a transfer once this one is gone.
+		if err := deps.unlinkTransfer(r.Context(), userID, *transaction); err != nil {
+			var notFoundErr *exceptions.TransactionNotFoundError
+			if !errors.As(err, &notFoundErr) {
+				log.Printf("Error unlinking transfer: %v", err)
+			}
+		}
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
+// defaultMappingDateFormat is the date format of a column mapping given
+// without one. Worksheets' date cells are read whatever it is.
+const defaultMappingDateFormat = "DD/MM/YYYY"
+
 // ImportTransactionsHandler godoc
-// @Summary Import transactions from CSV
-// @Description Import transactions for the authenticated user from a CSV file
+// @Summary Import transactions from a statement file
+// @Description Import transactions for the authenticated user from a bank's OFX, QFX, camt.053, MT940 or CSV export, or a QIF file from other finance software, chosen by the file's content. OFX transactions keep their FITID as the bank reference. camt.053 and MT940 transactions are dated by their booking date and keep their value date, with the counterparty as the description and the remittance information alongside. QIF categories and split lines are kept, adding any categories the user doesn't have. Excel .xlsx workbooks are read like CSV files, one worksheet at a time, with date cells and Excel serial dates read as dates. The layout of a CSV file or worksheet is detected from its header row using the user's saved import profiles and the built-in ones for Monzo, Starling, Barclays, HSBC, Nationwide, Lloyds and Amex, unless a profile is named or a column mapping given. When an account is given, transactions it already has from an earlier import are skipped and counted, matching OFX FITIDs and camt.053 servicer references and otherwise the date, amount, reference and description, and balances the file states, or its end-of-day balances from a balance column, are recorded as checkpoints for reconciliation.
 // @Tags import
 // @Accept multipart/form-data
 // @Produce json
 // @Param user-id header string true "User ID"
-// @Param file formData file true "CSV file"
-// @Success 200 {array} models.Transaction
-// @Failure 400 {string} string "Failed to read file"
+// @Param file formData file true "OFX, QFX, camt.053, MT940, QIF, CSV or XLSX file"
+// @Param accountId formData string false "Account the statement belongs to"
+// @Param currency formData string false "Currency of the statement, defaults to the account's currency or the user's base currency"
+// @Param profile formData string false "Import profile to read a CSV file or workbook with, instead of detecting it"
+// @Param mapping formData string false "Unsaved import profile as JSON, in the form the import profiles endpoint takes; the name is optional and the date format defaults to DD/MM/YYYY"
+// @Param sheet formData string false "Worksheet to read from an Excel workbook; defaults to the first"
+// @Param headerRow formData int false "Row holding the column names, counting from 1; found automatically when unset"
+// @Success 200 {object} models.ImportResult
+// @Failure 400 {string} string "Failed to read file, unknown import profile or unrecognised layout"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 500 {string} string "Failed to parse csv or save transactions"
 // @Router /transactions/import [post]
@@ -309,78 +537,214 @@ func (deps *RouterDeps) ImportTransactionsHandler(w http.ResponseWriter, r *http
 
 	userID := r.Context().Value(userIDKey).(string)
 
-	transactions, err := ParseCSV(r.Context(), deps.Repo, file, userID)
+	baseCurrency, err := deps.baseCurrency(r.Context(), userID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(exceptions.FailedToReadMessage, err), http.StatusInternalServerError)
+		log.Printf("Error getting base currency: %v", err)
+		http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
 		return
 	}
-
-	transactions, err = deps.Repo.BulkAddTransactions(context.Background(), userID, transactions)
+	accountID := r.FormValue("accountId")
+	account, err := deps.resolveAccount(r.Context(), userID, accountID)
 	if err != nil {
+		var notFoundErr *exceptions.AccountNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidAccountMessage, err), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Error getting account: %v", err)
 		http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
 		return
 	}
-
-	EncodeJSONResponse(w, transactions)
-}
-func ParseCSV(ctx context.Context, repo db.Repository, r io.Reader, userID string) ([]models.Transaction, error) {
-	var transactions []models.Transaction
-	csvReader := csv.NewReader(r)
-	csvReader.FieldsPerRecord = -1
-
-	records, err := csvReader.ReadAll()
+	if account != nil {
+		baseCurrency = account.Currency
+	}
+	currency, err := normaliseCurrency(r.FormValue("currency"), baseCurrency)
 	if err != nil {
-		return []models.Transaction{}, nil
+		http.Error(w, fmt.Sprintf(exceptions.InvalidCurrencyMessage, err), http.StatusBadRequest)
+		return
 	}
 
-	for i, record := range records {
-		if i == 0 {
-			continue
+	savedProfiles, err := deps.Repo.ListImportProfiles(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing import profiles: %v", err)
+		http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+	profiles := importer.Merge(savedProfiles)
+	if name := r.FormValue("profile"); name != "" {
+		profile, ok := importer.Find(profiles, name)
+		if !ok {
+			http.Error(w, fmt.Sprintf(exceptions.UnknownImportProfileMessage, name), http.StatusBadRequest)
+			return
 		}
-
-		if len(record) < 6 {
-			continue
+		profiles = []models.ImportProfile{profile}
+	}
+	if mapping := r.FormValue("mapping"); mapping != "" {
+		// A one-off profile, for files no saved profile fits.
+		profile := models.ImportProfile{Name: "mapping", DateFormat: defaultMappingDateFormat}
+		if err := json.Unmarshal([]byte(mapping), &profile); err != nil {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidImportProfileMessage, err), http.StatusBadRequest)
+			return
+		}
+		if err := importer.ValidateProfile(&profile); err != nil {
+			http.Error(w, fmt.Sprintf(exceptions.InvalidImportProfileMessage, err), http.StatusBadRequest)
+			return
+		}
+		profiles = []models.ImportProfile{profile}
+	}
+	options := importer.Options{Sheet: r.FormValue("sheet")}
+	if headerRow := r.FormValue("headerRow"); headerRow != "" {
+		options.HeaderRow, err = strconv.Atoi(headerRow)
+		if err != nil || options.HeaderRow < 1 {
+			http.Error(w, fmt.Sprintf("invalid headerRow %q", headerRow), http.StatusBadRequest)
+			return
 		}
+	}
 
-		date, err := time.Parse("02/01/2006", record[1])
-		if err != nil {
-			continue
+	statement, err := importer.Parse(file, userID, currency, profiles, options)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.FailedToReadMessage, err), http.StatusBadRequest)
+		return
+	}
+	transactions := statement.Transactions
+	normaliser, err := deps.merchantNormaliser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing merchant aliases: %v", err)
+		http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+	if len(statement.Categories) > 0 {
+		// Files from other finance software bring their own categories.
+		pack := models.CategoryPack{Name: statement.Profile, Categories: statement.Categories}
+		if _, err := categories.Seed(r.Context(), deps.Repo, userID, pack); err != nil {
+			log.Printf("Error adding imported categories: %v", err)
+			http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
+			return
+		}
+	}
+	transactionCategoriser, err := categoriser.Load(r.Context(), deps.Repo, userID)
+	if err != nil {
+		log.Printf("Error loading categoriser: %v", err)
+		http.Error(w, exceptions.FailedToCategoriseMessage, http.StatusInternalServerError)
+		return
+	}
+	for i := range transactions {
+		transactions[i].AccountID = accountID
+		transactions[i].Merchant = normaliser.Normalise(transactions[i].Description)
+		match := transactionCategoriser.Explain(transactions[i])
+		transactions[i].Category = match.Category
+		transactions[i].CategoryMatch = &match
+	}
+	tree, err := deps.categoryTree(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		http.Error(w, exceptions.FailedToCategoriseMessage, http.StatusInternalServerError)
+		return
+	}
+	for i := range transactions {
+		tree.FillID(&transactions[i])
+		if len(transactions[i].Splits) > 0 {
+			if err := resolveSplits(tree, transactions[i].Amount, transactions[i].Splits); err != nil {
+				log.Printf("Dropping splits of imported transaction %q: %v", transactions[i].Description, err)
+				transactions[i].Splits = nil
+			}
 		}
+	}
 
-		amountFloat, err := strconv.ParseFloat(record[3], 64)
+	transactions, skipped, err := deps.notYetImported(r.Context(), userID, accountID, statement.UniqueReferences, transactions)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
+		return
+	}
+	if len(transactions) > 0 {
+		transactions, err = deps.Repo.BulkAddTransactions(context.Background(), userID, transactions)
 		if err != nil {
-			continue
+			http.Error(w, exceptions.FailedToBulkAddTransactionsMessage, http.StatusInternalServerError)
+			return
 		}
-		amount := int32(amountFloat * 100)
-		category, err := categoriser.CategoriseTransaction(ctx, repo, userID, record[5], "")
-		if err != nil {
-			category = "Other"
+	}
+
+	if account != nil {
+		checkpoints := append(statement.Checkpoints, statementCheckpoints(statement.Balances)...)
+		if err := deps.addStatementCheckpoints(r.Context(), userID, account.ID, checkpoints); err != nil {
+			// The transactions are saved; missing checkpoints only weaken
+			// reconciliation, so don't fail the import over them.
+			log.Printf("Error saving statement balances: %v", err)
 		}
+	}
+
+	if transactions == nil {
+		transactions = []models.Transaction{}
+	}
+	EncodeJSONResponse(w, models.ImportResult{Transactions: transactions, Skipped: skipped})
+}
 
-		t := models.Transaction{
-			UserID:              userID,
-			TransactionDateTime: date,
-			Description:         strings.TrimSpace(record[5]),
-			Amount:              amount,
-			Category:            category,
-			Type:                detectType(amount),
-			BankReference:       strings.TrimSpace(record[2]),
-			InsertedAt:          time.Now(),
-			UpdatedAt:           time.Now(),
+// importKey identifies a statement transaction that has no reference its
+// format promises is unique.
+type importKey struct {
+	date        string
+	amount      int64
+	reference   string
+	description string
+}
+
+func newImportKey(transaction models.Transaction) importKey {
+	return importKey{
+		date:        transaction.TransactionDateTime.UTC().Format(time.DateOnly),
+		amount:      transaction.Amount,
+		reference:   transaction.BankReference,
+		description: transaction.Description,
+	}
+}
+
+// notYetImported leaves out the transactions the account already has from an
+// earlier import, returning the rest and how many were left out. A
+// transaction whose reference is in unique is matched on that reference, and
+// any other on its date, amount, reference and description. Each transaction
+// in the account matches at most one in the file, so repeats within a
+// statement, like two identical coffees on one day, are all kept. Without an
+// account nothing is left out.
+func (deps *RouterDeps) notYetImported(ctx context.Context, userID, accountID string, unique map[string]bool, transactions []models.Transaction) ([]models.Transaction, int, error) {
+	if accountID == "" {
+		return transactions, 0, nil
+	}
+	existing, err := deps.Repo.ListTransactions(ctx, userID, map[string]string{"accountId": accountID})
+	if err != nil {
+		return nil, 0, err
+	}
+	references := make(map[string]int)
+	keys := make(map[importKey]int, len(existing))
+	for _, transaction := range existing {
+		if transaction.BankReference != "" {
+			references[transaction.BankReference]++
 		}
-		transactions = append(transactions, t)
+		keys[newImportKey(transaction)]++
 	}
 
-	return transactions, nil
+	var fresh []models.Transaction
+	skipped := 0
+	for _, transaction := range transactions {
+		if unique[transaction.BankReference] {
+			if references[transaction.BankReference] > 0 {
+				references[transaction.BankReference]--
+				skipped++
+				continue
+			}
+		} else if key := newImportKey(transaction); keys[key] > 0 {
+			keys[key]--
+			skipped++
+			continue
+		}
+		fresh = append(fresh, transaction)
+	}
+	return fresh, skipped, nil
 }
 
-func detectType(amount int32) string {
-	switch {
-	case amount < 0:
-		return "Debit"
-	case amount > 0:
-		return "Credit"
-	default:
-		return "-" // shouldn't have zero amounts
+// normaliseCurrency validates a currency code, using fallback when unset.
+func normaliseCurrency(currency, fallback string) (string, error) {
+	if currency == "" {
+		return fallback, nil
 	}
+	return money.NormaliseCurrency(currency)
 }
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"backend/internal/db"
+	"backend/internal/models"
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// importRequest builds a multipart import request for file.
+func importRequest(t *testing.T, name, file string, fields map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(file))
+	for key, value := range fields {
+		form.WriteField(key, value)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/transactions/import", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	return r
+}
+
+func serveImport(t *testing.T, deps *RouterDeps, name, file string, fields map[string]string) models.ImportResult {
+	t.Helper()
+	var result models.ImportResult
+	decode(t, serveRequest(deps.ImportTransactionsHandler, importRequest(t, name, file, fields)), &result)
+	return result
+}
+
+func TestImportTransactionsHandler(t *testing.T) {
+	deps, repo := newTestDeps(t)
+	groceries := addCategory(t, repo, "Groceries", "tesco")
+	account := addAccount(t, repo, "Current")
+
+	csv := "02/06/2025,TESCO STORES 3021,-23.45\n03/06/2025,ACME LTD SALARY,2500.00\n"
+	transactions := serveImport(t, deps, "statement.csv", csv, map[string]string{"accountId": account.ID}).Transactions
+	if len(transactions) != 2 {
+		t.Fatalf("imported %d transactions, want 2", len(transactions))
+	}
+	tesco := transactions[0]
+	if tesco.ID == "" || tesco.Amount != -2345 || tesco.AccountID != account.ID || tesco.Category != "Groceries" || tesco.CategoryID != groceries.ID {
+		t.Errorf("transactions[0] = %+v, want a saved Groceries transaction in the account", tesco)
+	}
+	if saved := getTransaction(t, repo, tesco.ID); saved.CategoryID != groceries.ID || saved.CategoryMatch == nil {
+		t.Errorf("saved transaction = %+v, want its category and why it got it", saved)
+	}
+
+	unrecognised := "When,What,How much\n02/06/2025,TESCO,-1.00\n"
+	if w := serveRequest(deps.ImportTransactionsHandler, importRequest(t, "statement.csv", unrecognised, nil)); w.Code != http.StatusBadRequest {
+		t.Errorf("import of an unrecognised layout = %d, want 400", w.Code)
+	}
+	mapping := `{"columns":{"date":"When","description":"What","amount":"How much"}}`
+	mapped := serveImport(t, deps, "statement.csv", unrecognised, map[string]string{"mapping": mapping}).Transactions
+	if len(mapped) != 1 || mapped[0].Amount != -100 {
+		t.Errorf("import with a column mapping = %+v, want the one row", mapped)
+	}
+
+	all, err := repo.ListTransactions(context.Background(), testUserID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("repository has %d transactions, want 3", len(all))
+	}
+}
+
+func TestImportSkipsImportedTransactions(t *testing.T) {
+	deps, repo := newTestDeps(t)
+	current := addAccount(t, repo, "Current")
+	savings := addAccount(t, repo, "Savings")
+
+	fields := map[string]string{"accountId": current.ID, "profile": "starling"}
+	// Starling's references are free text that repeats, and whole rows can
+	// repeat within a statement.
+	csv := "Date,Counter Party,Reference,Amount (GBP),Balance (GBP)\n" +
+		"01/05/2025,J SMITH,Rent,-950.00,1050.00\n" +
+		"01/06/2025,J SMITH,Rent,-950.00,100.00\n" +
+		"02/06/2025,PRET A MANGER,,-4.10,95.90\n" +
+		"02/06/2025,PRET A MANGER,,-4.10,91.80\n"
+	if first := serveImport(t, deps, "statement.csv", csv, fields); len(first.Transactions) != 4 || first.Skipped != 0 {
+		t.Fatalf("first import = %d added, %d skipped, want every row added", len(first.Transactions), first.Skipped)
+	}
+	if again := serveImport(t, deps, "statement.csv", csv, fields); len(again.Transactions) != 0 || again.Skipped != 4 {
+		t.Errorf("second import of the same file = %+v, want all 4 skipped", again)
+	}
+
+	// An overlapping statement adds only what is new.
+	overlapping := csv + "02/06/2025,PRET A MANGER,,-4.10,87.70\n01/07/2025,J SMITH,Rent,-950.00,-862.30\n"
+	if added := serveImport(t, deps, "statement.csv", overlapping, fields); len(added.Transactions) != 2 || added.Skipped != 4 {
+		t.Errorf("overlapping import = %+v, want July's rent and the third coffee", added)
+	}
+
+	// OFX FITIDs are matched however the description is written.
+	ofx := "OFXHEADER:100\n<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>GBP<BANKTRANLIST>" +
+		"<STMTTRN><DTPOSTED>20250603<TRNAMT>-23.45<FITID>202506030001<NAME>TESCO STORES 3021</STMTTRN>" +
+		"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+	delete(fields, "profile")
+	serveImport(t, deps, "statement.ofx", ofx, fields)
+	renamed := strings.Replace(ofx, "TESCO STORES 3021", "TESCO STORES", 1)
+	if again := serveImport(t, deps, "statement.ofx", renamed, fields); len(again.Transactions) != 0 || again.Skipped != 1 {
+		t.Errorf("reimport of an OFX transaction = %+v, want it skipped by FITID", again)
+	}
+
+	// The same rows in another account are different transactions.
+	fields["accountId"] = savings.ID
+	if other := serveImport(t, deps, "statement.ofx", ofx, fields); len(other.Transactions) != 1 || other.Skipped != 0 {
+		t.Errorf("import into another account = %+v, want it added", other)
+	}
+
+	all, err := repo.ListTransactions(context.Background(), testUserID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 8 {
+		t.Errorf("repository has %d transactions, want 8", len(all))
+	}
+}
+
+// failingDeletes is a repository whose deletes fail with a driver error.
+type failingDeletes struct {
+	db.Repository
+}
+
+func (failingDeletes) DeleteTransaction(ctx context.Context, userID, transactionID string) error {
+	return errors.New("dial tcp 10.0.0.5:5432: connection refused")
+}
+
+func TestStorageErrorsAreNotSentToClients(t *testing.T) {
+	deps, repo := newTestDeps(t)
+	transaction := addTransaction(t, repo, models.Transaction{Description: "TESCO", Amount: -100})
+	deps.Repo = failingDeletes{Repository: repo}
+
+	w := serve(deps.DeleteTransactionHandler, http.MethodDelete, "/transactions/"+transaction.ID, nil, map[string]string{"id": transaction.ID})
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("delete = %d, want 500", w.Code)
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != "failed to delete transaction" {
+		t.Errorf("body = %q, want only the fixed message", body)
+	}
+}
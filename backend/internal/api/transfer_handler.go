@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"backend/internal/transfers"
+)
+
+// LinkTransferRequest names the two transactions that make up a transfer, in
+// either order.
+type LinkTransferRequest struct {
+	TransactionIDs []string `json:"transactionIds"`
+}
+
+// ListTransfersHandler godoc
+// @Summary List transfers
+// @Description List the linked transfers between the authenticated user's accounts
+// @Tags transfers
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Success 200 {array} models.TransferPair
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to list transfers"
+// @Router /transfers [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) ListTransfersHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, nil)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToListTransfersMessage, http.StatusInternalServerError)
+		return
+	}
+
+	byID := make(map[string]models.Transaction, len(transactions))
+	for _, transaction := range transactions {
+		byID[transaction.ID] = transaction
+	}
+	pairs := []models.TransferPair{}
+	for _, transaction := range transactions {
+		if transaction.TransferID == "" || transaction.Amount >= 0 {
+			continue
+		}
+		if credit, ok := byID[transaction.TransferID]; ok {
+			pairs = append(pairs, models.TransferPair{Debit: transaction, Credit: credit})
+		}
+	}
+
+	EncodeJSONResponse(w, pairs)
+}
+
+// DetectTransfersHandler godoc
+// @Summary Detect transfers
+// @Description Pair unlinked transactions in different accounts that have equal and opposite amounts within a date window, and link them unless dryRun is set
+// @Tags transfers
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param windowDays query int false "Maximum days between the two sides, defaults to 3"
+// @Param dryRun query bool false "Only report the pairs that would be linked"
+// @Success 200 {array} models.TransferPair
+// @Failure 400 {string} string "Invalid query parameters"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Failed to link transfer"
+// @Router /transfers/detect [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) DetectTransfersHandler(w http.ResponseWriter, r *http.Request) {
+	window := transfers.DefaultWindow
+	if value := r.URL.Query().Get("windowDays"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			http.Error(w, fmt.Sprintf("invalid windowDays %q", value), http.StatusBadRequest)
+			return
+		}
+		window = time.Duration(days) * 24 * time.Hour
+	}
+	dryRun, err := parseBoolParam(r, "dryRun")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	transactions, err := deps.Repo.ListTransactions(r.Context(), userID, nil)
+	if err != nil {
+		log.Printf("Error listing transactions: %v", err)
+		http.Error(w, exceptions.FailedToLinkTransferMessage, http.StatusInternalServerError)
+		return
+	}
+
+	pairs := transfers.Detect(transactions, window)
+	if !dryRun {
+		for i, pair := range pairs {
+			linked, err := deps.linkTransfer(r.Context(), userID, pair.Debit, pair.Credit)
+			if err != nil {
+				log.Printf("Error linking transfer: %v", err)
+				http.Error(w, exceptions.FailedToLinkTransferMessage, http.StatusInternalServerError)
+				return
+			}
+			pairs[i] = *linked
+		}
+	}
+
+	EncodeJSONResponse(w, pairs)
+}
+
+// LinkTransferHandler godoc
+// @Summary Link a transfer
+// @Description Mark two transactions with equal and opposite amounts as the two sides of a transfer
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param transfer body LinkTransferRequest true "Transactions to link"
+// @Success 200 {object} models.TransferPair
+// @Failure 400 {string} string "Invalid request body or transactions cannot be linked"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Transaction not found"
+// @Failure 500 {string} string "Failed to link transfer"
+// @Router /transfers [post]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) LinkTransferHandler(w http.ResponseWriter, r *http.Request) {
+	var request LinkTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	if len(request.TransactionIDs) != 2 {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, "transactionIds must name exactly two transactions"), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Context().Value(userIDKey).(string)
+
+	sides := make([]models.Transaction, 0, 2)
+	for _, transactionID := range request.TransactionIDs {
+		transaction, err := deps.Repo.GetTransactionByID(r.Context(), userID, transactionID)
+		if err != nil {
+			writeTransactionError(w, err, userID, transactionID, exceptions.FailedToLinkTransferMessage)
+			return
+		}
+		sides = append(sides, *transaction)
+	}
+	if err := transfers.Validate(sides[0], sides[1]); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	debit, credit := sides[0], sides[1]
+	if debit.Amount > 0 {
+		debit, credit = credit, debit
+	}
+	pair, err := deps.linkTransfer(r.Context(), userID, debit, credit)
+	if err != nil {
+		log.Printf("Error linking transfer: %v", err)
+		http.Error(w, exceptions.FailedToLinkTransferMessage, http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, pair)
+}
+
+// UnlinkTransferHandler godoc
+// @Summary Unlink a transfer
+// @Description Unlink the transfer that a transaction belongs to, so both sides count as spending and income again
+// @Tags transfers
+// @Param user-id header string true "User ID"
+// @Param id path string true "ID of either transaction in the transfer"
+// @Success 204 {string} string "No Content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Transaction not found or not part of a transfer"
+// @Failure 500 {string} string "Failed to unlink transfer"
+// @Router /transfers/{id} [delete]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) UnlinkTransferHandler(w http.ResponseWriter, r *http.Request) {
+	transactionID := mux.Vars(r)["id"]
+	userID := r.Context().Value(userIDKey).(string)
+
+	transaction, err := deps.Repo.GetTransactionByID(r.Context(), userID, transactionID)
+	if err != nil {
+		writeTransactionError(w, err, userID, transactionID, exceptions.FailedToUnlinkTransferMessage)
+		return
+	}
+	if transaction.TransferID == "" {
+		http.Error(w, exceptions.TransferNotFoundMessage, http.StatusNotFound)
+		return
+	}
+
+	if err := deps.unlinkTransfer(r.Context(), userID, *transaction); err != nil {
+		log.Printf("Error unlinking transfer: %v", err)
+		http.Error(w, exceptions.FailedToUnlinkTransferMessage, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// linkTransfer points debit and credit at each other. If the credit can't be
+// linked the debit is unlinked again, so that it isn't left out of spending
+// on its own.
+func (deps *RouterDeps) linkTransfer(ctx context.Context, userID string, debit, credit models.Transaction) (*models.TransferPair, error) {
+	linkedDebit, err := deps.Repo.UpdateTransaction(ctx, userID, debit.ID, models.TransactionUpdate{TransferID: &credit.ID})
+	if err != nil {
+		return nil, err
+	}
+	linkedCredit, err := deps.Repo.UpdateTransaction(ctx, userID, credit.ID, models.TransactionUpdate{TransferID: &debit.ID})
+	if err != nil {
+		if _, rollbackErr := deps.Repo.UpdateTransaction(ctx, userID, debit.ID, models.TransactionUpdate{TransferID: &debit.TransferID}); rollbackErr != nil {
+			return nil, fmt.Errorf("%w; unlinking the debit again also failed: %v", err, rollbackErr)
+		}
+		return nil, err
+	}
+	return &models.TransferPair{Debit: *linkedDebit, Credit: *linkedCredit}, nil
+}
+
+// unlinkTransfer clears both sides of the transfer transaction belongs to.
+// The counterpart goes first, and is skipped if it has already been deleted,
+// so this also tidies up after transaction itself is deleted.
+func (deps *RouterDeps) unlinkTransfer(ctx context.Context, userID string, transaction models.Transaction) error {
+	unlinked := ""
+	if _, err := deps.Repo.UpdateTransaction(ctx, userID, transaction.TransferID, models.TransactionUpdate{TransferID: &unlinked}); err != nil {
+		var notFoundErr *exceptions.TransactionNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			return err
+		}
+	}
+	_, err := deps.Repo.UpdateTransaction(ctx, userID, transaction.ID, models.TransactionUpdate{TransferID: &unlinked})
+	return err
+}
+
+// writeTransactionError maps repository errors for a single transaction to
+// the responses the transaction handlers use.
+func writeTransactionError(w http.ResponseWriter, err error, userID, transactionID, failedMessage string) {
+	var forbiddenErr *exceptions.UserForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		log.Print(exceptions.UserForbidden(userID))
+		http.Error(w, "", http.StatusForbidden)
+		return
+	}
+	var notFoundErr *exceptions.TransactionNotFoundError
+	if errors.As(err, &notFoundErr) {
+		log.Print(exceptions.TransactionNotFound(transactionID))
+		http.Error(w, exceptions.TransactionNotFoundMessage, http.StatusNotFound)
+		return
+	}
+	log.Printf("Error getting transaction: %v", err)
+	http.Error(w, failedMessage, http.StatusInternalServerError)
+}
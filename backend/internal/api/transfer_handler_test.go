@@ -0,0 +1,73 @@
+package api
+
+import (
+	"backend/internal/db"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestLinkAndUnlinkTransfer(t *testing.T) {
+	deps, repo := newTestDeps(t)
+	current := addAccount(t, repo, "Current")
+	savings := addAccount(t, repo, "Savings")
+	out := addTransaction(t, repo, models.Transaction{AccountID: current.ID, Description: "To savings", Amount: -5000})
+	in := addTransaction(t, repo, models.Transaction{AccountID: savings.ID, Description: "From current", Amount: 5000})
+	other := addTransaction(t, repo, models.Transaction{AccountID: savings.ID, Description: "Refund", Amount: 1000})
+
+	request := LinkTransferRequest{TransactionIDs: []string{in.ID, out.ID}}
+	var pair models.TransferPair
+	decode(t, serveJSON(t, deps.LinkTransferHandler, http.MethodPost, "/transfers", request, nil), &pair)
+	if pair.Debit.ID != out.ID || pair.Credit.ID != in.ID {
+		t.Errorf("linked %s to %s, want the debit %s first", pair.Debit.ID, pair.Credit.ID, out.ID)
+	}
+	if debit, credit := getTransaction(t, repo, out.ID), getTransaction(t, repo, in.ID); debit.TransferID != in.ID || credit.TransferID != out.ID {
+		t.Errorf("TransferIDs = %q, %q, want each side pointing at the other", debit.TransferID, credit.TransferID)
+	}
+
+	mismatched := LinkTransferRequest{TransactionIDs: []string{out.ID, other.ID}}
+	if w := serveJSON(t, deps.LinkTransferHandler, http.MethodPost, "/transfers", mismatched, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("link of unequal amounts = %d, want 400", w.Code)
+	}
+
+	vars := map[string]string{"id": in.ID}
+	if w := serve(deps.UnlinkTransferHandler, http.MethodDelete, "/transfers/"+in.ID, nil, vars); w.Code != http.StatusNoContent {
+		t.Fatalf("unlink = %d, want 204: %s", w.Code, w.Body.String())
+	}
+	if debit, credit := getTransaction(t, repo, out.ID), getTransaction(t, repo, in.ID); debit.TransferID != "" || credit.TransferID != "" {
+		t.Errorf("TransferIDs after unlinking = %q, %q, want both cleared", debit.TransferID, credit.TransferID)
+	}
+	if w := serve(deps.UnlinkTransferHandler, http.MethodDelete, "/transfers/"+in.ID, nil, vars); w.Code != http.StatusNotFound {
+		t.Errorf("unlink of an unlinked transaction = %d, want 404", w.Code)
+	}
+}
+
+// failingUpdates is a repository whose updates to one transaction fail.
+type failingUpdates struct {
+	db.Repository
+	transactionID string
+}
+
+func (r failingUpdates) UpdateTransaction(ctx context.Context, userID, transactionID string, updateData models.TransactionUpdate) (*models.Transaction, error) {
+	if transactionID == r.transactionID {
+		return nil, errors.New("update failed")
+	}
+	return r.Repository.UpdateTransaction(ctx, userID, transactionID, updateData)
+}
+
+func TestLinkTransferRollsBackDebit(t *testing.T) {
+	deps, repo := newTestDeps(t)
+	out := addTransaction(t, repo, models.Transaction{AccountID: "current", Amount: -5000})
+	in := addTransaction(t, repo, models.Transaction{AccountID: "savings", Amount: 5000})
+	deps.Repo = failingUpdates{Repository: repo, transactionID: in.ID}
+
+	request := LinkTransferRequest{TransactionIDs: []string{out.ID, in.ID}}
+	if w := serveJSON(t, deps.LinkTransferHandler, http.MethodPost, "/transfers", request, nil); w.Code != http.StatusInternalServerError {
+		t.Fatalf("link = %d, want 500", w.Code)
+	}
+	if debit := getTransaction(t, repo, out.ID); debit.TransferID != "" {
+		t.Errorf("debit TransferID = %q after the credit failed to link, want it unlinked again", debit.TransferID)
+	}
+}
@@ -1,10 +1,15 @@
 package api
 
 import (
+	"backend/internal/categories"
 	"backend/internal/db"
+	"backend/internal/exceptions"
 	"backend/internal/models"
+	"backend/internal/money"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 
@@ -12,12 +17,18 @@ import (
 )
 
 type SetupUserProfileRequest struct {
-	UID   string `json:"uid"`
-	Email string `json:"email"`
+	UID          string `json:"uid"`
+	Email        string `json:"email"`
+	BaseCurrency string `json:"baseCurrency,omitempty"`
+	// CategoryPack names the pack to seed categories from, defaulting to
+	// the configured one.
+	CategoryPack string `json:"categoryPack,omitempty"`
 }
 
 type SetupUserProfileDeps struct {
-	Repo db.Repository
+	Repo        db.Repository
+	Packs       *categories.Packs
+	DefaultPack string
 }
 
 func (deps *SetupUserProfileDeps) SetupUserProfileHandler(w http.ResponseWriter, r *http.Request) {
@@ -33,15 +44,33 @@ func (deps *SetupUserProfileDeps) SetupUserProfileHandler(w http.ResponseWriter,
 		return
 	}
 
+	baseCurrency, err := normaliseCurrency(req.BaseCurrency, money.DefaultCurrency)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(exceptions.InvalidCurrencyMessage, err)))
+		return
+	}
+	packName := req.CategoryPack
+	if packName == "" {
+		packName = deps.DefaultPack
+	}
+	pack, ok := deps.Packs.Get(packName)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(exceptions.UnknownCategoryPackMessage, packName)))
+		return
+	}
+
 	log.Printf("Setting up profile for user: %s", req.Email)
 
 	ctx := context.Background()
 
 	userData := map[string]any{
-		"email":     req.Email,
-		"createdAt": firestore.ServerTimestamp,
+		"email":        req.Email,
+		"baseCurrency": baseCurrency,
+		"createdAt":    firestore.ServerTimestamp,
 	}
-	err := deps.Repo.SeedNewUser(ctx, req.UID, userData)
+	err = deps.Repo.SeedNewUser(ctx, req.UID, userData)
 	if err != nil {
 		log.Printf("Failed to create user document for user %s: %v", req.UID, err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -49,28 +78,105 @@ func (deps *SetupUserProfileDeps) SetupUserProfileHandler(w http.ResponseWriter,
 		return
 	}
 
-	// Seed default categories
-	defaultCategories := []models.UserCategory{
-		{Name: "Groceries", Keywords: []string{"food", "supermarket", "grocery"}},
-		{Name: "Rent", Keywords: []string{"rent", "apartment", "housing"}},
-		{Name: "Utilities", Keywords: []string{"electricity", "water", "gas", "utility"}},
-		{Name: "Transport", Keywords: []string{"bus", "train", "uber", "taxi", "transport"}},
-		{Name: "Entertainment", Keywords: []string{"movie", "cinema", "netflix", "entertainment"}},
-		{Name: "Savings", Keywords: []string{"savings", "deposit", "bank"}},
-		{Name: "Health", Keywords: []string{"doctor", "pharmacy", "health"}},
-		{Name: "Other", Keywords: []string{}},
+	// Seeding merges, so setting up the same user again adds no duplicates.
+	if _, err := categories.Seed(ctx, deps.Repo, req.UID, pack); err != nil {
+		log.Printf("Failed to seed categories from pack %s for user %s: %v", pack.Name, req.UID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Failed to seed categories"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("User profile and categories seeded"))
+}
+
+// GetUserHandler godoc
+// @Summary Get the user profile
+// @Description Get the profile of the authenticated user, including their base currency
+// @Tags user
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Success 200 {object} models.User
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "User not found"
+// @Failure 500 {string} string "Failed to get user"
+// @Router /user [get]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) GetUserHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+
+	user, err := deps.Repo.GetUser(r.Context(), userID)
+	if err != nil {
+		var notFoundErr *exceptions.UserNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.UserNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error getting user: %v", err)
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
 	}
 
-	for _, cat := range defaultCategories {
-		_, err := deps.Repo.AddUserCategory(ctx, req.UID, cat)
+	EncodeJSONResponse(w, user)
+}
+
+// UpdateUserHandler godoc
+// @Summary Update the user profile
+// @Description Update the authenticated user's name or base currency
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param user-id header string true "User ID"
+// @Param user body models.UserUpdate true "Profile update data"
+// @Success 200 {object} models.User
+// @Failure 400 {string} string "Invalid request body or currency"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "User not found"
+// @Failure 500 {string} string "Failed to update user"
+// @Router /user [patch]
+// @Security ApiKeyAuth
+func (deps *RouterDeps) UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
+	var updateData models.UserUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		http.Error(w, fmt.Sprintf(exceptions.InvalidRequestBodyMessage, err), http.StatusBadRequest)
+		return
+	}
+	if updateData.BaseCurrency != nil {
+		currency, err := money.NormaliseCurrency(*updateData.BaseCurrency)
 		if err != nil {
-			log.Printf("Failed to add category %s for user %s: %v", cat.Name, req.UID, err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Failed to seed categories"))
+			http.Error(w, fmt.Sprintf(exceptions.InvalidCurrencyMessage, err), http.StatusBadRequest)
 			return
 		}
+		updateData.BaseCurrency = &currency
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("User profile and categories seeded"))
+	userID := r.Context().Value(userIDKey).(string)
+
+	user, err := deps.Repo.UpdateUser(r.Context(), userID, updateData)
+	if err != nil {
+		var notFoundErr *exceptions.UserNotFoundError
+		if errors.As(err, &notFoundErr) {
+			http.Error(w, exceptions.UserNotFoundMessage, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error updating user: %v", err)
+		http.Error(w, "Failed to update user", http.StatusInternalServerError)
+		return
+	}
+
+	EncodeJSONResponse(w, user)
+}
+
+// baseCurrency returns the user's base currency, falling back to the default
+// for users without a profile.
+func (deps *RouterDeps) baseCurrency(ctx context.Context, userID string) (string, error) {
+	user, err := deps.Repo.GetUser(ctx, userID)
+	if err != nil {
+		var notFoundErr *exceptions.UserNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return money.DefaultCurrency, nil
+		}
+		return "", err
+	}
+	return user.BaseCurrency, nil
 }
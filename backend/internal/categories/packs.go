@@ -0,0 +1,106 @@
+package categories
+
+import (
+	"backend/internal/models"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed packs/*.json
+var packFiles embed.FS
+
+// Packs holds the category packs available to seed from.
+type Packs struct {
+	byName map[string]models.CategoryPack
+}
+
+// LoadPacks loads the built-in packs and then any *.json packs in dir, which
+// replace built-in packs of the same name. An empty dir loads only the
+// built-in packs.
+func LoadPacks(dir string) (*Packs, error) {
+	p := &Packs{byName: make(map[string]models.CategoryPack)}
+	if err := p.addFiles(packFiles, "packs"); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := p.addFiles(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *Packs) addFiles(fsys fs.FS, dir string) error {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		file, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		pack, err := ParsePack(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("category pack %s: %w", name, err)
+		}
+		p.byName[pack.Name] = pack
+	}
+	return nil
+}
+
+// ParsePack reads a pack from JSON and checks that its name is set and its
+// category names are present and unique. The name is lowercased.
+func ParsePack(r io.Reader) (models.CategoryPack, error) {
+	var pack models.CategoryPack
+	if err := json.NewDecoder(r).Decode(&pack); err != nil {
+		return pack, err
+	}
+	pack.Name = strings.ToLower(strings.TrimSpace(pack.Name))
+	if pack.Name == "" {
+		return pack, errors.New("name is required")
+	}
+	seen := make(map[string]bool)
+	var check func([]models.PackCategory) error
+	check = func(categories []models.PackCategory) error {
+		for _, category := range categories {
+			if strings.TrimSpace(category.Name) == "" {
+				return errors.New("category name is required")
+			}
+			if seen[nameKey(category.Name)] {
+				return fmt.Errorf("%w: %s", ErrDuplicateName, category.Name)
+			}
+			seen[nameKey(category.Name)] = true
+			if err := check(category.Subcategories); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return pack, check(pack.Categories)
+}
+
+// Get returns the pack called name, ignoring case.
+func (p *Packs) Get(name string) (models.CategoryPack, bool) {
+	pack, ok := p.byName[strings.ToLower(strings.TrimSpace(name))]
+	return pack, ok
+}
+
+// List returns the packs in name order.
+func (p *Packs) List() []models.CategoryPack {
+	packs := make([]models.CategoryPack, 0, len(p.byName))
+	for _, pack := range p.byName {
+		packs = append(packs, pack)
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Name < packs[j].Name })
+	return packs
+}
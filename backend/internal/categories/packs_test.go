@@ -0,0 +1,139 @@
+package categories
+
+import (
+	"backend/internal/db"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltInPacks(t *testing.T) {
+	packs, err := LoadPacks("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := packs.List()
+	if len(list) != 2 || list[0].Name != "uk" || list[1].Name != "us" {
+		t.Fatalf("List() = %+v, want the uk and us packs", list)
+	}
+	for _, pack := range list {
+		var hasOther bool
+		for _, category := range pack.Categories {
+			hasOther = hasOther || category.Name == "Other"
+		}
+		if !hasOther {
+			t.Errorf("pack %s has no Other category for uncategorised transactions", pack.Name)
+		}
+	}
+	if _, ok := packs.Get(" UK "); !ok {
+		t.Error("Get(UK) not found, want a case-insensitive match")
+	}
+}
+
+func TestLoadPacksFromDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "uk.json"), `{"name": "UK", "categories": [{"name": "Everything"}]}`)
+	writeFile(t, filepath.Join(dir, "student.json"), `{"name": "student", "categories": [{"name": "Books"}]}`)
+	writeFile(t, filepath.Join(dir, "notes.txt"), `not a pack`)
+
+	packs, err := LoadPacks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uk, _ := packs.Get("uk"); len(uk.Categories) != 1 || uk.Categories[0].Name != "Everything" {
+		t.Errorf("uk = %+v, want the pack from the directory to replace the built-in one", uk)
+	}
+	if len(packs.List()) != 3 {
+		t.Errorf("List() = %+v, want uk, us and student", packs.List())
+	}
+
+	writeFile(t, filepath.Join(dir, "broken.json"), `{"name": "broken", "categories": [{"name": "A"}, {"name": "a"}]}`)
+	if _, err := LoadPacks(dir); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf("LoadPacks with duplicate names = %v, want ErrDuplicateName", err)
+	}
+}
+
+func TestParsePackNeedsName(t *testing.T) {
+	if _, err := ParsePack(strings.NewReader(`{"categories": [{"name": "A"}]}`)); err == nil {
+		t.Error("ParsePack without a name succeeded")
+	}
+	if _, err := ParsePack(strings.NewReader(`{"name": "x", "categories": [{"name": " "}]}`)); err == nil {
+		t.Error("ParsePack with a blank category name succeeded")
+	}
+}
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	repo := db.NewMemoryRepository()
+	userID := "user"
+	pack := models.CategoryPack{Name: "test", Categories: []models.PackCategory{
+		{Name: "Food", Subcategories: []models.PackCategory{
+			{Name: "Groceries", Keywords: []string{"tesco", "aldi"}},
+		}},
+		{Name: "Other"},
+	}}
+
+	// The user already has Groceries at the top level, with one keyword.
+	if _, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "groceries", Keywords: []string{"Tesco", "corner shop"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Seed(ctx, repo, userID, pack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(result.Added, ",") != "Food,Other" || strings.Join(result.Updated, ",") != "groceries" {
+		t.Errorf("Seed = %+v, want Food and Other added and groceries updated", result)
+	}
+	tree := mustTree(t, repo, userID)
+	groceries, _ := tree.ByName("Groceries")
+	if groceries.ParentID != "" || strings.Join(groceries.Keywords, ",") != "Tesco,corner shop,aldi" {
+		t.Errorf("groceries = %+v, want its place kept and aldi added", groceries)
+	}
+
+	again, err := Seed(ctx, repo, userID, pack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Added) != 0 || len(again.Updated) != 0 || len(mustTree(t, repo, userID).Categories()) != 3 {
+		t.Errorf("Seed again = %+v, want nothing changed", again)
+	}
+}
+
+func TestSeedNewUserBuildsHierarchy(t *testing.T) {
+	ctx := context.Background()
+	repo := db.NewMemoryRepository()
+	packs, err := LoadPacks("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uk, _ := packs.Get("uk")
+	if _, err := Seed(ctx, repo, "user", uk); err != nil {
+		t.Fatal(err)
+	}
+	tree := mustTree(t, repo, "user")
+	groceries, ok := tree.ByName("Groceries")
+	if !ok || tree.Path(groceries.ID) != "Food > Groceries" {
+		t.Errorf("Groceries path = %q, want Food > Groceries", tree.Path(groceries.ID))
+	}
+}
+
+func mustTree(t *testing.T, repo db.Repository, userID string) *Tree {
+	t.Helper()
+	categories, err := repo.ListUserCategories(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewTree(categories)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
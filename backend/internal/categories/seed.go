@@ -0,0 +1,81 @@
+package categories
+
+import (
+	"backend/internal/models"
+	"context"
+	"strings"
+)
+
+// CategoryStore is the part of the repository that seeding needs.
+type CategoryStore interface {
+	ListUserCategories(ctx context.Context, userID string) ([]models.UserCategory, error)
+	AddUserCategory(ctx context.Context, userID string, category models.UserCategory) (string, error)
+	UpdateUserCategory(ctx context.Context, userID, categoryID string, category models.UserCategory) (int, error)
+}
+
+// Seed merges pack into the user's categories. Categories are matched by
+// name, ignoring case: missing ones are added under their pack parent, and
+// existing ones keep their place in the hierarchy but gain any keywords they
+// lack. Seeding the same pack twice changes nothing the second time.
+func Seed(ctx context.Context, store CategoryStore, userID string, pack models.CategoryPack) (*models.CategorySeedResult, error) {
+	existing, err := store.ListUserCategories(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tree := NewTree(existing)
+	result := &models.CategorySeedResult{Pack: pack.Name, Added: []string{}, Updated: []string{}}
+
+	var seed func(categories []models.PackCategory, parentID string) error
+	seed = func(categories []models.PackCategory, parentID string) error {
+		for _, category := range categories {
+			id, err := seedOne(ctx, store, userID, tree, category, parentID, result)
+			if err != nil {
+				return err
+			}
+			if err := seed(category.Subcategories, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := seed(pack.Categories, ""); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// seedOne adds or updates a single pack category and returns its ID.
+func seedOne(ctx context.Context, store CategoryStore, userID string, tree *Tree, category models.PackCategory, parentID string, result *models.CategorySeedResult) (string, error) {
+	current, ok := tree.ByName(category.Name)
+	if !ok {
+		keywords := category.Keywords
+		if keywords == nil {
+			keywords = []string{}
+		}
+		id, err := store.AddUserCategory(ctx, userID, models.UserCategory{Name: category.Name, ParentID: parentID, Keywords: keywords})
+		if err != nil {
+			return "", err
+		}
+		result.Added = append(result.Added, category.Name)
+		return id, nil
+	}
+
+	have := make(map[string]bool, len(current.Keywords))
+	for _, keyword := range current.Keywords {
+		have[strings.ToLower(strings.TrimSpace(keyword))] = true
+	}
+	keywords := append([]string{}, current.Keywords...)
+	for _, keyword := range category.Keywords {
+		if !have[strings.ToLower(strings.TrimSpace(keyword))] {
+			keywords = append(keywords, keyword)
+		}
+	}
+	if len(keywords) > len(current.Keywords) {
+		current.Keywords = keywords
+		if _, err := store.UpdateUserCategory(ctx, userID, current.ID, current); err != nil {
+			return "", err
+		}
+		result.Updated = append(result.Updated, current.Name)
+	}
+	return current.ID, nil
+}
@@ -0,0 +1,189 @@
+// Package categories resolves a user's categories by ID and name and works
+// with their parent/child hierarchy.
+package categories
+
+import (
+	"backend/internal/models"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PathSeparator joins the names in a category's path, as in "Food > Groceries".
+const PathSeparator = " > "
+
+var (
+	ErrUnknownCategory = errors.New("unknown category")
+	ErrDuplicateName   = errors.New("a category with this name already exists")
+	ErrCycle           = errors.New("a category cannot be its own ancestor")
+)
+
+// Tree indexes a user's categories.
+type Tree struct {
+	byID     map[string]models.UserCategory
+	byName   map[string]models.UserCategory
+	children map[string][]string
+	ordered  []models.UserCategory
+}
+
+// NewTree indexes categories. Names are matched without regard to case.
+func NewTree(categories []models.UserCategory) *Tree {
+	t := &Tree{
+		byID:     make(map[string]models.UserCategory, len(categories)),
+		byName:   make(map[string]models.UserCategory, len(categories)),
+		children: make(map[string][]string),
+		ordered:  categories,
+	}
+	for _, category := range categories {
+		t.byID[category.ID] = category
+		if _, exists := t.byName[nameKey(category.Name)]; !exists {
+			t.byName[nameKey(category.Name)] = category
+		}
+		if category.ParentID != "" {
+			t.children[category.ParentID] = append(t.children[category.ParentID], category.ID)
+		}
+	}
+	return t
+}
+
+// Categories returns the indexed categories in their original order.
+func (t *Tree) Categories() []models.UserCategory {
+	return t.ordered
+}
+
+func (t *Tree) ByID(id string) (models.UserCategory, bool) {
+	category, ok := t.byID[id]
+	return category, ok
+}
+
+func (t *Tree) ByName(name string) (models.UserCategory, bool) {
+	category, ok := t.byName[nameKey(name)]
+	return category, ok
+}
+
+// Lookup finds a category by ID or, when id is empty, by name.
+func (t *Tree) Lookup(id, name string) (models.UserCategory, bool) {
+	if id != "" {
+		return t.ByID(id)
+	}
+	return t.ByName(name)
+}
+
+// Resolve returns the current ID and name of the category recorded with id
+// and name, matching by name records made before categories had IDs. A
+// category that no longer exists is returned as recorded.
+func (t *Tree) Resolve(id, name string) (string, string) {
+	if category, ok := t.Lookup(id, name); ok {
+		return category.ID, category.Name
+	}
+	return id, name
+}
+
+// Children returns the IDs of the direct subcategories of id.
+func (t *Tree) Children(id string) []string {
+	return t.children[id]
+}
+
+// Ancestors returns the chain of parents of id, nearest first. A parent that
+// no longer exists ends the chain.
+func (t *Tree) Ancestors(id string) []models.UserCategory {
+	var ancestors []models.UserCategory
+	seen := map[string]bool{id: true}
+	category, ok := t.byID[id]
+	for ok && category.ParentID != "" && !seen[category.ParentID] {
+		seen[category.ParentID] = true
+		if category, ok = t.byID[category.ParentID]; ok {
+			ancestors = append(ancestors, category)
+		}
+	}
+	return ancestors
+}
+
+// Path returns the full name of id from its top-level ancestor down, such as
+// "Food > Groceries".
+func (t *Tree) Path(id string) string {
+	category, ok := t.byID[id]
+	if !ok {
+		return ""
+	}
+	ancestors := t.Ancestors(id)
+	names := make([]string, 0, len(ancestors)+1)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		names = append(names, ancestors[i].Name)
+	}
+	return strings.Join(append(names, category.Name), PathSeparator)
+}
+
+// ParentNames maps each subcategory's name to its parent's name.
+func (t *Tree) ParentNames() map[string]string {
+	parents := make(map[string]string)
+	for _, category := range t.ordered {
+		if parent, ok := t.byID[category.ParentID]; ok {
+			parents[category.Name] = parent.Name
+		}
+	}
+	return parents
+}
+
+// Validate checks that category, which replaces the one with the same ID if
+// there is one, has a unique name and a parent that exists without making a
+// cycle.
+func (t *Tree) Validate(category models.UserCategory) error {
+	if strings.TrimSpace(category.Name) == "" {
+		return errors.New("name is required")
+	}
+	if strings.Contains(category.Name, strings.TrimSpace(PathSeparator)) {
+		return fmt.Errorf("name must not contain %q", strings.TrimSpace(PathSeparator))
+	}
+	if existing, ok := t.ByName(category.Name); ok && existing.ID != category.ID {
+		return fmt.Errorf("%w: %q", ErrDuplicateName, category.Name)
+	}
+	if category.ParentID == "" {
+		return nil
+	}
+	if _, ok := t.byID[category.ParentID]; !ok {
+		return fmt.Errorf("%w: parent %q", ErrUnknownCategory, category.ParentID)
+	}
+	if category.ParentID == category.ID {
+		return ErrCycle
+	}
+	for _, ancestor := range t.Ancestors(category.ParentID) {
+		if ancestor.ID == category.ID {
+			return ErrCycle
+		}
+	}
+	return nil
+}
+
+// Assign makes transaction's category name and ID agree. A category ID takes
+// precedence and must exist; otherwise the ID is looked up from the name,
+// and left empty if no category has that name.
+func (t *Tree) Assign(transaction *models.Transaction) error {
+	if transaction.CategoryID != "" {
+		category, ok := t.byID[transaction.CategoryID]
+		if !ok {
+			return fmt.Errorf("%w %q", ErrUnknownCategory, transaction.CategoryID)
+		}
+		transaction.Category = category.Name
+		return nil
+	}
+	t.FillID(transaction)
+	return nil
+}
+
+// FillID sets the ID of a transaction's category from its name, for
+// transactions stored before categories had IDs. It leaves transactions that
+// already have an ID alone.
+func (t *Tree) FillID(transaction *models.Transaction) {
+	if transaction.CategoryID != "" {
+		return
+	}
+	if category, ok := t.ByName(transaction.Category); ok {
+		transaction.CategoryID = category.ID
+		transaction.Category = category.Name
+	}
+}
+
+func nameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,103 @@
+package categories
+
+import (
+	"backend/internal/models"
+	"errors"
+	"testing"
+)
+
+func testTree() *Tree {
+	return NewTree([]models.UserCategory{
+		{ID: "food", Name: "Food"},
+		{ID: "groceries", Name: "Groceries", ParentID: "food"},
+		{ID: "dining", Name: "Dining", ParentID: "food"},
+		{ID: "coffee", Name: "Coffee", ParentID: "dining"},
+		{ID: "transport", Name: "Transport"},
+	})
+}
+
+func TestTreePaths(t *testing.T) {
+	tree := testTree()
+	tests := map[string]string{
+		"coffee":    "Food > Dining > Coffee",
+		"groceries": "Food > Groceries",
+		"transport": "Transport",
+		"missing":   "",
+	}
+	for id, want := range tests {
+		if got := tree.Path(id); got != want {
+			t.Errorf("Path(%q) = %q, want %q", id, got, want)
+		}
+	}
+	if children := tree.Children("food"); len(children) != 2 || children[0] != "groceries" || children[1] != "dining" {
+		t.Errorf("Children(food) = %v", children)
+	}
+	parents := tree.ParentNames()
+	if len(parents) != 3 || parents["Coffee"] != "Dining" || parents["Groceries"] != "Food" {
+		t.Errorf("ParentNames() = %v", parents)
+	}
+}
+
+func TestTreeValidate(t *testing.T) {
+	tree := testTree()
+	tests := []struct {
+		name     string
+		category models.UserCategory
+		want     error
+	}{
+		{"new subcategory", models.UserCategory{Name: "Takeaway", ParentID: "food"}, nil},
+		{"rename keeps own name", models.UserCategory{ID: "dining", Name: "dining", ParentID: "food"}, nil},
+		{"duplicate name", models.UserCategory{Name: "groceries"}, ErrDuplicateName},
+		{"unknown parent", models.UserCategory{Name: "Pets", ParentID: "missing"}, ErrUnknownCategory},
+		{"own parent", models.UserCategory{ID: "food", Name: "Food", ParentID: "food"}, ErrCycle},
+		{"under a descendant", models.UserCategory{ID: "food", Name: "Food", ParentID: "coffee"}, ErrCycle},
+	}
+	for _, tt := range tests {
+		err := tree.Validate(tt.category)
+		if (tt.want == nil && err != nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if err := tree.Validate(models.UserCategory{Name: "Food > Snacks"}); err == nil {
+		t.Error("Validate accepted a name containing the path separator")
+	}
+}
+
+func TestTreeAssign(t *testing.T) {
+	tree := testTree()
+
+	byName := models.Transaction{Category: "groceries"}
+	if err := tree.Assign(&byName); err != nil || byName.CategoryID != "groceries" || byName.Category != "Groceries" {
+		t.Errorf("Assign by name = %+v, %v", byName, err)
+	}
+	byID := models.Transaction{Category: "Stale", CategoryID: "coffee"}
+	if err := tree.Assign(&byID); err != nil || byID.Category != "Coffee" {
+		t.Errorf("Assign by ID = %+v, %v", byID, err)
+	}
+	unknownName := models.Transaction{Category: "Holidays"}
+	if err := tree.Assign(&unknownName); err != nil || unknownName.CategoryID != "" || unknownName.Category != "Holidays" {
+		t.Errorf("Assign with an unknown name = %+v, %v", unknownName, err)
+	}
+	unknownID := models.Transaction{CategoryID: "missing"}
+	if err := tree.Assign(&unknownID); !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("Assign with an unknown ID = %v, want ErrUnknownCategory", err)
+	}
+}
+
+func TestTreeResolve(t *testing.T) {
+	tree := testTree()
+	tests := []struct {
+		id, name         string
+		wantID, wantName string
+	}{
+		{"coffee", "Stale", "coffee", "Coffee"},
+		{"", "groceries", "groceries", "Groceries"},
+		{"", "Holidays", "", "Holidays"},
+		{"missing", "Pets", "missing", "Pets"},
+	}
+	for _, tt := range tests {
+		if id, name := tree.Resolve(tt.id, tt.name); id != tt.wantID || name != tt.wantName {
+			t.Errorf("Resolve(%q, %q) = %q, %q, want %q, %q", tt.id, tt.name, id, name, tt.wantID, tt.wantName)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package categoriser
+
+import (
+	"backend/internal/merchants"
+	"backend/internal/models"
+)
+
+// builtInMerchants names the merchants of transactions saved without one,
+// using only the built-in merchant table.
+var builtInMerchants = merchants.New(nil)
+
+// LearnedKey is the key a category learned from transaction is stored under:
+// its merchant name, or the name its description normalises to when it has
+// none, reduced with merchants.Key so that "Sainsbury's" and "SAINSBURYS"
+// share a key. It returns "" when no merchant can be told.
+func LearnedKey(transaction models.Transaction) string {
+	if transaction.Merchant != "" {
+		return merchants.Key(transaction.Merchant)
+	}
+	return merchants.Key(builtInMerchants.Normalise(transaction.Description))
+}
@@ -0,0 +1,71 @@
+package categoriser
+
+import (
+	"backend/internal/models"
+	"time"
+)
+
+// RecategoriseOptions narrows which existing transactions Recategorise looks
+// at. From and To bound the transaction date, inclusive.
+type RecategoriseOptions struct {
+	From, To *time.Time
+	// OnlyOther limits the run to transactions in DefaultCategory or with no
+	// category.
+	OnlyOther bool
+	// IncludeLegacy adds transactions categorised before the source of a
+	// category was recorded. They are left out by default because some of
+	// their categories were set by hand.
+	IncludeLegacy bool
+}
+
+// Recategorise works out the categories transactions would get now and
+// returns how many were eligible and the ones that would change. Categories
+// the user set themselves, including split transactions, are never changed,
+// and nor are categories of unknown source unless opts.IncludeLegacy is set.
+func (c *Categoriser) Recategorise(transactions []models.Transaction, opts RecategoriseOptions) (int, []models.CategoryChange) {
+	examined := 0
+	changes := []models.CategoryChange{}
+	for _, transaction := range transactions {
+		if !eligible(transaction, opts) {
+			continue
+		}
+		examined++
+
+		current := transaction.Category
+		transaction.Category = ""
+		match := c.Explain(transaction)
+		if match.Category == current {
+			continue
+		}
+		changes = append(changes, models.CategoryChange{
+			TransactionID:       transaction.ID,
+			Description:         transaction.Description,
+			TransactionDateTime: transaction.TransactionDateTime,
+			From:                current,
+			To:                  match.Category,
+			Match:               match,
+		})
+	}
+	return examined, changes
+}
+
+func eligible(transaction models.Transaction, opts RecategoriseOptions) bool {
+	match := transaction.CategoryMatch
+	if (match != nil && match.Source == models.CategorySourceManual) || len(transaction.Splits) > 0 {
+		return false
+	}
+	if match == nil && !opts.IncludeLegacy {
+		return false
+	}
+	if opts.OnlyOther && transaction.Category != "" && transaction.Category != DefaultCategory {
+		return false
+	}
+	date := transaction.TransactionDateTime
+	if opts.From != nil && date.Before(*opts.From) {
+		return false
+	}
+	if opts.To != nil && date.After(*opts.To) {
+		return false
+	}
+	return true
+}
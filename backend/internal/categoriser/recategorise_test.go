@@ -0,0 +1,72 @@
+package categoriser
+
+import (
+	"backend/internal/models"
+	"testing"
+	"time"
+)
+
+func TestRecategorise(t *testing.T) {
+	c := New(nil, nil, []models.UserCategory{
+		{Name: "Transport", Keywords: []string{"uber"}},
+		{Name: "Groceries", Keywords: []string{"tesco"}},
+	})
+	date := func(day int) time.Time { return time.Date(2025, time.June, day, 0, 0, 0, 0, time.UTC) }
+	auto := func(category string) *models.CategoryMatch {
+		return &models.CategoryMatch{Source: models.CategorySourceDefault, Category: category}
+	}
+	transactions := []models.Transaction{
+		{ID: "auto-other", Description: "UBER TRIP", Category: "Other", CategoryMatch: auto("Other"), TransactionDateTime: date(1)},
+		{ID: "manual", Description: "UBER EATS", Category: "Dining", CategoryMatch: &models.CategoryMatch{Source: models.CategorySourceManual, Category: "Dining"}, TransactionDateTime: date(2)},
+		{ID: "legacy", Description: "TESCO", Category: "Food", TransactionDateTime: date(3)},
+		{ID: "unchanged", Description: "TESCO EXPRESS", Category: "Groceries", CategoryMatch: auto("Groceries"), TransactionDateTime: date(4)},
+		{ID: "auto-keyword", Description: "UBER TRIP", Category: "Travel", CategoryMatch: &models.CategoryMatch{Source: models.CategorySourceKeyword, Category: "Travel"}, TransactionDateTime: date(5)},
+		{ID: "split", Description: "UBER TRIP", Category: "Other", CategoryMatch: auto("Other"), TransactionDateTime: date(6), Splits: []models.Split{{Amount: -500, Category: "Transport"}, {Amount: -300, Category: "Dining"}}},
+	}
+	ids := func(changes []models.CategoryChange) []string {
+		var got []string
+		for _, change := range changes {
+			got = append(got, change.TransactionID)
+		}
+		return got
+	}
+	from, to := date(2), date(4)
+
+	tests := []struct {
+		name         string
+		opts         RecategoriseOptions
+		wantExamined int
+		wantChanged  []string
+	}{
+		{"automatic categories", RecategoriseOptions{}, 3, []string{"auto-other", "auto-keyword"}},
+		{"only other", RecategoriseOptions{OnlyOther: true}, 1, []string{"auto-other"}},
+		{"including legacy", RecategoriseOptions{IncludeLegacy: true}, 4, []string{"auto-other", "legacy", "auto-keyword"}},
+		{"date range", RecategoriseOptions{From: &from, To: &to}, 1, nil},
+		{"date range including legacy", RecategoriseOptions{From: &from, To: &to, IncludeLegacy: true}, 2, []string{"legacy"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			examined, changes := c.Recategorise(transactions, tt.opts)
+			got := ids(changes)
+			if examined != tt.wantExamined || len(got) != len(tt.wantChanged) {
+				t.Fatalf("Recategorise = %d, %v, want %d, %v", examined, got, tt.wantExamined, tt.wantChanged)
+			}
+			for i := range got {
+				if got[i] != tt.wantChanged[i] {
+					t.Errorf("changes = %v, want %v", got, tt.wantChanged)
+				}
+			}
+		})
+	}
+
+	_, changes := c.Recategorise(transactions[:1], RecategoriseOptions{})
+	want := models.CategoryChange{TransactionID: "auto-other", Description: "UBER TRIP", TransactionDateTime: date(1), From: "Other", To: "Transport"}
+	got := changes[0]
+	if got.Match.Source != models.CategorySourceKeyword || got.Match.Keyword != "uber" {
+		t.Errorf("Match = %+v, want the keyword that matched", got.Match)
+	}
+	got.Match = models.CategoryMatch{}
+	if got != want {
+		t.Errorf("change = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package categoriser
+
+import (
+	"backend/internal/models"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rule is a CategoryRule compiled for matching.
+type Rule struct {
+	models.CategoryRule
+	contains string
+	exact    string
+	pattern  *regexp.Regexp
+	from, to time.Time
+}
+
+// CompileRule checks that rule is well formed and prepares it for matching.
+func CompileRule(rule models.CategoryRule) (*Rule, error) {
+	if strings.TrimSpace(rule.Category) == "" {
+		return nil, errors.New("category is required")
+	}
+	if rule.Priority < 0 {
+		return nil, errors.New("priority must not be negative")
+	}
+
+	c := rule.Conditions
+	compiled := &Rule{
+		CategoryRule: rule,
+		contains:     strings.ToLower(strings.TrimSpace(c.DescriptionContains)),
+		exact:        strings.ToLower(strings.TrimSpace(c.DescriptionExact)),
+	}
+	if c.DescriptionRegex != "" {
+		pattern, err := regexp.Compile("(?i)" + c.DescriptionRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid descriptionRegex: %w", err)
+		}
+		compiled.pattern = pattern
+	}
+	if c.MinAmount != nil && *c.MinAmount < 0 || c.MaxAmount != nil && *c.MaxAmount < 0 {
+		return nil, errors.New("minAmount and maxAmount must not be negative")
+	}
+	if c.MinAmount != nil && c.MaxAmount != nil && *c.MinAmount > *c.MaxAmount {
+		return nil, errors.New("minAmount must not be more than maxAmount")
+	}
+	if c.Type != "" && c.Type != "Debit" && c.Type != "Credit" {
+		return nil, fmt.Errorf("type %q must be Debit or Credit", c.Type)
+	}
+	if c.DateFrom != nil {
+		compiled.from = truncateToDay(*c.DateFrom)
+	}
+	if c.DateTo != nil {
+		compiled.to = truncateToDay(*c.DateTo).AddDate(0, 0, 1)
+	}
+	if c.DateFrom != nil && c.DateTo != nil && !compiled.from.Before(compiled.to) {
+		return nil, errors.New("dateFrom must not be after dateTo")
+	}
+	if compiled.contains == "" && compiled.exact == "" && compiled.pattern == nil && c.MinAmount == nil && c.MaxAmount == nil &&
+		c.Type == "" && c.AccountID == "" && c.DateFrom == nil && c.DateTo == nil {
+		return nil, errors.New("at least one condition is required")
+	}
+	return compiled, nil
+}
+
+// Matches reports whether transaction meets every condition of the rule.
+func (r *Rule) Matches(transaction models.Transaction) bool {
+	_, ok := r.match(transaction)
+	return ok
+}
+
+// match tests transaction against the rule and, when a description condition
+// took part, returns the part of the description it matched.
+func (r *Rule) match(transaction models.Transaction) (*models.TextSpan, bool) {
+	c := r.Conditions
+	var span *models.TextSpan
+	if r.contains != "" {
+		if span = findFold(transaction.Description, r.contains); span == nil {
+			return nil, false
+		}
+	}
+	if r.exact != "" {
+		trimmed := strings.TrimSpace(transaction.Description)
+		if strings.ToLower(trimmed) != r.exact {
+			return nil, false
+		}
+		start := strings.Index(transaction.Description, trimmed)
+		span = &models.TextSpan{Start: start, End: start + len(trimmed), Text: trimmed}
+	}
+	if r.pattern != nil {
+		loc := r.pattern.FindStringIndex(transaction.Description)
+		if loc == nil {
+			return nil, false
+		}
+		span = &models.TextSpan{Start: loc[0], End: loc[1], Text: transaction.Description[loc[0]:loc[1]]}
+	}
+
+	amount := transaction.Amount
+	if amount < 0 {
+		amount = -amount
+	}
+	if c.MinAmount != nil && amount < *c.MinAmount {
+		return nil, false
+	}
+	if c.MaxAmount != nil && amount > *c.MaxAmount {
+		return nil, false
+	}
+	if c.Type != "" && transaction.Type != c.Type {
+		return nil, false
+	}
+	if c.AccountID != "" && transaction.AccountID != c.AccountID {
+		return nil, false
+	}
+
+	date := transaction.TransactionDateTime.UTC()
+	if c.DateFrom != nil && date.Before(r.from) {
+		return nil, false
+	}
+	if c.DateTo != nil && !date.Before(r.to) {
+		return nil, false
+	}
+	return span, true
+}
+
+// findFold finds lowered, which must already be lower case, in s ignoring
+// case, and returns where it is in s.
+func findFold(s, lowered string) *models.TextSpan {
+	for start := range s {
+		end := start
+		for _, want := range lowered {
+			if end >= len(s) {
+				return nil
+			}
+			got, size := utf8.DecodeRuneInString(s[end:])
+			if unicode.ToLower(got) != want {
+				end = -1
+				break
+			}
+			end += size
+		}
+		if end >= 0 {
+			return &models.TextSpan{Start: start, End: end, Text: s[start:end]}
+		}
+	}
+	return nil
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
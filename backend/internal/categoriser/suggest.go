@@ -0,0 +1,126 @@
+package categoriser
+
+import (
+	"backend/internal/models"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultMinSuggestionCount is how many transactions a group needs before it
+// is suggested as a category.
+const DefaultMinSuggestionCount = 2
+
+// Suggest groups the spending left in DefaultCategory, or with no category,
+// by merchant key and proposes a category for each group of at least
+// minCount transactions, biggest spend first. The keywords suggested between
+// them match every transaction in the group. Transfers, splits and categories
+// the user set themselves are left out. Uncategorised spending of unknown
+// source is included, as giving it a category overwrites nothing.
+func Suggest(transactions []models.ConvertedTransaction, minCount int) []models.CategorySuggestion {
+	groups := make(map[string][]models.ConvertedTransaction)
+	var keys []string
+	for _, transaction := range transactions {
+		if transaction.Amount >= 0 || transaction.TransferID != "" ||
+			!eligible(transaction.Transaction, RecategoriseOptions{OnlyOther: true, IncludeLegacy: true}) {
+			continue
+		}
+		key := LearnedKey(transaction.Transaction)
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], transaction)
+	}
+
+	suggestions := []models.CategorySuggestion{}
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) < minCount {
+			continue
+		}
+		merchants := commonMerchants(group)
+		suggestion := models.CategorySuggestion{
+			Name:             titleCase(key),
+			Keywords:         suggestKeywords(group, append(merchants, key)),
+			TransactionCount: len(group),
+		}
+		if len(merchants) > 0 {
+			suggestion.Name = group[merchantIndex(group, merchants[0])].Merchant
+		}
+		for _, transaction := range group {
+			if transaction.Converted != nil {
+				suggestion.Total -= transaction.Converted.Amount
+			}
+			suggestion.TransactionIDs = append(suggestion.TransactionIDs, transaction.ID)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Total != suggestions[j].Total {
+			return suggestions[i].Total > suggestions[j].Total
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	return suggestions
+}
+
+// commonMerchants returns the lowercased merchant names in group, most
+// common first.
+func commonMerchants(group []models.ConvertedTransaction) []string {
+	counts := make(map[string]int)
+	var merchants []string
+	for _, transaction := range group {
+		merchant := strings.ToLower(strings.TrimSpace(transaction.Merchant))
+		if merchant == "" {
+			continue
+		}
+		if counts[merchant] == 0 {
+			merchants = append(merchants, merchant)
+		}
+		counts[merchant]++
+	}
+	sort.SliceStable(merchants, func(i, j int) bool { return counts[merchants[i]] > counts[merchants[j]] })
+	return merchants
+}
+
+func merchantIndex(group []models.ConvertedTransaction, merchant string) int {
+	for i, transaction := range group {
+		if strings.EqualFold(strings.TrimSpace(transaction.Merchant), merchant) {
+			return i
+		}
+	}
+	return 0
+}
+
+// suggestKeywords picks from candidates, in order, the keywords needed for
+// every transaction in group to match one the way Explain matches them.
+func suggestKeywords(group []models.ConvertedTransaction, candidates []string) []string {
+	covered := make([]bool, len(group))
+	var keywords []string
+	for _, candidate := range candidates {
+		useful := false
+		for i, transaction := range group {
+			if !covered[i] && (findFold(transaction.Description, candidate) != nil || findFold(transaction.Merchant, candidate) != nil) {
+				covered[i] = true
+				useful = true
+			}
+		}
+		if useful {
+			keywords = append(keywords, candidate)
+		}
+	}
+	return keywords
+}
+
+func titleCase(key string) string {
+	words := strings.Fields(key)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
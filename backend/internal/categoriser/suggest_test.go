@@ -0,0 +1,58 @@
+package categoriser
+
+import (
+	"backend/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	other := func(id, description, merchant string, amount int64, converted *models.ConvertedAmount) models.ConvertedTransaction {
+		return models.ConvertedTransaction{
+			Transaction: models.Transaction{ID: id, Description: description, Merchant: merchant, Amount: amount, Category: DefaultCategory},
+			Converted:   converted,
+		}
+	}
+	gbp := func(amount int64) *models.ConvertedAmount {
+		return &models.ConvertedAmount{Amount: amount, Currency: "GBP"}
+	}
+	manual := other("manual", "PUREGYM LTD", "PureGym", -2500, gbp(-2500))
+	manual.CategoryMatch = &models.CategoryMatch{Source: models.CategorySourceManual, Category: DefaultCategory}
+	transfer := other("transfer", "PUREGYM LTD", "PureGym", -2500, gbp(-2500))
+	transfer.TransferID = "t1"
+	categorised := other("categorised", "PUREGYM LTD", "PureGym", -2500, gbp(-2500))
+	categorised.Category = "Health"
+	transactions := []models.ConvertedTransaction{
+		other("gym-1", "PUREGYM LTD 0412", "PureGym", -2500, gbp(-2500)),
+		other("gym-2", "PUREGYM LTD 0512", "PureGym", -2500, gbp(-2500)),
+		other("cafe-1", "BLUE DOOR CAFE 44", "", -450, gbp(-450)),
+		other("cafe-2", "Blue Door Cafe 45", "", -900, nil),
+		other("refund", "BLUE DOOR CAFE 44", "", 450, gbp(450)),
+		other("once", "CORNER SHOP", "Corner Shop", -10000, gbp(-10000)),
+		manual, transfer, categorised,
+	}
+
+	got := Suggest(transactions, DefaultMinSuggestionCount)
+	if len(got) != 2 {
+		t.Fatalf("Suggest = %+v, want the gym and the cafe", got)
+	}
+	gym, cafe := got[0], got[1]
+	if gym.Name != "PureGym" || gym.Total != 5000 || gym.TransactionCount != 2 || strings.Join(gym.Keywords, ",") != "puregym" {
+		t.Errorf("gym = %+v, want PureGym with 50.00 across two transactions and keyword puregym", gym)
+	}
+	if cafe.Name != "Blue Door Cafe" || cafe.Total != 450 || strings.Join(cafe.TransactionIDs, ",") != "cafe-1,cafe-2" || strings.Join(cafe.Keywords, ",") != "blue door cafe" {
+		t.Errorf("cafe = %+v, want both cafe payments with only the converted one totalled", cafe)
+	}
+
+	c := New(nil, nil, []models.UserCategory{{Name: cafe.Name, Keywords: cafe.Keywords}})
+	for _, transaction := range transactions[2:4] {
+		transaction.Category = ""
+		if category := c.Categorise(transaction.Transaction); category != cafe.Name {
+			t.Errorf("Categorise(%q) = %q, want the suggested keywords to match it", transaction.Description, category)
+		}
+	}
+
+	if got := Suggest(transactions, 1); len(got) != 3 || got[0].Name != "Corner Shop" {
+		t.Errorf("Suggest with minCount 1 = %+v, want the corner shop first", got)
+	}
+}
@@ -1,26 +1,134 @@
 package categoriser
 
 import (
+	"backend/internal/categories"
 	"backend/internal/db"
+	"backend/internal/models"
 	"context"
+	"log"
+	"sort"
 	"strings"
 )
 
-func CategoriseTransaction(ctx context.Context, repo db.Repository, userID, description, category string) (string, error) {
-	if category != "" {
-		return category, nil
+// DefaultCategory is given to transactions that nothing else matches.
+const DefaultCategory = "Other"
+
+// Categoriser assigns categories to one user's transactions. What the user
+// taught it by correcting earlier transactions from the same merchant comes
+// first, then their rules in priority order; failing those, the category with
+// the longest keyword found in the description, or else in the merchant name,
+// wins.
+type Categoriser struct {
+	learned  map[string]string
+	rules    []*Rule
+	keywords []keyword
+}
+
+type keyword struct {
+	text     string
+	original string
+	category string
+}
+
+// New builds a Categoriser from learned mappings, rules, which must already be
+// in priority order, and categories. Mappings and rules give their category's
+// current name. Rules that no longer compile are skipped.
+func New(mappings []models.CategoryMapping, rules []models.CategoryRule, userCategories []models.UserCategory) *Categoriser {
+	tree := categories.NewTree(userCategories)
+	c := &Categoriser{learned: make(map[string]string, len(mappings))}
+	for _, mapping := range mappings {
+		_, c.learned[mapping.Merchant] = tree.Resolve(mapping.CategoryID, mapping.Category)
+	}
+	for _, rule := range rules {
+		rule.CategoryID, rule.Category = tree.Resolve(rule.CategoryID, rule.Category)
+		compiled, err := CompileRule(rule)
+		if err != nil {
+			log.Printf("Skipping rule %s: %v", rule.ID, err)
+			continue
+		}
+		c.rules = append(c.rules, compiled)
+	}
+	for _, category := range userCategories {
+		for _, kw := range category.Keywords {
+			if text := strings.ToLower(strings.TrimSpace(kw)); text != "" {
+				c.keywords = append(c.keywords, keyword{text: text, original: kw, category: category.Name})
+			}
+		}
+	}
+	// Longest first so "amazon prime" beats "amazon"; ties are broken by
+	// category name so the result doesn't depend on storage order.
+	sort.SliceStable(c.keywords, func(i, j int) bool {
+		if len(c.keywords[i].text) != len(c.keywords[j].text) {
+			return len(c.keywords[i].text) > len(c.keywords[j].text)
+		}
+		return c.keywords[i].category < c.keywords[j].category
+	})
+	return c
+}
+
+// Load builds a Categoriser from the user's stored mappings, rules and
+// categories.
+func Load(ctx context.Context, repo db.Repository, userID string) (*Categoriser, error) {
+	mappings, err := repo.ListCategoryMappings(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
-	userCategories, err := repo.ListUserCategories(ctx, userID)
+	rules, err := repo.ListRules(ctx, userID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	categories, err := repo.ListUserCategories(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return New(mappings, rules, categories), nil
+}
+
+// Categorise returns the category for transaction, keeping the one it already
+// has if set.
+func (c *Categoriser) Categorise(transaction models.Transaction) string {
+	return c.Explain(transaction).Category
+}
+
+// Explain works out the category for transaction, as Categorise does, and
+// says what decided it.
+func (c *Categoriser) Explain(transaction models.Transaction) models.CategoryMatch {
+	if transaction.Category != "" {
+		return models.CategoryMatch{Source: models.CategorySourceManual, Category: transaction.Category}
+	}
+	// A mapping learned before the user aliased the merchant is keyed by the
+	// name its description normalises to without the alias.
+	described := transaction
+	described.Merchant = ""
+	for _, merchant := range []string{LearnedKey(transaction), LearnedKey(described)} {
+		if category, ok := c.learned[merchant]; merchant != "" && ok {
+			return models.CategoryMatch{Source: models.CategorySourceLearned, Category: category, Merchant: merchant}
+		}
+	}
+	for _, rule := range c.rules {
+		if span, ok := rule.match(transaction); ok {
+			return models.CategoryMatch{
+				Source:   models.CategorySourceRule,
+				Category: rule.Category,
+				RuleID:   rule.ID,
+				RuleName: rule.Name,
+				Span:     span,
+			}
+		}
+	}
+	for _, kw := range c.keywords {
+		if span := findFold(transaction.Description, kw.text); span != nil {
+			return models.CategoryMatch{Source: models.CategorySourceKeyword, Category: kw.category, Keyword: kw.original, Span: span}
+		}
 	}
-	descLower := strings.ToLower(description)
-	for _, cat := range userCategories {
-		for _, kw := range cat.Keywords {
-			if strings.Contains(descLower, strings.ToLower(kw)) {
-				return cat.Name, nil
+	if transaction.Merchant != "" {
+		// The span locates keywords in the description, so there is none
+		// for a match on the merchant name.
+		for _, kw := range c.keywords {
+			if findFold(transaction.Merchant, kw.text) != nil {
+				return models.CategoryMatch{Source: models.CategorySourceKeyword, Category: kw.category, Keyword: kw.original}
 			}
 		}
 	}
-	return "Other", nil
+	return models.CategoryMatch{Source: models.CategorySourceDefault, Category: DefaultCategory}
 }
@@ -0,0 +1,183 @@
+package categoriser
+
+import (
+	"backend/internal/models"
+	"testing"
+	"time"
+)
+
+func TestCategorise(t *testing.T) {
+	june := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	endOfJune := time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	rules := []models.CategoryRule{
+		{ID: "pret", Category: "Dining", Conditions: models.RuleConditions{DescriptionContains: "PRET"}},
+		{ID: "big-tesco", Category: "Household", Conditions: models.RuleConditions{DescriptionRegex: `^tesco\b`, MinAmount: int64p(10000), Type: "Debit"}},
+		{ID: "salary", Category: "Income", Conditions: models.RuleConditions{DescriptionExact: "acme ltd", Type: "Credit"}},
+		{ID: "joint", Category: "Bills", Conditions: models.RuleConditions{AccountID: "joint", MaxAmount: int64p(2000)}},
+		{ID: "june", Category: "Holiday", Conditions: models.RuleConditions{DateFrom: &june, DateTo: &endOfJune}},
+		{ID: "broken", Category: "Broken", Conditions: models.RuleConditions{DescriptionRegex: "("}},
+	}
+	categories := []models.UserCategory{
+		{Name: "Shopping", Keywords: []string{"amazon"}},
+		{Name: "Bills", Keywords: []string{"Amazon Prime"}},
+		{Name: "Food", Keywords: []string{"tesco"}},
+		{Name: "Coffee", Keywords: []string{"costa"}},
+	}
+	mappings := []models.CategoryMapping{
+		{Merchant: "sainsburys", Category: "Snacks"},
+		{Merchant: "pretty nails", Category: "Beauty"},
+		{Merchant: "marks spencer", Category: "Groceries"},
+	}
+	c := New(mappings, rules, categories)
+	may := time.Date(2025, time.May, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		transaction models.Transaction
+		want        string
+	}{
+		{"existing category kept", models.Transaction{Description: "PRET A MANGER", Category: "Work"}, "Work"},
+		{"contains ignores case", models.Transaction{Description: "Pret A Manger", Amount: -450, Type: "Debit", TransactionDateTime: may}, "Dining"},
+		{"regex with amount and type", models.Transaction{Description: "TESCO STORES 2041", Amount: -12000, Type: "Debit", TransactionDateTime: may}, "Household"},
+		{"amount below minimum falls through to keywords", models.Transaction{Description: "TESCO STORES 2041", Amount: -1200, Type: "Debit", TransactionDateTime: may}, "Food"},
+		{"wrong type falls through", models.Transaction{Description: "TESCO STORES", Amount: 12000, Type: "Credit", TransactionDateTime: may}, "Food"},
+		{"exact match", models.Transaction{Description: " ACME LTD ", Amount: 250000, Type: "Credit", TransactionDateTime: may}, "Income"},
+		{"exact needs whole description", models.Transaction{Description: "ACME LTD BONUS", Amount: 250000, Type: "Credit", TransactionDateTime: may}, "Other"},
+		{"account with maximum", models.Transaction{Description: "DD", Amount: -2000, Type: "Debit", AccountID: "joint", TransactionDateTime: may}, "Bills"},
+		{"account above maximum", models.Transaction{Description: "DD", Amount: -2001, Type: "Debit", AccountID: "joint", TransactionDateTime: may}, "Other"},
+		{"date range includes last day", models.Transaction{Description: "HOTEL", Amount: -9000, Type: "Debit", TransactionDateTime: endOfJune.Add(23 * time.Hour)}, "Holiday"},
+		{"date range excludes next day", models.Transaction{Description: "HOTEL", Amount: -9000, Type: "Debit", TransactionDateTime: endOfJune.AddDate(0, 0, 1)}, "Other"},
+		{"rules run in order", models.Transaction{Description: "PRET", Amount: -100, Type: "Debit", TransactionDateTime: june}, "Dining"},
+		{"longest keyword wins", models.Transaction{Description: "AMAZON PRIME MEMBERSHIP", Amount: -899, Type: "Debit", TransactionDateTime: may}, "Bills"},
+		{"shorter keyword", models.Transaction{Description: "AMAZON MARKETPLACE", Amount: -899, Type: "Debit", TransactionDateTime: may}, "Shopping"},
+		{"learned mapping", models.Transaction{Description: "CARD PAYMENT TO SAINSBURYS LOCAL 0412", Amount: -350, Type: "Debit", TransactionDateTime: may}, "Snacks"},
+		{"learned mapping by merchant name", models.Transaction{Description: "MS ONLINE GROCERY", Merchant: "Marks & Spencer", Amount: -350, Type: "Debit", TransactionDateTime: may}, "Groceries"},
+		{"learned before the merchant was aliased", models.Transaction{Description: "SAINSBURYS S/MKT 0412", Merchant: "Corner shop", Amount: -350, Type: "Debit", TransactionDateTime: may}, "Snacks"},
+		{"keyword in merchant name", models.Transaction{Description: "CST COF 112", Merchant: "Costa Coffee", Amount: -350, Type: "Debit", TransactionDateTime: may}, "Coffee"},
+		{"learned mapping beats rules", models.Transaction{Description: "PRETTY NAILS 22", Amount: -450, Type: "Debit", TransactionDateTime: may}, "Beauty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Categorise(tt.transaction); got != tt.want {
+				t.Errorf("Categorise = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileRule(t *testing.T) {
+	later := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	earlier := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	invalid := map[string]models.CategoryRule{
+		"no category":       {Conditions: models.RuleConditions{DescriptionContains: "x"}},
+		"no conditions":     {Category: "Food"},
+		"bad regex":         {Category: "Food", Conditions: models.RuleConditions{DescriptionRegex: "["}},
+		"negative amount":   {Category: "Food", Conditions: models.RuleConditions{MinAmount: int64p(-1)}},
+		"inverted amounts":  {Category: "Food", Conditions: models.RuleConditions{MinAmount: int64p(10), MaxAmount: int64p(5)}},
+		"bad type":          {Category: "Food", Conditions: models.RuleConditions{Type: "debit"}},
+		"inverted dates":    {Category: "Food", Conditions: models.RuleConditions{DateFrom: &later, DateTo: &earlier}},
+		"negative priority": {Category: "Food", Priority: -1, Conditions: models.RuleConditions{DescriptionContains: "x"}},
+	}
+	for name, rule := range invalid {
+		if _, err := CompileRule(rule); err == nil {
+			t.Errorf("%s: CompileRule succeeded, want an error", name)
+		}
+	}
+
+	if _, err := CompileRule(models.CategoryRule{Category: "Food", Conditions: models.RuleConditions{DateFrom: &earlier, DateTo: &earlier}}); err != nil {
+		t.Errorf("CompileRule for a single day: %v", err)
+	}
+}
+
+func TestLearnedKey(t *testing.T) {
+	tests := map[string]models.Transaction{
+		"tesco":            {Description: "TESCO STORES 2041"},
+		"pret a manger":    {Description: "CARD PAYMENT TO PRET A MANGER ON 12 JUN"},
+		"sainsburys":       {Description: "JS ONLINE GROCERY", Merchant: "Sainsbury's"},
+		"the coffee house": {Description: "SQ *THE COFFEE HOUSE 0412"},
+		"":                 {Description: "12345 / 6789"},
+	}
+	for want, transaction := range tests {
+		if got := LearnedKey(transaction); got != want {
+			t.Errorf("LearnedKey(%+v) = %q, want %q", transaction, got, want)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	rules := []models.CategoryRule{
+		{ID: "r1", Name: "Coffee", Category: "Dining", Conditions: models.RuleConditions{DescriptionRegex: `costa\s+coffee`}},
+		{ID: "r2", Name: "Big", Category: "Rent", Conditions: models.RuleConditions{MinAmount: int64p(100000)}},
+	}
+	categories := []models.UserCategory{{Name: "Transport", Keywords: []string{"TfL"}}}
+	mappings := []models.CategoryMapping{{Merchant: "lidl", Category: "Groceries"}}
+	c := New(mappings, rules, categories)
+
+	tests := []struct {
+		name        string
+		transaction models.Transaction
+		want        models.CategoryMatch
+	}{
+		{
+			"manual",
+			models.Transaction{Description: "COSTA COFFEE", Category: "Work"},
+			models.CategoryMatch{Source: models.CategorySourceManual, Category: "Work"},
+		},
+		{
+			"learned",
+			models.Transaction{Description: "LIDL GB 1234"},
+			models.CategoryMatch{Source: models.CategorySourceLearned, Category: "Groceries", Merchant: "lidl"},
+		},
+		{
+			"rule on description",
+			models.Transaction{Description: "CARD PAYMENT Costa  Coffee 22"},
+			models.CategoryMatch{Source: models.CategorySourceRule, Category: "Dining", RuleID: "r1", RuleName: "Coffee",
+				Span: &models.TextSpan{Start: 13, End: 26, Text: "Costa  Coffee"}},
+		},
+		{
+			"rule without description conditions",
+			models.Transaction{Description: "LANDLORD", Amount: -120000},
+			models.CategoryMatch{Source: models.CategorySourceRule, Category: "Rent", RuleID: "r2", RuleName: "Big"},
+		},
+		{
+			"keyword",
+			models.Transaction{Description: "Tfl Travel Charge"},
+			models.CategoryMatch{Source: models.CategorySourceKeyword, Category: "Transport", Keyword: "TfL",
+				Span: &models.TextSpan{Start: 0, End: 3, Text: "Tfl"}},
+		},
+		{
+			"default",
+			models.Transaction{Description: "SOMETHING ELSE"},
+			models.CategoryMatch{Source: models.CategorySourceDefault, Category: DefaultCategory},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Explain(tt.transaction)
+			if (got.Span == nil) != (tt.want.Span == nil) || got.Span != nil && *got.Span != *tt.want.Span {
+				t.Errorf("Span = %+v, want %+v", got.Span, tt.want.Span)
+			}
+			got.Span, tt.want.Span = nil, nil
+			if got != tt.want {
+				t.Errorf("Explain = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func int64p(v int64) *int64 { return &v }
+
+func TestCategoriseUsesCurrentCategoryNames(t *testing.T) {
+	categories := []models.UserCategory{{ID: "eating-out", Name: "Eating out"}}
+	rules := []models.CategoryRule{{ID: "pret", Category: "Dining", CategoryID: "eating-out", Conditions: models.RuleConditions{DescriptionContains: "pret"}}}
+	mappings := []models.CategoryMapping{{Merchant: "costa coffee", Category: "Dining", CategoryID: "eating-out"}}
+	c := New(mappings, rules, categories)
+
+	for _, description := range []string{"PRET A MANGER", "COSTA"} {
+		if got := c.Categorise(models.Transaction{Description: description}); got != "Eating out" {
+			t.Errorf("Categorise(%q) = %q, want the renamed category", description, got)
+		}
+	}
+}
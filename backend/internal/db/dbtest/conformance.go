@@ -0,0 +1,1637 @@
+// Package dbtest contains a behavioural test suite that every db.Repository
+// implementation is expected to pass.
+package dbtest
+
+import (
+	"backend/internal/db"
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"backend/internal/money"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+// NewRepositoryFunc returns the repository under test. It is called once per
+// subtest; implementations backed by shared storage may return the same
+// instance because every subtest uses freshly generated user IDs.
+type NewRepositoryFunc func(t *testing.T) db.Repository
+
+// RunRepositoryTests runs the conformance suite against newRepo.
+func RunRepositoryTests(t *testing.T, newRepo NewRepositoryFunc) {
+	tests := map[string]func(t *testing.T, repo db.Repository){
+		"SeedNewUser":                 testSeedNewUser,
+		"UserProfile":                 testUserProfile,
+		"UserNotFound":                testUserNotFound,
+		"TransactionCRUD":             testTransactionCRUD,
+		"TransactionNotFound":         testTransactionNotFound,
+		"TransactionOwnership":        testTransactionOwnership,
+		"ListTransactionsIsolation":   testListTransactionsIsolation,
+		"ListTransactionsFilters":     testListTransactionsFilters,
+		"BulkAddTransactions":         testBulkAddTransactions,
+		"BulkAddTransactionsEmpty":    testBulkAddTransactionsEmpty,
+		"UserCategoryCRUD":            testUserCategoryCRUD,
+		"UserCategoryNotFound":        testUserCategoryNotFound,
+		"UserCategoriesAreIsolated":   testUserCategoriesAreIsolated,
+		"UserCategoryHierarchy":       testUserCategoryHierarchy,
+		"UserCategoryRenameCascades":  testUserCategoryRenameCascades,
+		"UserCategoryDeleteReassigns": testUserCategoryDeleteReassigns,
+		"UserCategoryReferences":      testUserCategoryReferences,
+		"UpdateTransactionKeepsOther": testUpdateTransactionKeepsOtherFields,
+		"LegacyTransactionCurrency":   testLegacyTransactionCurrency,
+		"AccountCRUD":                 testAccountCRUD,
+		"AccountNotFound":             testAccountNotFound,
+		"AccountsAreIsolated":         testAccountsAreIsolated,
+		"BalanceCheckpoints":          testBalanceCheckpoints,
+		"TransactionTransferLink":     testTransactionTransferLink,
+		"BudgetCRUD":                  testBudgetCRUD,
+		"BudgetNotFound":              testBudgetNotFound,
+		"Allocations":                 testAllocations,
+		"RuleCRUD":                    testRuleCRUD,
+		"RuleNotFound":                testRuleNotFound,
+		"CategoryMappings":            testCategoryMappings,
+		"MerchantAliases":             testMerchantAliases,
+		"ImportProfiles":              testImportProfiles,
+		"TransactionMerchant":         testTransactionMerchant,
+		"TransactionCategoryMatch":    testTransactionCategoryMatch,
+		"TransactionSplits":           testTransactionSplits,
+		"TransactionValueDate":        testTransactionValueDate,
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, newRepo(t))
+		})
+	}
+}
+
+func testSeedNewUser(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	if err := repo.SeedNewUser(ctx, userID, map[string]interface{}{"email": "first@example.com"}); err != nil {
+		t.Fatalf("SeedNewUser: %v", err)
+	}
+	// Seeding twice overwrites the profile rather than failing.
+	if err := repo.SeedNewUser(ctx, userID, map[string]interface{}{"email": "second@example.com"}); err != nil {
+		t.Fatalf("SeedNewUser again: %v", err)
+	}
+}
+
+func testUserProfile(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	// Profiles seeded before base currencies existed fall back to the default.
+	if err := repo.SeedNewUser(ctx, userID, map[string]interface{}{"email": "user@example.com"}); err != nil {
+		t.Fatalf("SeedNewUser: %v", err)
+	}
+	user, err := repo.GetUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.ID != userID || user.Email != "user@example.com" || user.BaseCurrency != money.DefaultCurrency {
+		t.Errorf("GetUser = %+v", user)
+	}
+
+	baseCurrency := "EUR"
+	firstName := "Ada"
+	updated, err := repo.UpdateUser(ctx, userID, models.UserUpdate{BaseCurrency: &baseCurrency, FirstName: &firstName})
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if updated.BaseCurrency != baseCurrency || updated.FirstName != firstName || updated.Email != "user@example.com" {
+		t.Errorf("UpdateUser = %+v", updated)
+	}
+
+	if err := repo.SeedNewUser(ctx, userID, map[string]interface{}{"email": "user@example.com", "baseCurrency": "USD"}); err != nil {
+		t.Fatalf("SeedNewUser: %v", err)
+	}
+	user, err = repo.GetUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.BaseCurrency != "USD" || user.FirstName != "" {
+		t.Errorf("SeedNewUser did not replace the profile: %+v", user)
+	}
+}
+
+func testUserNotFound(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	var notFoundErr *exceptions.UserNotFoundError
+
+	if _, err := repo.GetUser(ctx, userID); !errors.As(err, &notFoundErr) {
+		t.Errorf("GetUser error = %v, want UserNotFoundError", err)
+	}
+	baseCurrency := "EUR"
+	if _, err := repo.UpdateUser(ctx, userID, models.UserUpdate{BaseCurrency: &baseCurrency}); !errors.As(err, &notFoundErr) {
+		t.Errorf("UpdateUser error = %v, want UserNotFoundError", err)
+	}
+}
+
+func testTransactionCRUD(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	want := NewTransaction(userID, "TESCO STORES 3021", -1999)
+
+	id, err := repo.AddTransaction(ctx, userID, want)
+	if err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+	if id == "" {
+		t.Fatal("AddTransaction returned an empty ID")
+	}
+
+	got, err := repo.GetTransactionByID(ctx, userID, id)
+	if err != nil {
+		t.Fatalf("GetTransactionByID: %v", err)
+	}
+	want.ID = id
+	want.UserID = ""
+	assertTransactionEqual(t, got, want)
+
+	description := "TESCO EXPRESS"
+	category := "Groceries"
+	updated, err := repo.UpdateTransaction(ctx, userID, id, models.TransactionUpdate{
+		Description: &description,
+		Category:    &category,
+	})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if updated.ID != id || updated.Description != description || updated.Category != category {
+		t.Fatalf("UpdateTransaction returned %+v", updated)
+	}
+	if updated.UserID != "" {
+		t.Errorf("UpdateTransaction leaked user ID %q", updated.UserID)
+	}
+	if !updated.UpdatedAt.After(want.UpdatedAt) {
+		t.Errorf("UpdatedAt = %v, want after %v", updated.UpdatedAt, want.UpdatedAt)
+	}
+
+	if err := repo.DeleteTransaction(ctx, userID, id); err != nil {
+		t.Fatalf("DeleteTransaction: %v", err)
+	}
+	_, err = repo.GetTransactionByID(ctx, userID, id)
+	assertTransactionNotFound(t, err)
+}
+
+func testUpdateTransactionKeepsOtherFields(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	original := NewTransaction(userID, "NETFLIX.COM", -1099)
+
+	id, err := repo.AddTransaction(ctx, userID, original)
+	if err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+
+	// Larger than an int32 can hold.
+	amount := int64(-5_000_000_000)
+	currency := "EUR"
+	updated, err := repo.UpdateTransaction(ctx, userID, id, models.TransactionUpdate{Amount: &amount, Currency: &currency})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if updated.Amount != amount || updated.Currency != currency {
+		t.Errorf("Amount = %d %s, want %d %s", updated.Amount, updated.Currency, amount, currency)
+	}
+	if updated.Description != original.Description || updated.Category != original.Category ||
+		updated.Type != original.Type || updated.BankReference != original.BankReference {
+		t.Errorf("UpdateTransaction changed fields it was not given: %+v", updated)
+	}
+	if !updated.TransactionDateTime.Equal(original.TransactionDateTime) {
+		t.Errorf("TransactionDateTime = %v, want %v", updated.TransactionDateTime, original.TransactionDateTime)
+	}
+}
+
+func testLegacyTransactionCurrency(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	legacy := NewTransaction(userID, "BEFORE CURRENCIES", -1234)
+	legacy.Currency = ""
+
+	id, err := repo.AddTransaction(ctx, userID, legacy)
+	if err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+	got, err := repo.GetTransactionByID(ctx, userID, id)
+	if err != nil {
+		t.Fatalf("GetTransactionByID: %v", err)
+	}
+	if got.Currency != money.DefaultCurrency {
+		t.Errorf("Currency = %q, want %q", got.Currency, money.DefaultCurrency)
+	}
+
+	// Patching another field keeps the transaction valid.
+	category := "Bills"
+	updated, err := repo.UpdateTransaction(ctx, userID, id, models.TransactionUpdate{Category: &category})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if updated.Currency != money.DefaultCurrency || updated.Amount != legacy.Amount {
+		t.Errorf("UpdateTransaction returned %d %q, want %d %q", updated.Amount, updated.Currency, legacy.Amount, money.DefaultCurrency)
+	}
+}
+
+func testTransactionTransferLink(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	id, err := repo.AddTransaction(ctx, userID, NewTransaction(userID, "TO SAVINGS", -5000))
+	if err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+
+	counterpart := "counterpart-id"
+	linked, err := repo.UpdateTransaction(ctx, userID, id, models.TransactionUpdate{TransferID: &counterpart})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if linked.TransferID != counterpart || linked.Description != "TO SAVINGS" {
+		t.Errorf("linked transaction = %+v", *linked)
+	}
+	got, err := repo.GetTransactionByID(ctx, userID, id)
+	if err != nil {
+		t.Fatalf("GetTransactionByID: %v", err)
+	}
+	if got.TransferID != counterpart {
+		t.Errorf("TransferID = %q, want %q", got.TransferID, counterpart)
+	}
+
+	unlinked := ""
+	if _, err := repo.UpdateTransaction(ctx, userID, id, models.TransactionUpdate{TransferID: &unlinked}); err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	got, err = repo.GetTransactionByID(ctx, userID, id)
+	if err != nil {
+		t.Fatalf("GetTransactionByID: %v", err)
+	}
+	if got.TransferID != "" {
+		t.Errorf("TransferID after unlinking = %q, want empty", got.TransferID)
+	}
+}
+
+func testTransactionNotFound(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	missingID := "missing" + NewUserID(t)
+
+	_, err := repo.GetTransactionByID(ctx, userID, missingID)
+	assertTransactionNotFound(t, err)
+
+	description := "anything"
+	_, err = repo.UpdateTransaction(ctx, userID, missingID, models.TransactionUpdate{Description: &description})
+	assertTransactionNotFound(t, err)
+
+	err = repo.DeleteTransaction(ctx, userID, missingID)
+	assertTransactionNotFound(t, err)
+}
+
+func testTransactionOwnership(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	owner := NewUserID(t)
+	other := NewUserID(t)
+
+	// A document stored under owner but recording a different user must
+	// never be returned to, or modified by, the path owner.
+	id, err := repo.AddTransaction(ctx, owner, NewTransaction(other, "MISFILED", -100))
+	if err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+
+	_, err = repo.GetTransactionByID(ctx, owner, id)
+	assertUserForbidden(t, err)
+
+	description := "changed"
+	_, err = repo.UpdateTransaction(ctx, owner, id, models.TransactionUpdate{Description: &description})
+	assertUserForbidden(t, err)
+
+	err = repo.DeleteTransaction(ctx, owner, id)
+	assertUserForbidden(t, err)
+
+	// Another user cannot reach the document at all.
+	_, err = repo.GetTransactionByID(ctx, other, id)
+	assertTransactionNotFound(t, err)
+}
+
+func testListTransactionsIsolation(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	alice := NewUserID(t)
+	bob := NewUserID(t)
+
+	if _, err := repo.AddTransaction(ctx, alice, NewTransaction(alice, "ALICE", -100)); err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+
+	transactions, err := repo.ListTransactions(ctx, bob, nil)
+	if err != nil {
+		t.Fatalf("ListTransactions: %v", err)
+	}
+	if len(transactions) != 0 {
+		t.Fatalf("ListTransactions for another user returned %d transactions", len(transactions))
+	}
+
+	transactions, err = repo.ListTransactions(ctx, alice, nil)
+	if err != nil {
+		t.Fatalf("ListTransactions: %v", err)
+	}
+	if len(transactions) != 1 {
+		t.Fatalf("ListTransactions returned %d transactions, want 1", len(transactions))
+	}
+	if transactions[0].ID == "" || transactions[0].UserID != "" {
+		t.Errorf("ListTransactions returned ID %q and user ID %q", transactions[0].ID, transactions[0].UserID)
+	}
+}
+
+func testListTransactionsFilters(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	groceries := NewTransaction(userID, "TESCO", -2500)
+	groceries.Category = "Groceries"
+	salary := NewTransaction(userID, "ACME LTD SALARY", 250000)
+	salary.Category = "Income"
+	salary.Type = "Credit"
+	salary.AccountID = "current-account"
+	dining := NewTransaction(userID, "DELIVEROO", -1850)
+	dining.Category = "Dining"
+
+	ids := make(map[string]string)
+	for _, transaction := range []models.Transaction{groceries, salary, dining} {
+		id, err := repo.AddTransaction(ctx, userID, transaction)
+		if err != nil {
+			t.Fatalf("AddTransaction: %v", err)
+		}
+		ids[transaction.Description] = id
+	}
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		want    []string
+	}{
+		{"none", nil, []string{"TESCO", "ACME LTD SALARY", "DELIVEROO"}},
+		{"category", map[string]string{"category": "Groceries"}, []string{"TESCO"}},
+		{"type", map[string]string{"type": "Debit"}, []string{"TESCO", "DELIVEROO"}},
+		{"combined", map[string]string{"type": "Debit", "category": "Dining"}, []string{"DELIVEROO"}},
+		{"account", map[string]string{"accountId": "current-account"}, []string{"ACME LTD SALARY"}},
+		{"no match", map[string]string{"category": "Travel"}, nil},
+		{"unknown field", map[string]string{"doesNotExist": "x"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions, err := repo.ListTransactions(ctx, userID, tt.filters)
+			if err != nil {
+				t.Fatalf("ListTransactions: %v", err)
+			}
+			got := make(map[string]bool)
+			for _, transaction := range transactions {
+				if ids[transaction.Description] != transaction.ID {
+					t.Errorf("transaction %q has ID %q, want %q", transaction.Description, transaction.ID, ids[transaction.Description])
+				}
+				got[transaction.Description] = true
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListTransactions(%v) returned %v, want %v", tt.filters, got, tt.want)
+			}
+			for _, description := range tt.want {
+				if !got[description] {
+					t.Errorf("ListTransactions(%v) is missing %q", tt.filters, description)
+				}
+			}
+		})
+	}
+}
+
+func testBulkAddTransactions(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	input := []models.Transaction{
+		NewTransaction(userID, "ONE", -100),
+		NewTransaction(userID, "TWO", -200),
+		NewTransaction(userID, "THREE", 300),
+	}
+
+	added, err := repo.BulkAddTransactions(ctx, userID, input)
+	if err != nil {
+		t.Fatalf("BulkAddTransactions: %v", err)
+	}
+	if len(added) != len(input) {
+		t.Fatalf("BulkAddTransactions returned %d transactions, want %d", len(added), len(input))
+	}
+
+	seen := make(map[string]bool)
+	for i, transaction := range added {
+		if transaction.ID == "" {
+			t.Fatalf("BulkAddTransactions returned transaction %d without an ID", i)
+		}
+		if seen[transaction.ID] {
+			t.Fatalf("BulkAddTransactions returned duplicate ID %q", transaction.ID)
+		}
+		seen[transaction.ID] = true
+		if transaction.UserID != "" {
+			t.Errorf("BulkAddTransactions leaked user ID %q", transaction.UserID)
+		}
+		if transaction.Description != input[i].Description {
+			t.Errorf("transaction %d description = %q, want %q", i, transaction.Description, input[i].Description)
+		}
+
+		stored, err := repo.GetTransactionByID(ctx, userID, transaction.ID)
+		if err != nil {
+			t.Fatalf("GetTransactionByID(%q): %v", transaction.ID, err)
+		}
+		assertTransactionEqual(t, stored, transaction)
+	}
+
+	listed, err := repo.ListTransactions(ctx, userID, nil)
+	if err != nil {
+		t.Fatalf("ListTransactions: %v", err)
+	}
+	if len(listed) != len(input) {
+		t.Errorf("ListTransactions returned %d transactions, want %d", len(listed), len(input))
+	}
+}
+
+func testBulkAddTransactionsEmpty(t *testing.T, repo db.Repository) {
+	if _, err := repo.BulkAddTransactions(context.Background(), NewUserID(t), nil); err == nil {
+		t.Fatal("BulkAddTransactions with no transactions should fail")
+	}
+}
+
+func testUserCategoryCRUD(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	groceriesID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Groceries", Keywords: []string{"tesco", "aldi"}})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	otherID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Other", Keywords: []string{}})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	if groceriesID == "" {
+		t.Fatal("AddUserCategory returned an empty ID")
+	}
+
+	categories := listCategoriesByName(t, repo, userID)
+	if len(categories) != 2 {
+		t.Fatalf("ListUserCategories returned %d categories, want 2", len(categories))
+	}
+	if got := categories["Groceries"].Keywords; len(got) != 2 || got[0] != "tesco" || got[1] != "aldi" {
+		t.Errorf("Groceries keywords = %v", got)
+	}
+	if got := categories["Other"].Keywords; len(got) != 0 {
+		t.Errorf("Other keywords = %v, want none", got)
+	}
+
+	_, err = repo.UpdateUserCategory(ctx, userID, groceriesID, models.UserCategory{Name: "Food", Keywords: []string{"lidl"}})
+	if err != nil {
+		t.Fatalf("UpdateUserCategory: %v", err)
+	}
+	categories = listCategoriesByName(t, repo, userID)
+	if _, ok := categories["Groceries"]; ok {
+		t.Error("UpdateUserCategory did not rename the category")
+	}
+	if got := categories["Food"].Keywords; len(got) != 1 || got[0] != "lidl" {
+		t.Errorf("Food keywords = %v, want [lidl]", got)
+	}
+
+	if _, err := repo.DeleteUserCategory(ctx, userID, groceriesID, otherID); err != nil {
+		t.Fatalf("DeleteUserCategory: %v", err)
+	}
+	categories = listCategoriesByName(t, repo, userID)
+	if _, ok := categories["Food"]; ok || len(categories) != 1 {
+		t.Errorf("DeleteUserCategory left %v", categories)
+	}
+}
+
+func testUserCategoryNotFound(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	missingID := "missing" + NewUserID(t)
+
+	_, err := repo.UpdateUserCategory(ctx, userID, missingID, models.UserCategory{Name: "Ghost"})
+	var notFoundErr *exceptions.CategoryNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("UpdateUserCategory on a missing category returned %v, want CategoryNotFoundError", err)
+	}
+	if categories := listCategoriesByName(t, repo, userID); len(categories) != 0 {
+		t.Errorf("UpdateUserCategory on a missing category created %v", categories)
+	}
+
+	otherID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Other"})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	if _, err := repo.DeleteUserCategory(ctx, userID, missingID, otherID); !errors.As(err, &notFoundErr) || notFoundErr.CategoryID != missingID {
+		t.Errorf("DeleteUserCategory on a missing category returned %v, want CategoryNotFoundError", err)
+	}
+	if _, err := repo.DeleteUserCategory(ctx, userID, otherID, missingID); !errors.As(err, &notFoundErr) || notFoundErr.CategoryID != missingID {
+		t.Errorf("DeleteUserCategory reassigning to a missing category returned %v, want CategoryNotFoundError", err)
+	}
+	if _, err := repo.DeleteUserCategory(ctx, userID, otherID, otherID); !errors.As(err, &notFoundErr) {
+		t.Errorf("DeleteUserCategory reassigning to itself returned %v, want CategoryNotFoundError", err)
+	}
+	if categories := listCategoriesByName(t, repo, userID); len(categories) != 1 {
+		t.Errorf("failed deletes left %v, want Other", categories)
+	}
+}
+
+func testUserCategoriesAreIsolated(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	alice := NewUserID(t)
+	bob := NewUserID(t)
+
+	id, err := repo.AddUserCategory(ctx, alice, models.UserCategory{Name: "Groceries"})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	if categories := listCategoriesByName(t, repo, bob); len(categories) != 0 {
+		t.Fatalf("ListUserCategories for another user returned %v", categories)
+	}
+
+	_, err = repo.UpdateUserCategory(ctx, bob, id, models.UserCategory{Name: "Stolen"})
+	var notFoundErr *exceptions.CategoryNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("UpdateUserCategory for another user returned %v, want CategoryNotFoundError", err)
+	}
+	bobsID, err := repo.AddUserCategory(ctx, bob, models.UserCategory{Name: "Other"})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	if _, err := repo.DeleteUserCategory(ctx, bob, id, bobsID); !errors.As(err, &notFoundErr) {
+		t.Errorf("DeleteUserCategory for another user returned %v, want CategoryNotFoundError", err)
+	}
+	if _, ok := listCategoriesByName(t, repo, alice)["Groceries"]; !ok {
+		t.Error("another user was able to delete the category")
+	}
+}
+
+func testUserCategoryHierarchy(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	foodID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Food"})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	groceriesID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Groceries", ParentID: foodID})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+
+	categories := listCategoriesByName(t, repo, userID)
+	if got := categories["Food"]; got.ID != foodID || got.ParentID != "" {
+		t.Errorf("Food = %+v, want ID %q and no parent", got, foodID)
+	}
+	if got := categories["Groceries"]; got.ID != groceriesID || got.ParentID != foodID {
+		t.Errorf("Groceries = %+v, want ID %q under %q", got, groceriesID, foodID)
+	}
+
+	// Updating replaces the parent, so an empty ParentID moves it to the top level.
+	if _, err := repo.UpdateUserCategory(ctx, userID, groceriesID, models.UserCategory{Name: "Groceries"}); err != nil {
+		t.Fatalf("UpdateUserCategory: %v", err)
+	}
+	if got := listCategoriesByName(t, repo, userID)["Groceries"]; got.ParentID != "" {
+		t.Errorf("Groceries parent after update = %q, want none", got.ParentID)
+	}
+
+	transaction := NewTransaction(userID, "TESCO STORES", -1250)
+	transaction.Category = "Groceries"
+	transaction.CategoryID = groceriesID
+	id, err := repo.AddTransaction(ctx, userID, transaction)
+	if err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+	if got, err := repo.GetTransactionByID(ctx, userID, id); err != nil || got.CategoryID != groceriesID {
+		t.Fatalf("GetTransactionByID = %+v, %v, want category ID %q", got, err, groceriesID)
+	}
+	if found, err := repo.ListTransactions(ctx, userID, map[string]string{"categoryId": groceriesID}); err != nil || len(found) != 1 {
+		t.Errorf("ListTransactions by categoryId = %v, %v, want 1", found, err)
+	}
+	updated, err := repo.UpdateTransaction(ctx, userID, id, models.TransactionUpdate{CategoryID: &foodID})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if updated.CategoryID != foodID {
+		t.Errorf("CategoryID after update = %q, want %q", updated.CategoryID, foodID)
+	}
+}
+
+func testUserCategoryRenameCascades(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	groceriesID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Groceries"})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	byID := NewTransaction(userID, "TESCO STORES", -1250)
+	byID.Category, byID.CategoryID = "Groceries", groceriesID
+	// Transactions stored before categories had IDs only have the name,
+	// which is matched ignoring case.
+	byName := NewTransaction(userID, "ALDI", -800)
+	byName.Category = "groceries"
+	elsewhere := NewTransaction(userID, "TFL", -280)
+	elsewhere.Category = "Transport"
+	ids := addTransactions(t, repo, userID, byID, byName, elsewhere)
+
+	// Changing only the keywords leaves transactions alone.
+	if updated, err := repo.UpdateUserCategory(ctx, userID, groceriesID, models.UserCategory{Name: "Groceries", Keywords: []string{"tesco"}}); err != nil || updated != 0 {
+		t.Fatalf("UpdateUserCategory without a rename = %d, %v, want 0", updated, err)
+	}
+	updated, err := repo.UpdateUserCategory(ctx, userID, groceriesID, models.UserCategory{Name: "Food shopping"})
+	if err != nil {
+		t.Fatalf("UpdateUserCategory: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("UpdateUserCategory renamed %d transactions, want 2", updated)
+	}
+	for _, id := range ids[:2] {
+		got, err := repo.GetTransactionByID(ctx, userID, id)
+		if err != nil {
+			t.Fatalf("GetTransactionByID: %v", err)
+		}
+		if got.Category != "Food shopping" || got.CategoryID != groceriesID {
+			t.Errorf("transaction %q after rename = %q (%q), want Food shopping (%q)", got.Description, got.Category, got.CategoryID, groceriesID)
+		}
+	}
+	if got, err := repo.GetTransactionByID(ctx, userID, ids[2]); err != nil || got.Category != "Transport" {
+		t.Errorf("rename changed another category's transaction: %+v, %v", got, err)
+	}
+}
+
+func testUserCategoryDeleteReassigns(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	diningID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Dining"})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	foodID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Food"})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	byID := NewTransaction(userID, "PIZZA EXPRESS", -3200)
+	byID.Category, byID.CategoryID = "Dining", diningID
+	byName := NewTransaction(userID, "NANDOS", -2100)
+	byName.Category = "Dining"
+	split := NewTransaction(userID, "M&S", -3000)
+	split.Category = "Other"
+	split.Splits = []models.Split{
+		{Amount: -1000, Category: "Other"},
+		{Amount: -2000, Category: "Dining", CategoryID: diningID},
+	}
+	ids := addTransactions(t, repo, userID, byID, byName, split)
+
+	moved, err := repo.DeleteUserCategory(ctx, userID, diningID, foodID)
+	if err != nil {
+		t.Fatalf("DeleteUserCategory: %v", err)
+	}
+	if moved != 3 {
+		t.Errorf("DeleteUserCategory moved %d transactions, want 3", moved)
+	}
+	got, err := repo.GetTransactionByID(ctx, userID, ids[2])
+	if err != nil {
+		t.Fatalf("GetTransactionByID: %v", err)
+	}
+	if got.Category != "Other" || len(got.Splits) != 2 || got.Splits[0].Category != "Other" || got.Splits[1].Category != "Food" || got.Splits[1].CategoryID != foodID {
+		t.Errorf("split transaction after delete = %+v, want only its Dining line moved to Food", got)
+	}
+	for _, id := range ids[:2] {
+		got, err := repo.GetTransactionByID(ctx, userID, id)
+		if err != nil {
+			t.Fatalf("GetTransactionByID: %v", err)
+		}
+		if got.Category != "Food" || got.CategoryID != foodID {
+			t.Errorf("transaction %q after delete = %q (%q), want Food (%q)", got.Description, got.Category, got.CategoryID, foodID)
+		}
+	}
+	if _, ok := listCategoriesByName(t, repo, userID)["Dining"]; ok {
+		t.Error("DeleteUserCategory left the category")
+	}
+}
+
+func testUserCategoryReferences(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	diningID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Dining"})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+	foodID, err := repo.AddUserCategory(ctx, userID, models.UserCategory{Name: "Food"})
+	if err != nil {
+		t.Fatalf("AddUserCategory: %v", err)
+	}
+
+	// One of each by ID, one of each stored before categories had IDs with
+	// the name in another case, and one of each in another category.
+	for _, budget := range []models.Budget{
+		{Category: "Dining", CategoryID: diningID, Period: models.BudgetPeriodMonthly, Amount: 20000, Currency: "GBP", CreatedAt: now},
+		{Category: "dining", Period: models.BudgetPeriodWeekly, Amount: 5000, Currency: "GBP", CreatedAt: now},
+		{Category: "Transport", Period: models.BudgetPeriodMonthly, Amount: 8000, Currency: "GBP", CreatedAt: now},
+	} {
+		if _, err := repo.AddBudget(ctx, userID, budget); err != nil {
+			t.Fatalf("AddBudget: %v", err)
+		}
+	}
+	if _, err := repo.AddAllocations(ctx, userID, []models.Allocation{
+		{Month: "2025-07", Category: "Dining", CategoryID: diningID, Amount: 20000, Kind: models.AllocationKindAssign, CreatedAt: now},
+		{Month: "2025-07", Category: "dining", Amount: 5000, Kind: models.AllocationKindAssign, CreatedAt: now},
+		{Month: "2025-07", Category: "Transport", Amount: 8000, Kind: models.AllocationKindAssign, CreatedAt: now},
+	}); err != nil {
+		t.Fatalf("AddAllocations: %v", err)
+	}
+	for i, rule := range []models.CategoryRule{
+		{Name: "Restaurants", Category: "Dining", CategoryID: diningID, Conditions: models.RuleConditions{DescriptionRegex: "^pizza"}},
+		{Name: "Takeaway", Category: "dining", Conditions: models.RuleConditions{DescriptionRegex: "^deliveroo"}},
+		{Name: "Trains", Category: "Transport", Conditions: models.RuleConditions{DescriptionRegex: "^trainline"}},
+	} {
+		rule.Priority, rule.CreatedAt, rule.UpdatedAt = i+1, now, now
+		if _, err := repo.AddRule(ctx, userID, rule); err != nil {
+			t.Fatalf("AddRule: %v", err)
+		}
+	}
+	for _, mapping := range []models.CategoryMapping{
+		{Merchant: "pizza express", Category: "Dining", CategoryID: diningID, UpdatedAt: now},
+		{Merchant: "nandos", Category: "dining", UpdatedAt: now},
+		{Merchant: "tfl", Category: "Transport", UpdatedAt: now},
+	} {
+		if err := repo.SetCategoryMapping(ctx, userID, mapping); err != nil {
+			t.Fatalf("SetCategoryMapping: %v", err)
+		}
+	}
+
+	check := func(when, name, id string) {
+		t.Helper()
+		type reference struct{ category, categoryID string }
+		var got []reference
+		budgets, err := repo.ListBudgets(ctx, userID)
+		if err != nil {
+			t.Fatalf("ListBudgets: %v", err)
+		}
+		for _, budget := range budgets {
+			got = append(got, reference{budget.Category, budget.CategoryID})
+		}
+		allocations, err := repo.ListAllocations(ctx, userID, "2025-07")
+		if err != nil {
+			t.Fatalf("ListAllocations: %v", err)
+		}
+		for _, allocation := range allocations {
+			got = append(got, reference{allocation.Category, allocation.CategoryID})
+		}
+		rules, err := repo.ListRules(ctx, userID)
+		if err != nil {
+			t.Fatalf("ListRules: %v", err)
+		}
+		for _, rule := range rules {
+			got = append(got, reference{rule.Category, rule.CategoryID})
+		}
+		mappings, err := repo.ListCategoryMappings(ctx, userID)
+		if err != nil {
+			t.Fatalf("ListCategoryMappings: %v", err)
+		}
+		for _, mapping := range mappings {
+			got = append(got, reference{mapping.Category, mapping.CategoryID})
+		}
+
+		moved, transport := 0, 0
+		for _, ref := range got {
+			switch {
+			case ref.category == name && ref.categoryID == id:
+				moved++
+			case ref.category == "Transport" && ref.categoryID == "":
+				transport++
+			default:
+				t.Errorf("%s: found %q (%q), want %q (%q) or Transport", when, ref.category, ref.categoryID, name, id)
+			}
+		}
+		if moved != 8 || transport != 4 {
+			t.Errorf("%s: %d moved and %d left in Transport, want 8 and 4", when, moved, transport)
+		}
+	}
+
+	if _, err := repo.UpdateUserCategory(ctx, userID, diningID, models.UserCategory{Name: "Eating out"}); err != nil {
+		t.Fatalf("UpdateUserCategory: %v", err)
+	}
+	check("after rename", "Eating out", diningID)
+
+	if _, err := repo.DeleteUserCategory(ctx, userID, diningID, foodID); err != nil {
+		t.Fatalf("DeleteUserCategory: %v", err)
+	}
+	check("after delete", "Food", foodID)
+}
+
+func testAccountCRUD(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	created := time.Now().UTC().Truncate(time.Millisecond)
+	account := models.Account{
+		Name:           "Joint current",
+		Institution:    "Monzo",
+		Type:           models.AccountTypeCurrent,
+		Currency:       "GBP",
+		OpeningBalance: 125000,
+		CreatedAt:      created,
+		UpdatedAt:      created,
+	}
+	id, err := repo.AddAccount(ctx, userID, account)
+	if err != nil {
+		t.Fatalf("AddAccount: %v", err)
+	}
+	if id == "" {
+		t.Fatal("AddAccount returned an empty ID")
+	}
+	account.ID = id
+
+	got, err := repo.GetAccount(ctx, userID, id)
+	if err != nil {
+		t.Fatalf("GetAccount: %v", err)
+	}
+	if got.ID != id || got.Name != account.Name || got.Institution != account.Institution ||
+		got.Type != account.Type || got.Currency != account.Currency || got.OpeningBalance != account.OpeningBalance ||
+		!got.CreatedAt.Equal(created) {
+		t.Errorf("GetAccount = %+v, want %+v", *got, account)
+	}
+
+	if _, err := repo.AddAccount(ctx, userID, models.Account{Name: "Travel card", Type: models.AccountTypeCreditCard, Currency: "EUR", CreatedAt: created.Add(time.Second), UpdatedAt: created}); err != nil {
+		t.Fatalf("AddAccount: %v", err)
+	}
+	accounts, err := repo.ListAccounts(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListAccounts: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].ID != id || accounts[1].Name != "Travel card" {
+		t.Fatalf("ListAccounts = %+v", accounts)
+	}
+
+	name := "Household"
+	currency := "EUR"
+	opening := int64(-500)
+	updated, err := repo.UpdateAccount(ctx, userID, id, models.AccountUpdate{Name: &name, Currency: &currency, OpeningBalance: &opening})
+	if err != nil {
+		t.Fatalf("UpdateAccount: %v", err)
+	}
+	if updated.ID != id || updated.Name != name || updated.Currency != currency || updated.OpeningBalance != opening ||
+		updated.Institution != "Monzo" || updated.Type != models.AccountTypeCurrent {
+		t.Errorf("UpdateAccount = %+v", *updated)
+	}
+
+	if err := repo.DeleteAccount(ctx, userID, id); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	assertAccountNotFound(t, func() error { _, err := repo.GetAccount(ctx, userID, id); return err }())
+}
+
+func testAccountNotFound(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	missingID := "missing" + NewUserID(t)
+
+	_, err := repo.GetAccount(ctx, userID, missingID)
+	assertAccountNotFound(t, err)
+
+	name := "Ghost"
+	_, err = repo.UpdateAccount(ctx, userID, missingID, models.AccountUpdate{Name: &name})
+	assertAccountNotFound(t, err)
+
+	assertAccountNotFound(t, repo.DeleteAccount(ctx, userID, missingID))
+
+	accounts, err := repo.ListAccounts(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListAccounts: %v", err)
+	}
+	if len(accounts) != 0 {
+		t.Errorf("ListAccounts for a user without accounts returned %v", accounts)
+	}
+}
+
+func testAccountsAreIsolated(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	alice := NewUserID(t)
+	bob := NewUserID(t)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	id, err := repo.AddAccount(ctx, alice, models.Account{Name: "Savings", Type: models.AccountTypeSavings, Currency: "GBP", CreatedAt: now, UpdatedAt: now})
+	if err != nil {
+		t.Fatalf("AddAccount: %v", err)
+	}
+
+	accounts, err := repo.ListAccounts(ctx, bob)
+	if err != nil {
+		t.Fatalf("ListAccounts: %v", err)
+	}
+	if len(accounts) != 0 {
+		t.Fatalf("ListAccounts for another user returned %v", accounts)
+	}
+	_, err = repo.GetAccount(ctx, bob, id)
+	assertAccountNotFound(t, err)
+	name := "Stolen"
+	_, err = repo.UpdateAccount(ctx, bob, id, models.AccountUpdate{Name: &name})
+	assertAccountNotFound(t, err)
+	assertAccountNotFound(t, repo.DeleteAccount(ctx, bob, id))
+
+	if got, err := repo.GetAccount(ctx, alice, id); err != nil || got.Name != "Savings" {
+		t.Errorf("GetAccount after another user's changes = %+v, %v", got, err)
+	}
+}
+
+func testBalanceCheckpoints(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	_, err := repo.AddBalanceCheckpoint(ctx, userID, "missing", models.BalanceCheckpoint{Date: now, CreatedAt: now})
+	assertAccountNotFound(t, err)
+
+	accountID, err := repo.AddAccount(ctx, userID, models.Account{Name: "Current", Type: models.AccountTypeCurrent, Currency: "GBP", CreatedAt: now, UpdatedAt: now})
+	if err != nil {
+		t.Fatalf("AddAccount: %v", err)
+	}
+	later := time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)
+	earlier := time.Date(2025, time.May, 31, 0, 0, 0, 0, time.UTC)
+	laterID, err := repo.AddBalanceCheckpoint(ctx, userID, accountID, models.BalanceCheckpoint{Date: later, Balance: 4200, Source: models.BalanceSourceManual, CreatedAt: now})
+	if err != nil {
+		t.Fatalf("AddBalanceCheckpoint: %v", err)
+	}
+	if _, err := repo.AddBalanceCheckpoint(ctx, userID, accountID, models.BalanceCheckpoint{Date: earlier, Balance: -100, Source: models.BalanceSourceStatement, CreatedAt: now}); err != nil {
+		t.Fatalf("AddBalanceCheckpoint: %v", err)
+	}
+
+	checkpoints, err := repo.ListBalanceCheckpoints(ctx, userID, accountID)
+	if err != nil {
+		t.Fatalf("ListBalanceCheckpoints: %v", err)
+	}
+	if len(checkpoints) != 2 || !checkpoints[0].Date.Equal(earlier) || !checkpoints[1].Date.Equal(later) {
+		t.Fatalf("ListBalanceCheckpoints = %+v, want oldest first", checkpoints)
+	}
+	if got := checkpoints[1]; got.ID != laterID || got.AccountID != accountID || got.Balance != 4200 || got.Source != models.BalanceSourceManual {
+		t.Errorf("checkpoint = %+v", got)
+	}
+
+	if others, err := repo.ListBalanceCheckpoints(ctx, NewUserID(t), accountID); err != nil || len(others) != 0 {
+		t.Errorf("ListBalanceCheckpoints for another user = %v, %v", others, err)
+	}
+	var notFoundErr *exceptions.BalanceCheckpointNotFoundError
+	if err := repo.DeleteBalanceCheckpoint(ctx, NewUserID(t), accountID, laterID); !errors.As(err, &notFoundErr) {
+		t.Errorf("DeleteBalanceCheckpoint for another user returned %v, want BalanceCheckpointNotFoundError", err)
+	}
+
+	if err := repo.DeleteBalanceCheckpoint(ctx, userID, accountID, laterID); err != nil {
+		t.Fatalf("DeleteBalanceCheckpoint: %v", err)
+	}
+	if err := repo.DeleteBalanceCheckpoint(ctx, userID, accountID, laterID); !errors.As(err, &notFoundErr) {
+		t.Errorf("DeleteBalanceCheckpoint twice returned %v, want BalanceCheckpointNotFoundError", err)
+	}
+
+	// Checkpoints go with their account.
+	if err := repo.DeleteAccount(ctx, userID, accountID); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	if remaining, err := repo.ListBalanceCheckpoints(ctx, userID, accountID); err != nil || len(remaining) != 0 {
+		t.Errorf("ListBalanceCheckpoints after DeleteAccount = %v, %v", remaining, err)
+	}
+}
+
+func testBudgetCRUD(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	start := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	budget := models.Budget{
+		Category:   "Groceries",
+		CategoryID: "groceries-id",
+		Period:     models.BudgetPeriodMonthly,
+		Amount:     30000,
+		Currency:   "GBP",
+		Rollover:   true,
+		StartDate:  start,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	id, err := repo.AddBudget(ctx, userID, budget)
+	if err != nil {
+		t.Fatalf("AddBudget: %v", err)
+	}
+	if id == "" {
+		t.Fatal("AddBudget returned an empty ID")
+	}
+
+	got, err := repo.GetBudget(ctx, userID, id)
+	if err != nil {
+		t.Fatalf("GetBudget: %v", err)
+	}
+	if got.ID != id || got.Category != budget.Category || got.CategoryID != budget.CategoryID || got.Period != budget.Period || got.Amount != budget.Amount ||
+		got.Currency != budget.Currency || !got.Rollover || !got.StartDate.Equal(start) {
+		t.Errorf("GetBudget = %+v, want %+v", *got, budget)
+	}
+
+	if budgets, err := repo.ListBudgets(ctx, NewUserID(t)); err != nil || len(budgets) != 0 {
+		t.Errorf("ListBudgets for another user = %v, %v", budgets, err)
+	}
+	budgets, err := repo.ListBudgets(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListBudgets: %v", err)
+	}
+	if len(budgets) != 1 || budgets[0].ID != id {
+		t.Fatalf("ListBudgets = %+v", budgets)
+	}
+
+	amount := int64(25000)
+	rollover := false
+	updated, err := repo.UpdateBudget(ctx, userID, id, models.BudgetUpdate{Amount: &amount, Rollover: &rollover})
+	if err != nil {
+		t.Fatalf("UpdateBudget: %v", err)
+	}
+	if updated.Amount != amount || updated.Rollover || updated.Category != "Groceries" || updated.CategoryID != "groceries-id" || updated.Period != models.BudgetPeriodMonthly {
+		t.Errorf("UpdateBudget = %+v", *updated)
+	}
+	category, categoryID := "Dining", "dining-id"
+	updated, err = repo.UpdateBudget(ctx, userID, id, models.BudgetUpdate{Category: &category, CategoryID: &categoryID})
+	if err != nil {
+		t.Fatalf("UpdateBudget: %v", err)
+	}
+	if updated.Category != category || updated.CategoryID != categoryID || updated.Amount != amount {
+		t.Errorf("UpdateBudget of the category = %+v", *updated)
+	}
+
+	if err := repo.DeleteBudget(ctx, userID, id); err != nil {
+		t.Fatalf("DeleteBudget: %v", err)
+	}
+	_, err = repo.GetBudget(ctx, userID, id)
+	assertBudgetNotFound(t, err)
+}
+
+func testBudgetNotFound(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	alice := NewUserID(t)
+	bob := NewUserID(t)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	id, err := repo.AddBudget(ctx, alice, models.Budget{Category: "Dining", Period: models.BudgetPeriodWeekly, Amount: 5000, Currency: "GBP", StartDate: now, CreatedAt: now, UpdatedAt: now})
+	if err != nil {
+		t.Fatalf("AddBudget: %v", err)
+	}
+
+	_, err = repo.GetBudget(ctx, bob, id)
+	assertBudgetNotFound(t, err)
+	amount := int64(1)
+	_, err = repo.UpdateBudget(ctx, bob, id, models.BudgetUpdate{Amount: &amount})
+	assertBudgetNotFound(t, err)
+	assertBudgetNotFound(t, repo.DeleteBudget(ctx, bob, id))
+
+	if got, err := repo.GetBudget(ctx, alice, id); err != nil || got.Amount != 5000 {
+		t.Errorf("GetBudget after another user's changes = %+v, %v", got, err)
+	}
+}
+
+func testAllocations(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	if _, err := repo.AddAllocations(ctx, userID, nil); err == nil {
+		t.Fatal("AddAllocations with no allocations should fail")
+	}
+
+	july, err := repo.AddAllocations(ctx, userID, []models.Allocation{
+		{Month: "2025-07", Category: "Groceries", Amount: 30000, Kind: models.AllocationKindAssign, CreatedAt: now},
+	})
+	if err != nil {
+		t.Fatalf("AddAllocations: %v", err)
+	}
+	june, err := repo.AddAllocations(ctx, userID, []models.Allocation{
+		{Month: "2025-06", Category: "Dining", Amount: -2000, Kind: models.AllocationKindMove, CreatedAt: now.Add(time.Second)},
+		{Month: "2025-06", Category: "Groceries", CategoryID: "groceries-id", Amount: 2000, Kind: models.AllocationKindMove, CreatedAt: now.Add(time.Second)},
+	})
+	if err != nil {
+		t.Fatalf("AddAllocations: %v", err)
+	}
+	if len(june) != 2 || june[0].ID == "" || june[0].ID == june[1].ID || june[1].Category != "Groceries" {
+		t.Fatalf("AddAllocations = %+v", june)
+	}
+	if _, err := repo.AddAllocations(ctx, NewUserID(t), []models.Allocation{
+		{Month: "2025-06", Category: "Other", Amount: 1, Kind: models.AllocationKindAssign, CreatedAt: now},
+	}); err != nil {
+		t.Fatalf("AddAllocations: %v", err)
+	}
+
+	got, err := repo.ListAllocations(ctx, userID, "2025-06")
+	if err != nil {
+		t.Fatalf("ListAllocations: %v", err)
+	}
+	if len(got) != 2 || got[0].Amount != -2000 || got[1].Amount != 2000 || got[0].Month != "2025-06" || got[0].Kind != models.AllocationKindMove ||
+		got[0].CategoryID != "" || got[1].CategoryID != "groceries-id" {
+		t.Errorf("ListAllocations through June = %+v", got)
+	}
+
+	got, err = repo.ListAllocations(ctx, userID, "2025-12")
+	if err != nil {
+		t.Fatalf("ListAllocations: %v", err)
+	}
+	if len(got) != 3 || got[2].ID != july[0].ID || !got[2].CreatedAt.Equal(now) {
+		t.Errorf("ListAllocations through December = %+v, want June's entries then July's", got)
+	}
+}
+
+func testRuleCRUD(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	minAmount := int64(500)
+	from := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	rule := models.CategoryRule{
+		Name:       "Big shops",
+		Priority:   2,
+		Category:   "Groceries",
+		CategoryID: "groceries-id",
+		Conditions: models.RuleConditions{
+			DescriptionRegex: "^tesco",
+			MinAmount:        &minAmount,
+			Type:             "Debit",
+			DateFrom:         &from,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	id, err := repo.AddRule(ctx, userID, rule)
+	if err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	if id == "" {
+		t.Fatal("AddRule returned an empty ID")
+	}
+
+	got, err := repo.GetRule(ctx, userID, id)
+	if err != nil {
+		t.Fatalf("GetRule: %v", err)
+	}
+	c := got.Conditions
+	if got.ID != id || got.Name != rule.Name || got.Priority != 2 || got.Category != rule.Category || got.CategoryID != rule.CategoryID ||
+		c.DescriptionRegex != "^tesco" || c.MinAmount == nil || *c.MinAmount != minAmount || c.MaxAmount != nil ||
+		c.Type != "Debit" || c.DateFrom == nil || !c.DateFrom.Equal(from) || c.DateTo != nil {
+		t.Errorf("GetRule = %+v, want %+v", *got, rule)
+	}
+
+	first, err := repo.AddRule(ctx, userID, models.CategoryRule{
+		Priority: 1, Category: "Dining", Conditions: models.RuleConditions{DescriptionContains: "pret"}, CreatedAt: now.Add(time.Second), UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	tied, err := repo.AddRule(ctx, userID, models.CategoryRule{
+		Priority: 2, Category: "Bills", Conditions: models.RuleConditions{DescriptionExact: "rent"}, CreatedAt: now.Add(2 * time.Second), UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+
+	if rules, err := repo.ListRules(ctx, NewUserID(t)); err != nil || len(rules) != 0 {
+		t.Errorf("ListRules for another user = %v, %v", rules, err)
+	}
+	rules, err := repo.ListRules(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListRules: %v", err)
+	}
+	if len(rules) != 3 || rules[0].ID != first || rules[1].ID != id || rules[2].ID != tied {
+		t.Fatalf("ListRules = %+v, want priority order with ties oldest first", rules)
+	}
+
+	priority := 3
+	category, categoryID := "Household", "household-id"
+	conditions := models.RuleConditions{DescriptionContains: "sainsbury"}
+	updated, err := repo.UpdateRule(ctx, userID, id, models.CategoryRuleUpdate{Priority: &priority, Category: &category, CategoryID: &categoryID, Conditions: &conditions})
+	if err != nil {
+		t.Fatalf("UpdateRule: %v", err)
+	}
+	if updated.Priority != 3 || updated.Name != "Big shops" || updated.Category != category || updated.CategoryID != categoryID || updated.Conditions.DescriptionContains != "sainsbury" ||
+		updated.Conditions.DescriptionRegex != "" || updated.Conditions.MinAmount != nil || updated.Conditions.DateFrom != nil {
+		t.Errorf("UpdateRule = %+v, want the conditions replaced", *updated)
+	}
+
+	if err := repo.DeleteRule(ctx, userID, id); err != nil {
+		t.Fatalf("DeleteRule: %v", err)
+	}
+	_, err = repo.GetRule(ctx, userID, id)
+	assertRuleNotFound(t, err)
+}
+
+func testRuleNotFound(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	alice := NewUserID(t)
+	bob := NewUserID(t)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	id, err := repo.AddRule(ctx, alice, models.CategoryRule{
+		Priority: 1, Category: "Dining", Conditions: models.RuleConditions{DescriptionContains: "pret"}, CreatedAt: now, UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+
+	_, err = repo.GetRule(ctx, bob, id)
+	assertRuleNotFound(t, err)
+	category := "Other"
+	_, err = repo.UpdateRule(ctx, bob, id, models.CategoryRuleUpdate{Category: &category})
+	assertRuleNotFound(t, err)
+	assertRuleNotFound(t, repo.DeleteRule(ctx, bob, id))
+
+	if got, err := repo.GetRule(ctx, alice, id); err != nil || got.Category != "Dining" {
+		t.Errorf("GetRule after another user's changes = %+v, %v", got, err)
+	}
+}
+
+func testCategoryMappings(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, mapping := range []models.CategoryMapping{
+		{Merchant: "tesco stores", Category: "Groceries", UpdatedAt: now},
+		{Merchant: "pret manger", Category: "Dining", UpdatedAt: now},
+		{Merchant: "tesco stores", Category: "Household", CategoryID: "household-id", UpdatedAt: now.Add(time.Second)},
+	} {
+		if err := repo.SetCategoryMapping(ctx, userID, mapping); err != nil {
+			t.Fatalf("SetCategoryMapping: %v", err)
+		}
+	}
+
+	if mappings, err := repo.ListCategoryMappings(ctx, NewUserID(t)); err != nil || len(mappings) != 0 {
+		t.Errorf("ListCategoryMappings for another user = %v, %v", mappings, err)
+	}
+	mappings, err := repo.ListCategoryMappings(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListCategoryMappings: %v", err)
+	}
+	if len(mappings) != 2 || mappings[0].Merchant != "pret manger" || mappings[1].Merchant != "tesco stores" ||
+		mappings[1].Category != "Household" || mappings[1].CategoryID != "household-id" || !mappings[1].UpdatedAt.Equal(now.Add(time.Second)) {
+		t.Fatalf("ListCategoryMappings = %+v, want merchant order with the later mapping replacing the first", mappings)
+	}
+
+	var notFoundErr *exceptions.CategoryMappingNotFoundError
+	if err := repo.DeleteCategoryMapping(ctx, NewUserID(t), "tesco stores"); !errors.As(err, &notFoundErr) {
+		t.Fatalf("DeleteCategoryMapping for another user = %v, want CategoryMappingNotFoundError", err)
+	}
+	if err := repo.DeleteCategoryMapping(ctx, userID, "tesco stores"); err != nil {
+		t.Fatalf("DeleteCategoryMapping: %v", err)
+	}
+	if err := repo.DeleteCategoryMapping(ctx, userID, "tesco stores"); !errors.As(err, &notFoundErr) {
+		t.Fatalf("DeleteCategoryMapping twice = %v, want CategoryMappingNotFoundError", err)
+	}
+	if mappings, err := repo.ListCategoryMappings(ctx, userID); err != nil || len(mappings) != 1 {
+		t.Errorf("ListCategoryMappings after delete = %v, %v", mappings, err)
+	}
+}
+
+func testMerchantAliases(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, alias := range []models.MerchantAlias{
+		{Pattern: "js online", Merchant: "Sainsburys", UpdatedAt: now},
+		{Pattern: "bobs", Merchant: "Bob's Barbers", UpdatedAt: now},
+		{Pattern: "js online", Merchant: "Sainsbury's", UpdatedAt: now.Add(time.Second)},
+	} {
+		if err := repo.SetMerchantAlias(ctx, userID, alias); err != nil {
+			t.Fatalf("SetMerchantAlias: %v", err)
+		}
+	}
+
+	if aliases, err := repo.ListMerchantAliases(ctx, NewUserID(t)); err != nil || len(aliases) != 0 {
+		t.Errorf("ListMerchantAliases for another user = %v, %v", aliases, err)
+	}
+	aliases, err := repo.ListMerchantAliases(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListMerchantAliases: %v", err)
+	}
+	if len(aliases) != 2 || aliases[0].Pattern != "bobs" || aliases[1].Pattern != "js online" ||
+		aliases[1].Merchant != "Sainsbury's" || !aliases[1].UpdatedAt.Equal(now.Add(time.Second)) {
+		t.Fatalf("ListMerchantAliases = %+v, want pattern order with the later alias replacing the first", aliases)
+	}
+
+	var notFoundErr *exceptions.MerchantAliasNotFoundError
+	if err := repo.DeleteMerchantAlias(ctx, NewUserID(t), "bobs"); !errors.As(err, &notFoundErr) {
+		t.Fatalf("DeleteMerchantAlias for another user = %v, want MerchantAliasNotFoundError", err)
+	}
+	if err := repo.DeleteMerchantAlias(ctx, userID, "bobs"); err != nil {
+		t.Fatalf("DeleteMerchantAlias: %v", err)
+	}
+	if err := repo.DeleteMerchantAlias(ctx, userID, "bobs"); !errors.As(err, &notFoundErr) {
+		t.Fatalf("DeleteMerchantAlias twice = %v, want MerchantAliasNotFoundError", err)
+	}
+	if aliases, err := repo.ListMerchantAliases(ctx, userID); err != nil || len(aliases) != 1 {
+		t.Errorf("ListMerchantAliases after delete = %v, %v", aliases, err)
+	}
+}
+
+func testImportProfiles(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	columns := models.ImportColumns{Date: "Posted", Description: "Payee", Debit: "Out", Credit: "In"}
+
+	for _, profile := range []models.ImportProfile{
+		{Name: "credit union", Columns: columns, DateFormat: "YYYY-MM-DD", UpdatedAt: now},
+		{Name: "amex", Columns: columns, DateFormat: "DD/MM/YYYY", InvertSign: true, UpdatedAt: now},
+		{Name: "credit union", Columns: columns, DateFormat: "DD.MM.YYYY", Header: []string{"Posted", "Payee", "Out", "In"}, UpdatedAt: now.Add(time.Second)},
+	} {
+		if err := repo.SetImportProfile(ctx, userID, profile); err != nil {
+			t.Fatalf("SetImportProfile: %v", err)
+		}
+	}
+
+	if profiles, err := repo.ListImportProfiles(ctx, NewUserID(t)); err != nil || len(profiles) != 0 {
+		t.Errorf("ListImportProfiles for another user = %v, %v", profiles, err)
+	}
+	profiles, err := repo.ListImportProfiles(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListImportProfiles: %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "amex" || !profiles[0].InvertSign || profiles[1].Name != "credit union" {
+		t.Fatalf("ListImportProfiles = %+v, want name order", profiles)
+	}
+	if union := profiles[1]; union.DateFormat != "DD.MM.YYYY" || union.Columns != columns || len(union.Header) != 4 ||
+		!union.UpdatedAt.Equal(now.Add(time.Second)) {
+		t.Errorf("credit union = %+v, want the later profile to replace the first", union)
+	}
+
+	var notFoundErr *exceptions.ImportProfileNotFoundError
+	if err := repo.DeleteImportProfile(ctx, NewUserID(t), "amex"); !errors.As(err, &notFoundErr) {
+		t.Fatalf("DeleteImportProfile for another user = %v, want ImportProfileNotFoundError", err)
+	}
+	if err := repo.DeleteImportProfile(ctx, userID, "amex"); err != nil {
+		t.Fatalf("DeleteImportProfile: %v", err)
+	}
+	if err := repo.DeleteImportProfile(ctx, userID, "amex"); !errors.As(err, &notFoundErr) {
+		t.Fatalf("DeleteImportProfile twice = %v, want ImportProfileNotFoundError", err)
+	}
+	if profiles, err := repo.ListImportProfiles(ctx, userID); err != nil || len(profiles) != 1 {
+		t.Errorf("ListImportProfiles after delete = %v, %v", profiles, err)
+	}
+}
+
+func testTransactionMerchant(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	transaction := NewTransaction(userID, "CARD PAYMENT TO TESCO STORES 3021", -1250)
+	transaction.Merchant = "Tesco"
+	ids := addTransactions(t, repo, userID, transaction, NewTransaction(userID, "UNKNOWN", -100))
+
+	got, err := repo.GetTransactionByID(ctx, userID, ids[0])
+	if err != nil || got.Merchant != "Tesco" {
+		t.Fatalf("GetTransactionByID = %+v, %v, want merchant Tesco", got, err)
+	}
+	listed, err := repo.ListTransactions(ctx, userID, map[string]string{"merchant": "Tesco"})
+	if err != nil || len(listed) != 1 || listed[0].ID != ids[0] {
+		t.Fatalf("ListTransactions by merchant = %+v, %v", listed, err)
+	}
+
+	description, merchant := "SAINSBURYS S/MKT", "Sainsbury's"
+	updated, err := repo.UpdateTransaction(ctx, userID, ids[0], models.TransactionUpdate{Description: &description, Merchant: &merchant})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if updated.Merchant != merchant || updated.Description != description {
+		t.Errorf("UpdateTransaction = %+v, want merchant %q", updated, merchant)
+	}
+	amount := int64(-1300)
+	if updated, err := repo.UpdateTransaction(ctx, userID, ids[0], models.TransactionUpdate{Amount: &amount}); err != nil || updated.Merchant != merchant {
+		t.Errorf("UpdateTransaction without a merchant = %+v, %v, want merchant kept", updated, err)
+	}
+}
+
+func testTransactionValueDate(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	valueDate := time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC)
+	transaction := NewTransaction(userID, "ACME GMBH", 150010)
+	transaction.ValueDate = &valueDate
+	transaction.Remittance = "Invoice 2025-117"
+	ids := addTransactions(t, repo, userID, transaction, NewTransaction(userID, "UNKNOWN", -100))
+
+	got, err := repo.GetTransactionByID(ctx, userID, ids[0])
+	if err != nil || got.ValueDate == nil || !got.ValueDate.Equal(valueDate) || got.Remittance != transaction.Remittance {
+		t.Fatalf("GetTransactionByID = %+v, %v, want value date %v and remittance", got, err, valueDate)
+	}
+	if got, err := repo.GetTransactionByID(ctx, userID, ids[1]); err != nil || got.ValueDate != nil || got.Remittance != "" {
+		t.Errorf("GetTransactionByID without a value date = %+v, %v", got, err)
+	}
+}
+
+func testTransactionCategoryMatch(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	transaction := NewTransaction(userID, "CARD PAYMENT TO TFL", -280)
+	transaction.Category = "Transport"
+	transaction.CategoryMatch = &models.CategoryMatch{
+		Source:   models.CategorySourceKeyword,
+		Category: "Transport",
+		Keyword:  "tfl",
+		Span:     &models.TextSpan{Start: 16, End: 19, Text: "TFL"},
+	}
+	id, err := repo.AddTransaction(ctx, userID, transaction)
+	if err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+	plain, err := repo.AddTransaction(ctx, userID, NewTransaction(userID, "NO EXPLANATION", -100))
+	if err != nil {
+		t.Fatalf("AddTransaction: %v", err)
+	}
+
+	got, err := repo.GetTransactionByID(ctx, userID, id)
+	if err != nil {
+		t.Fatalf("GetTransactionByID: %v", err)
+	}
+	match := got.CategoryMatch
+	if match == nil || match.Source != models.CategorySourceKeyword || match.Keyword != "tfl" || match.Span == nil || *match.Span != *transaction.CategoryMatch.Span {
+		t.Errorf("CategoryMatch = %+v, want %+v", match, transaction.CategoryMatch)
+	}
+	if got, err := repo.GetTransactionByID(ctx, userID, plain); err != nil || got.CategoryMatch != nil {
+		t.Errorf("GetTransactionByID without an explanation = %+v, %v", got, err)
+	}
+
+	category := "Travel"
+	updated, err := repo.UpdateTransaction(ctx, userID, id, models.TransactionUpdate{
+		Category:      &category,
+		CategoryMatch: &models.CategoryMatch{Source: models.CategorySourceManual, Category: category},
+	})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if m := updated.CategoryMatch; m == nil || m.Source != models.CategorySourceManual || m.Category != category || m.Keyword != "" || m.Span != nil {
+		t.Errorf("CategoryMatch after update = %+v, want it replaced by a manual match", m)
+	}
+}
+
+func testTransactionSplits(t *testing.T, repo db.Repository) {
+	ctx := context.Background()
+	userID := NewUserID(t)
+
+	ids := addTransactions(t, repo, userID, NewTransaction(userID, "TESCO STORES", -6000))
+	splits := []models.Split{
+		{Amount: -4500, Category: "Groceries", CategoryID: "groceries"},
+		{Amount: -1500, Category: "Household", CategoryID: "household", Note: "cleaning"},
+	}
+	updated, err := repo.UpdateTransaction(ctx, userID, ids[0], models.TransactionUpdate{Splits: &splits})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if len(updated.Splits) != 2 {
+		t.Fatalf("Splits after update = %+v, want 2 lines", updated.Splits)
+	}
+	got, err := repo.GetTransactionByID(ctx, userID, ids[0])
+	if err != nil {
+		t.Fatalf("GetTransactionByID: %v", err)
+	}
+	if len(got.Splits) != 2 || got.Splits[0] != splits[0] || got.Splits[1] != splits[1] {
+		t.Errorf("Splits = %+v, want %+v", got.Splits, splits)
+	}
+	if got.Amount != -6000 || got.Description != "TESCO STORES" {
+		t.Errorf("splitting changed the transaction: %+v", got)
+	}
+
+	updated, err = repo.UpdateTransaction(ctx, userID, ids[0], models.TransactionUpdate{Splits: &[]models.Split{}})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if len(updated.Splits) != 0 {
+		t.Errorf("Splits after removal = %+v, want none", updated.Splits)
+	}
+	if listed, err := repo.ListTransactions(ctx, userID, nil); err != nil || len(listed) != 1 || len(listed[0].Splits) != 0 {
+		t.Errorf("ListTransactions after removing splits = %+v, %v", listed, err)
+	}
+}
+
+// NewUserID returns a user ID that is unique to this test run.
+func NewUserID(t *testing.T) string {
+	t.Helper()
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+	return "test-user-" + hex.EncodeToString(b)
+}
+
+// NewTransaction returns a fully populated transaction for userID. Times are
+// rounded so they survive storage with microsecond precision.
+func NewTransaction(userID, description string, amount int64) models.Transaction {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	transactionType := "Debit"
+	if amount > 0 {
+		transactionType = "Credit"
+	}
+	return models.Transaction{
+		UserID:              userID,
+		TransactionDateTime: time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC),
+		Description:         description,
+		Amount:              amount,
+		Currency:            "GBP",
+		Category:            "Other",
+		Type:                transactionType,
+		BankReference:       "REF-" + description,
+		InsertedAt:          now,
+		UpdatedAt:           now,
+	}
+}
+
+// addTransactions stores transactions for userID and returns their IDs in
+// order.
+func addTransactions(t *testing.T, repo db.Repository, userID string, transactions ...models.Transaction) []string {
+	t.Helper()
+	ids := make([]string, 0, len(transactions))
+	for _, transaction := range transactions {
+		id, err := repo.AddTransaction(context.Background(), userID, transaction)
+		if err != nil {
+			t.Fatalf("AddTransaction: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func listCategoriesByName(t *testing.T, repo db.Repository, userID string) map[string]models.UserCategory {
+	t.Helper()
+	categories, err := repo.ListUserCategories(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ListUserCategories: %v", err)
+	}
+	byName := make(map[string]models.UserCategory, len(categories))
+	for _, cat := range categories {
+		byName[cat.Name] = cat
+	}
+	return byName
+}
+
+func assertTransactionEqual(t *testing.T, got *models.Transaction, want models.Transaction) {
+	t.Helper()
+	if got.ID != want.ID ||
+		got.UserID != want.UserID ||
+		got.AccountID != want.AccountID ||
+		got.Description != want.Description ||
+		got.Amount != want.Amount ||
+		got.Currency != want.Currency ||
+		got.Category != want.Category ||
+		got.Type != want.Type ||
+		got.BankReference != want.BankReference ||
+		got.TransferID != want.TransferID ||
+		!got.TransactionDateTime.Equal(want.TransactionDateTime) ||
+		!got.InsertedAt.Equal(want.InsertedAt) ||
+		!got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("transaction = %+v, want %+v", *got, want)
+	}
+}
+
+func assertTransactionNotFound(t *testing.T, err error) {
+	t.Helper()
+	var notFoundErr *exceptions.TransactionNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("got error %v, want TransactionNotFoundError", err)
+	}
+}
+
+func assertAccountNotFound(t *testing.T, err error) {
+	t.Helper()
+	var notFoundErr *exceptions.AccountNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("got error %v, want AccountNotFoundError", err)
+	}
+}
+
+func assertBudgetNotFound(t *testing.T, err error) {
+	t.Helper()
+	var notFoundErr *exceptions.BudgetNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("got error %v, want BudgetNotFoundError", err)
+	}
+}
+
+func assertRuleNotFound(t *testing.T, err error) {
+	t.Helper()
+	var notFoundErr *exceptions.RuleNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("got error %v, want RuleNotFoundError", err)
+	}
+}
+
+func assertUserForbidden(t *testing.T, err error) {
+	t.Helper()
+	var forbiddenErr *exceptions.UserForbiddenError
+	if !errors.As(err, &forbiddenErr) {
+		t.Fatalf("got error %v, want UserForbiddenError", err)
+	}
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (r *FirestoreRepository) AddAccount(ctx context.Context, userID string, account models.Account) (string, error) {
+	col := r.client.Collection("users").Doc(userID).Collection("accounts")
+	ref, _, err := col.Add(ctx, account)
+	if err != nil {
+		return "", fmt.Errorf("failed to add account: %w", err)
+	}
+	return ref.ID, nil
+}
+
+func (r *FirestoreRepository) GetAccount(ctx context.Context, userID, accountID string) (*models.Account, error) {
+	doc, err := r.client.Collection("users").Doc(userID).Collection("accounts").Doc(accountID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, exceptions.AccountNotFound(accountID)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	var account models.Account
+	if err := doc.DataTo(&account); err != nil {
+		return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+	}
+	account.ID = doc.Ref.ID
+	return &account, nil
+}
+
+func (r *FirestoreRepository) ListAccounts(ctx context.Context, userID string) ([]models.Account, error) {
+	iter := r.client.Collection("users").Doc(userID).Collection("accounts").OrderBy("createdAt", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+
+	var accounts []models.Account
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				return accounts, nil
+			}
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+		var account models.Account
+		if err := doc.DataTo(&account); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		account.ID = doc.Ref.ID
+		accounts = append(accounts, account)
+	}
+}
+
+func (r *FirestoreRepository) UpdateAccount(ctx context.Context, userID, accountID string, updateData models.AccountUpdate) (*models.Account, error) {
+	updates := []firestore.Update{{Path: "updatedAt", Value: time.Now()}}
+	if updateData.Name != nil {
+		updates = append(updates, firestore.Update{Path: "name", Value: *updateData.Name})
+	}
+	if updateData.Institution != nil {
+		updates = append(updates, firestore.Update{Path: "institution", Value: *updateData.Institution})
+	}
+	if updateData.Type != nil {
+		updates = append(updates, firestore.Update{Path: "type", Value: *updateData.Type})
+	}
+	if updateData.Currency != nil {
+		updates = append(updates, firestore.Update{Path: "currency", Value: *updateData.Currency})
+	}
+	if updateData.OpeningBalance != nil {
+		updates = append(updates, firestore.Update{Path: "openingBalance", Value: *updateData.OpeningBalance})
+	}
+
+	_, err := r.client.Collection("users").Doc(userID).Collection("accounts").Doc(accountID).Update(ctx, updates)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, exceptions.AccountNotFound(accountID)
+		}
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+	return r.GetAccount(ctx, userID, accountID)
+}
+
+func (r *FirestoreRepository) DeleteAccount(ctx context.Context, userID, accountID string) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("accounts").Doc(accountID)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return exceptions.AccountNotFound(accountID)
+		}
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	// Subcollections outlive their parent document, so remove the
+	// checkpoints explicitly.
+	refs, err := docRef.Collection("balanceCheckpoints").DocumentRefs(ctx).GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to list balance checkpoints: %w", err)
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	writer := r.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(refs))
+	for _, ref := range refs {
+		job, err := writer.Delete(ref)
+		if err != nil {
+			writer.End()
+			return fmt.Errorf("failed to delete balance checkpoint: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	writer.End()
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return fmt.Errorf("failed to delete balance checkpoint: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *FirestoreRepository) AddBalanceCheckpoint(ctx context.Context, userID, accountID string, checkpoint models.BalanceCheckpoint) (string, error) {
+	if _, err := r.GetAccount(ctx, userID, accountID); err != nil {
+		return "", err
+	}
+	col := r.client.Collection("users").Doc(userID).Collection("accounts").Doc(accountID).Collection("balanceCheckpoints")
+	ref, _, err := col.Add(ctx, checkpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to add balance checkpoint: %w", err)
+	}
+	return ref.ID, nil
+}
+
+func (r *FirestoreRepository) ListBalanceCheckpoints(ctx context.Context, userID, accountID string) ([]models.BalanceCheckpoint, error) {
+	iter := r.client.Collection("users").Doc(userID).Collection("accounts").Doc(accountID).
+		Collection("balanceCheckpoints").OrderBy("date", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+
+	var checkpoints []models.BalanceCheckpoint
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				return checkpoints, nil
+			}
+			return nil, fmt.Errorf("failed to list balance checkpoints: %w", err)
+		}
+		var checkpoint models.BalanceCheckpoint
+		if err := doc.DataTo(&checkpoint); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		checkpoint.ID = doc.Ref.ID
+		checkpoint.AccountID = accountID
+		checkpoints = append(checkpoints, checkpoint)
+	}
+}
+
+func (r *FirestoreRepository) DeleteBalanceCheckpoint(ctx context.Context, userID, accountID, checkpointID string) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("accounts").Doc(accountID).Collection("balanceCheckpoints").Doc(checkpointID)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return exceptions.BalanceCheckpointNotFound(checkpointID)
+		}
+		return fmt.Errorf("failed to delete balance checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+// AddAllocations writes the entries in a single transaction so that both
+// halves of a move land together.
+func (r *FirestoreRepository) AddAllocations(ctx context.Context, userID string, allocations []models.Allocation) ([]models.Allocation, error) {
+	if len(allocations) == 0 {
+		return nil, fmt.Errorf("no allocations to add")
+	}
+
+	collection := r.client.Collection("users").Doc(userID).Collection("allocations")
+	refs := make([]*firestore.DocumentRef, len(allocations))
+	for i := range allocations {
+		refs[i] = collection.NewDoc()
+	}
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for i, allocation := range allocations {
+			if err := tx.Create(refs[i], allocation); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add allocations: %w", err)
+	}
+
+	added := make([]models.Allocation, 0, len(allocations))
+	for i, allocation := range allocations {
+		allocation.ID = refs[i].ID
+		added = append(added, allocation)
+	}
+	return added, nil
+}
+
+func (r *FirestoreRepository) ListAllocations(ctx context.Context, userID, throughMonth string) ([]models.Allocation, error) {
+	iter := r.client.Collection("users").Doc(userID).Collection("allocations").
+		Where("month", "<=", throughMonth).Documents(ctx)
+	defer iter.Stop()
+
+	var allocations []models.Allocation
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, fmt.Errorf("failed to list allocations: %w", err)
+		}
+		var allocation models.Allocation
+		if err := doc.DataTo(&allocation); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		allocation.ID = doc.Ref.ID
+		allocations = append(allocations, allocation)
+	}
+	// Sorted here rather than in the query to avoid needing a composite index.
+	sortAllocations(allocations)
+	return allocations, nil
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (r *FirestoreRepository) AddBudget(ctx context.Context, userID string, budget models.Budget) (string, error) {
+	col := r.client.Collection("users").Doc(userID).Collection("budgets")
+	ref, _, err := col.Add(ctx, budget)
+	if err != nil {
+		return "", fmt.Errorf("failed to add budget: %w", err)
+	}
+	return ref.ID, nil
+}
+
+func (r *FirestoreRepository) GetBudget(ctx context.Context, userID, budgetID string) (*models.Budget, error) {
+	doc, err := r.client.Collection("users").Doc(userID).Collection("budgets").Doc(budgetID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, exceptions.BudgetNotFound(budgetID)
+		}
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+	var budget models.Budget
+	if err := doc.DataTo(&budget); err != nil {
+		return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+	}
+	budget.ID = doc.Ref.ID
+	return &budget, nil
+}
+
+func (r *FirestoreRepository) ListBudgets(ctx context.Context, userID string) ([]models.Budget, error) {
+	iter := r.client.Collection("users").Doc(userID).Collection("budgets").OrderBy("createdAt", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+
+	var budgets []models.Budget
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				return budgets, nil
+			}
+			return nil, fmt.Errorf("failed to list budgets: %w", err)
+		}
+		var budget models.Budget
+		if err := doc.DataTo(&budget); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		budget.ID = doc.Ref.ID
+		budgets = append(budgets, budget)
+	}
+}
+
+func (r *FirestoreRepository) UpdateBudget(ctx context.Context, userID, budgetID string, updateData models.BudgetUpdate) (*models.Budget, error) {
+	updates := []firestore.Update{{Path: "updatedAt", Value: time.Now()}}
+	if updateData.Category != nil {
+		updates = append(updates, firestore.Update{Path: "category", Value: *updateData.Category})
+	}
+	if updateData.CategoryID != nil {
+		updates = append(updates, firestore.Update{Path: "categoryId", Value: *updateData.CategoryID})
+	}
+	if updateData.Period != nil {
+		updates = append(updates, firestore.Update{Path: "period", Value: *updateData.Period})
+	}
+	if updateData.Amount != nil {
+		updates = append(updates, firestore.Update{Path: "amount", Value: *updateData.Amount})
+	}
+	if updateData.Currency != nil {
+		updates = append(updates, firestore.Update{Path: "currency", Value: *updateData.Currency})
+	}
+	if updateData.Rollover != nil {
+		updates = append(updates, firestore.Update{Path: "rollover", Value: *updateData.Rollover})
+	}
+	if updateData.StartDate != nil {
+		updates = append(updates, firestore.Update{Path: "startDate", Value: *updateData.StartDate})
+	}
+
+	_, err := r.client.Collection("users").Doc(userID).Collection("budgets").Doc(budgetID).Update(ctx, updates)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, exceptions.BudgetNotFound(budgetID)
+		}
+		return nil, fmt.Errorf("failed to update budget: %w", err)
+	}
+	return r.GetBudget(ctx, userID, budgetID)
+}
+
+func (r *FirestoreRepository) DeleteBudget(ctx context.Context, userID, budgetID string) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("budgets").Doc(budgetID)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return exceptions.BudgetNotFound(budgetID)
+		}
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	return nil
+}
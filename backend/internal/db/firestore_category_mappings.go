@@ -0,0 +1,58 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Category mappings are keyed by merchant, which only ever holds lowercase
+// letters, digits and spaces, so it is safe to use as the document ID.
+
+func (r *FirestoreRepository) SetCategoryMapping(ctx context.Context, userID string, mapping models.CategoryMapping) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("categoryMappings").Doc(mapping.Merchant)
+	if _, err := docRef.Set(ctx, mapping); err != nil {
+		return fmt.Errorf("failed to set category mapping: %w", err)
+	}
+	return nil
+}
+
+func (r *FirestoreRepository) ListCategoryMappings(ctx context.Context, userID string) ([]models.CategoryMapping, error) {
+	iter := r.client.Collection("users").Doc(userID).Collection("categoryMappings").Documents(ctx)
+	defer iter.Stop()
+
+	var mappings []models.CategoryMapping
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				return mappings, nil
+			}
+			return nil, fmt.Errorf("failed to list category mappings: %w", err)
+		}
+		var mapping models.CategoryMapping
+		if err := doc.DataTo(&mapping); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		mapping.Merchant = doc.Ref.ID
+		mappings = append(mappings, mapping)
+	}
+}
+
+func (r *FirestoreRepository) DeleteCategoryMapping(ctx context.Context, userID, merchant string) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("categoryMappings").Doc(merchant)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return exceptions.CategoryMappingNotFound(merchant)
+		}
+		return fmt.Errorf("failed to delete category mapping: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Import profiles are keyed by name, which is validated to hold only
+// lowercase letters, digits, spaces, hyphens and underscores, so it is safe
+// to use as the document ID.
+
+func (r *FirestoreRepository) SetImportProfile(ctx context.Context, userID string, profile models.ImportProfile) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("importProfiles").Doc(profile.Name)
+	if _, err := docRef.Set(ctx, profile); err != nil {
+		return fmt.Errorf("failed to set import profile: %w", err)
+	}
+	return nil
+}
+
+func (r *FirestoreRepository) ListImportProfiles(ctx context.Context, userID string) ([]models.ImportProfile, error) {
+	iter := r.client.Collection("users").Doc(userID).Collection("importProfiles").Documents(ctx)
+	defer iter.Stop()
+
+	var profiles []models.ImportProfile
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				return profiles, nil
+			}
+			return nil, fmt.Errorf("failed to list import profiles: %w", err)
+		}
+		var profile models.ImportProfile
+		if err := doc.DataTo(&profile); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		profile.Name = doc.Ref.ID
+		profiles = append(profiles, profile)
+	}
+}
+
+func (r *FirestoreRepository) DeleteImportProfile(ctx context.Context, userID, name string) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("importProfiles").Doc(name)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return exceptions.ImportProfileNotFound(name)
+		}
+		return fmt.Errorf("failed to delete import profile: %w", err)
+	}
+	return nil
+}
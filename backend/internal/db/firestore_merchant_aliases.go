@@ -0,0 +1,58 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Merchant aliases are keyed by pattern, which only ever holds lowercase
+// letters, digits and spaces, so it is safe to use as the document ID.
+
+func (r *FirestoreRepository) SetMerchantAlias(ctx context.Context, userID string, alias models.MerchantAlias) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("merchantAliases").Doc(alias.Pattern)
+	if _, err := docRef.Set(ctx, alias); err != nil {
+		return fmt.Errorf("failed to set merchant alias: %w", err)
+	}
+	return nil
+}
+
+func (r *FirestoreRepository) ListMerchantAliases(ctx context.Context, userID string) ([]models.MerchantAlias, error) {
+	iter := r.client.Collection("users").Doc(userID).Collection("merchantAliases").Documents(ctx)
+	defer iter.Stop()
+
+	var aliases []models.MerchantAlias
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				return aliases, nil
+			}
+			return nil, fmt.Errorf("failed to list merchant aliases: %w", err)
+		}
+		var alias models.MerchantAlias
+		if err := doc.DataTo(&alias); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		alias.Pattern = doc.Ref.ID
+		aliases = append(aliases, alias)
+	}
+}
+
+func (r *FirestoreRepository) DeleteMerchantAlias(ctx context.Context, userID, pattern string) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("merchantAliases").Doc(pattern)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return exceptions.MerchantAliasNotFound(pattern)
+		}
+		return fmt.Errorf("failed to delete merchant alias: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"backend/internal/money"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type firestoreMigration struct {
+	name string
+	run  func(ctx context.Context, client *firestore.Client) error
+}
+
+// firestoreMigrations are data migrations for documents written by older
+// versions of the API. They run in order and each is recorded in the
+// schemaMigrations collection once it has completed.
+var firestoreMigrations = []firestoreMigration{
+	{name: "0001_transaction_currency", run: backfillTransactionCurrency},
+}
+
+// Migrate applies any Firestore data migrations that have not yet been run.
+func (r *FirestoreRepository) Migrate(ctx context.Context) error {
+	for _, migration := range firestoreMigrations {
+		docRef := r.client.Collection("schemaMigrations").Doc(migration.name)
+		_, err := docRef.Get(ctx)
+		if err == nil {
+			continue
+		}
+		if status.Code(err) != codes.NotFound {
+			return fmt.Errorf("failed to read migration %s: %w", migration.name, err)
+		}
+
+		if err := migration.run(ctx, r.client); err != nil {
+			return fmt.Errorf("migration %s failed: %w", migration.name, err)
+		}
+		if _, err := docRef.Set(ctx, map[string]interface{}{"appliedAt": time.Now()}); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", migration.name, err)
+		}
+		log.Printf("Applied migration %s", migration.name)
+	}
+	return nil
+}
+
+// backfillTransactionCurrency sets the default currency on transactions
+// stored when amounts were int32 pence with no currency.
+func backfillTransactionCurrency(ctx context.Context, client *firestore.Client) error {
+	iter := client.CollectionGroup("transactions").Documents(ctx)
+	defer iter.Stop()
+
+	writer := client.BulkWriter(ctx)
+	var jobs []*firestore.BulkWriterJob
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			writer.End()
+			return err
+		}
+		if _, err := doc.DataAt("currency"); err == nil {
+			continue
+		}
+		job, err := writer.Update(doc.Ref, []firestore.Update{{Path: "currency", Value: money.DefaultCurrency}})
+		if err != nil {
+			writer.End()
+			return err
+		}
+		jobs = append(jobs, job)
+	}
+	writer.End()
+
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (r *FirestoreRepository) AddRule(ctx context.Context, userID string, rule models.CategoryRule) (string, error) {
+	col := r.client.Collection("users").Doc(userID).Collection("rules")
+	ref, _, err := col.Add(ctx, rule)
+	if err != nil {
+		return "", fmt.Errorf("failed to add rule: %w", err)
+	}
+	return ref.ID, nil
+}
+
+func (r *FirestoreRepository) GetRule(ctx context.Context, userID, ruleID string) (*models.CategoryRule, error) {
+	doc, err := r.client.Collection("users").Doc(userID).Collection("rules").Doc(ruleID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, exceptions.RuleNotFound(ruleID)
+		}
+		return nil, fmt.Errorf("failed to get rule: %w", err)
+	}
+	var rule models.CategoryRule
+	if err := doc.DataTo(&rule); err != nil {
+		return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+	}
+	rule.ID = doc.Ref.ID
+	return &rule, nil
+}
+
+// ListRules sorts in memory rather than ordering on two fields, which would
+// need a composite index. Users have few enough rules for this not to matter.
+func (r *FirestoreRepository) ListRules(ctx context.Context, userID string) ([]models.CategoryRule, error) {
+	iter := r.client.Collection("users").Doc(userID).Collection("rules").Documents(ctx)
+	defer iter.Stop()
+
+	var rules []models.CategoryRule
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, fmt.Errorf("failed to list rules: %w", err)
+		}
+		var rule models.CategoryRule
+		if err := doc.DataTo(&rule); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		rule.ID = doc.Ref.ID
+		rules = append(rules, rule)
+	}
+	sortRules(rules)
+	return rules, nil
+}
+
+func (r *FirestoreRepository) UpdateRule(ctx context.Context, userID, ruleID string, updateData models.CategoryRuleUpdate) (*models.CategoryRule, error) {
+	updates := []firestore.Update{{Path: "updatedAt", Value: time.Now()}}
+	if updateData.Name != nil {
+		updates = append(updates, firestore.Update{Path: "name", Value: *updateData.Name})
+	}
+	if updateData.Priority != nil {
+		updates = append(updates, firestore.Update{Path: "priority", Value: *updateData.Priority})
+	}
+	if updateData.Category != nil {
+		updates = append(updates, firestore.Update{Path: "category", Value: *updateData.Category})
+	}
+	if updateData.CategoryID != nil {
+		updates = append(updates, firestore.Update{Path: "categoryId", Value: *updateData.CategoryID})
+	}
+	if updateData.Conditions != nil {
+		// Replace the conditions wholesale so that ones left out are cleared.
+		updates = append(updates, firestore.Update{Path: "conditions", Value: *updateData.Conditions})
+	}
+
+	_, err := r.client.Collection("users").Doc(userID).Collection("rules").Doc(ruleID).Update(ctx, updates)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, exceptions.RuleNotFound(ruleID)
+		}
+		return nil, fmt.Errorf("failed to update rule: %w", err)
+	}
+	return r.GetRule(ctx, userID, ruleID)
+}
+
+func (r *FirestoreRepository) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	docRef := r.client.Collection("users").Doc(userID).Collection("rules").Doc(ruleID)
+	if _, err := docRef.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return exceptions.RuleNotFound(ruleID)
+		}
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+	return nil
+}
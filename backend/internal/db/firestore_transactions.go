@@ -3,6 +3,7 @@ package db
 import (
 	"backend/internal/exceptions"
 	"backend/internal/models"
+	"backend/internal/money"
 	"context"
 	"errors"
 	"fmt"
@@ -30,7 +31,7 @@ func (r *FirestoreRepository) GetTransactionByID(ctx context.Context, userID, tr
 		if status.Code(err) == codes.NotFound {
 			return nil, exceptions.TransactionNotFound(transactionID)
 		}
-		return nil, fmt.Errorf(exceptions.FailedToGetTransactionMessage, err)
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToGetTransactionMessage, err)
 	}
 	var transaction models.Transaction
 	if err := doc.DataTo(&transaction); err != nil {
@@ -42,6 +43,7 @@ func (r *FirestoreRepository) GetTransactionByID(ctx context.Context, userID, tr
 
 	transaction.ID = doc.Ref.ID
 	transaction.UserID = ""
+	withLegacyDefaults(&transaction)
 	return &transaction, nil
 }
 
@@ -66,7 +68,7 @@ func (r *FirestoreRepository) ListTransactions(ctx context.Context, userID strin
 			if status.Code(err) == codes.NotFound {
 				return transactions, nil
 			}
-			return nil, fmt.Errorf(exceptions.FailedToListTransactionsMessage)
+			return nil, fmt.Errorf("%s: %w", exceptions.FailedToListTransactionsMessage, err)
 		}
 
 		var transaction models.Transaction
@@ -75,6 +77,7 @@ func (r *FirestoreRepository) ListTransactions(ctx context.Context, userID strin
 		}
 		transaction.ID = doc.Ref.ID
 		transaction.UserID = ""
+		withLegacyDefaults(&transaction)
 		transactions = append(transactions, transaction)
 	}
 }
@@ -84,16 +87,31 @@ func (r *FirestoreRepository) BulkAddTransactions(ctx context.Context, userID st
 		return nil, fmt.Errorf("no transactions to add")
 	}
 
+	collection := r.client.Collection("users").Doc(userID).Collection("transactions")
+	writer := r.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(transactions))
+	added := make([]models.Transaction, 0, len(transactions))
 	for _, transaction := range transactions {
-		docRef := r.client.Collection("users").Doc(transaction.UserID).Collection("transactions").NewDoc()
-		_, err := docRef.Set(ctx, transaction)
+		docRef := collection.NewDoc()
+		job, err := writer.Create(docRef, transaction)
 		if err != nil {
-			return nil, fmt.Errorf(exceptions.FailedToCreateTransactionMessage)
+			writer.End()
+			return nil, fmt.Errorf("%s: %w", exceptions.FailedToCreateTransactionMessage, err)
 		}
+		jobs = append(jobs, job)
+
 		transaction.ID = docRef.ID
 		transaction.UserID = ""
+		added = append(added, transaction)
+	}
+	writer.End()
+
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return nil, fmt.Errorf("%s: %w", exceptions.FailedToCreateTransactionMessage, err)
+		}
 	}
-	return transactions, nil
+	return added, nil
 }
 
 func (r *FirestoreRepository) UpdateTransaction(ctx context.Context, userID, transactionID string, updateData models.TransactionUpdate) (*models.Transaction, error) {
@@ -103,7 +121,7 @@ func (r *FirestoreRepository) UpdateTransaction(ctx context.Context, userID, tra
 		if status.Code(err) == codes.NotFound {
 			return nil, exceptions.TransactionNotFound(transactionID)
 		}
-		return nil, fmt.Errorf(exceptions.FailedToGetTransactionMessage, err)
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToGetTransactionMessage, err)
 	}
 
 	var transaction models.Transaction
@@ -117,15 +135,19 @@ func (r *FirestoreRepository) UpdateTransaction(ctx context.Context, userID, tra
 
 	updateMap := toUpdateMap(updateData)
 	updateMap["updatedAt"] = time.Now()
+	if _, ok := updateMap["currency"]; !ok && transaction.Currency == "" {
+		// Backfill documents written before currencies were stored.
+		updateMap["currency"] = money.DefaultCurrency
+	}
 
 	_, err = docRef.Set(ctx, updateMap, firestore.MergeAll)
 	if err != nil {
-		return nil, fmt.Errorf(exceptions.FailedToUpdateTransactionMessage, err)
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToUpdateTransactionMessage, err)
 	}
 
 	doc, err = docRef.Get(ctx)
 	if err != nil {
-		return nil, fmt.Errorf(exceptions.FailedToGetTransactionMessage, err)
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToGetTransactionMessage, err)
 	}
 	if err := doc.DataTo(&transaction); err != nil {
 		return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
@@ -133,6 +155,7 @@ func (r *FirestoreRepository) UpdateTransaction(ctx context.Context, userID, tra
 
 	transaction.ID = doc.Ref.ID
 	transaction.UserID = ""
+	withLegacyDefaults(&transaction)
 	return &transaction, nil
 }
 
@@ -143,7 +166,7 @@ func (r *FirestoreRepository) DeleteTransaction(ctx context.Context, userID, tra
 		if status.Code(err) == codes.NotFound {
 			return exceptions.TransactionNotFound(transactionID)
 		}
-		return fmt.Errorf(exceptions.FailedToGetTransactionMessage, err)
+		return fmt.Errorf("%s: %w", exceptions.FailedToGetTransactionMessage, err)
 	}
 
 	var transaction models.Transaction
@@ -157,22 +180,53 @@ func (r *FirestoreRepository) DeleteTransaction(ctx context.Context, userID, tra
 
 	_, err = docRef.Delete(ctx)
 	if err != nil {
-		return fmt.Errorf(exceptions.FailedToDeleteTransactionMessage, err)
+		return fmt.Errorf("%s: %w", exceptions.FailedToDeleteTransactionMessage, err)
 	}
 	return nil
 }
 
 func toUpdateMap(update models.TransactionUpdate) map[string]interface{} {
 	result := make(map[string]interface{})
+	if update.AccountID != nil {
+		result["accountId"] = *update.AccountID
+	}
 	if update.Description != nil {
 		result["description"] = *update.Description
 	}
+	if update.Merchant != nil {
+		result["merchant"] = *update.Merchant
+	}
 	if update.Amount != nil {
 		result["amount"] = *update.Amount
 	}
+	if update.Currency != nil {
+		result["currency"] = *update.Currency
+	}
 	if update.Category != nil {
 		result["category"] = *update.Category
 	}
+	if update.CategoryID != nil {
+		result["categoryId"] = *update.CategoryID
+	}
+	if update.CategoryMatch != nil {
+		// A struct is written as a single value, so this replaces the whole
+		// explanation rather than merging into the old one.
+		result["categoryMatch"] = update.CategoryMatch
+	}
+	if update.Splits != nil {
+		if len(*update.Splits) == 0 {
+			result["splits"] = firestore.Delete
+		} else {
+			result["splits"] = *update.Splits
+		}
+	}
+	if update.TransferID != nil {
+		if *update.TransferID == "" {
+			result["transferId"] = firestore.Delete
+		} else {
+			result["transferId"] = *update.TransferID
+		}
+	}
 	if update.Type != nil {
 		result["type"] = *update.Type
 	}
@@ -1,8 +1,15 @@
 package db
 
 import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
 	"context"
 	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (r *FirestoreRepository) SeedNewUser(ctx context.Context, userID string, userData map[string]interface{}) error {
@@ -13,3 +20,43 @@ func (r *FirestoreRepository) SeedNewUser(ctx context.Context, userID string, us
 	}
 	return nil
 }
+
+func (r *FirestoreRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	doc, err := r.client.Collection("users").Doc(userID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, exceptions.UserNotFound(userID)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var user models.User
+	if err := doc.DataTo(&user); err != nil {
+		return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+	}
+	user.ID = doc.Ref.ID
+	withUserDefaults(&user)
+	return &user, nil
+}
+
+func (r *FirestoreRepository) UpdateUser(ctx context.Context, userID string, updateData models.UserUpdate) (*models.User, error) {
+	updates := []firestore.Update{{Path: "updatedAt", Value: time.Now()}}
+	if updateData.FirstName != nil {
+		updates = append(updates, firestore.Update{Path: "firstName", Value: *updateData.FirstName})
+	}
+	if updateData.LastName != nil {
+		updates = append(updates, firestore.Update{Path: "lastName", Value: *updateData.LastName})
+	}
+	if updateData.BaseCurrency != nil {
+		updates = append(updates, firestore.Update{Path: "baseCurrency", Value: *updateData.BaseCurrency})
+	}
+
+	_, err := r.client.Collection("users").Doc(userID).Update(ctx, updates)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, exceptions.UserNotFound(userID)
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return r.GetUser(ctx, userID)
+}
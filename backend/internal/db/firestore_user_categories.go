@@ -1,14 +1,21 @@
 package db
 
 import (
+	"backend/internal/exceptions"
 	"backend/internal/models"
 	"context"
 	"errors"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// maxFirestoreWrites is the most writes Firestore accepts in one commit.
+const maxFirestoreWrites = 500
+
 func (r *FirestoreRepository) ListUserCategories(ctx context.Context, userID string) ([]models.UserCategory, error) {
 	iter := r.client.Collection("users").Doc(userID).Collection("categories").Documents(ctx)
 	defer iter.Stop()
@@ -26,6 +33,7 @@ func (r *FirestoreRepository) ListUserCategories(ctx context.Context, userID str
 		if err := doc.DataTo(&cat); err != nil {
 			return nil, err
 		}
+		cat.ID = doc.Ref.ID
 		categories = append(categories, cat)
 	}
 }
@@ -39,14 +47,233 @@ func (r *FirestoreRepository) AddUserCategory(ctx context.Context, userID string
 	return ref.ID, nil
 }
 
-func (r *FirestoreRepository) UpdateUserCategory(ctx context.Context, userID, categoryID string, category models.UserCategory) error {
+func (r *FirestoreRepository) UpdateUserCategory(ctx context.Context, userID, categoryID string, category models.UserCategory) (int, error) {
 	docRef := r.client.Collection("users").Doc(userID).Collection("categories").Doc(categoryID)
-	_, err := docRef.Set(ctx, category, firestore.MergeAll)
-	return err
+	existing, err := r.getUserCategory(ctx, docRef)
+	if err != nil {
+		return 0, err
+	}
+
+	category.ID = categoryID
+	var refs []*firestore.DocumentRef
+	if category.Name != existing.Name {
+		if refs, err = r.documentsInCategory(ctx, userID, *existing); err != nil {
+			return 0, err
+		}
+	}
+	moved, err := r.moveDocuments(ctx, refs, *existing, category, func(tx *firestore.Transaction) error {
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "name", Value: category.Name},
+			{Path: "parentId", Value: category.ParentID},
+			{Path: "keywords", Value: category.Keywords},
+		})
+	})
+	if status.Code(err) == codes.NotFound {
+		return 0, exceptions.CategoryNotFound(categoryID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
 }
 
-func (r *FirestoreRepository) DeleteUserCategory(ctx context.Context, userID, categoryID string) error {
-	docRef := r.client.Collection("users").Doc(userID).Collection("categories").Doc(categoryID)
-	_, err := docRef.Delete(ctx)
-	return err
+func (r *FirestoreRepository) DeleteUserCategory(ctx context.Context, userID, categoryID, reassignTo string) (int, error) {
+	collection := r.client.Collection("users").Doc(userID).Collection("categories")
+	docRef := collection.Doc(categoryID)
+	from, err := r.getUserCategory(ctx, docRef)
+	if err != nil {
+		return 0, err
+	}
+	if reassignTo == categoryID {
+		return 0, exceptions.CategoryNotFound(reassignTo)
+	}
+	to, err := r.getUserCategory(ctx, collection.Doc(reassignTo))
+	if err != nil {
+		return 0, err
+	}
+
+	refs, err := r.documentsInCategory(ctx, userID, *from)
+	if err != nil {
+		return 0, err
+	}
+	moved, err := r.moveDocuments(ctx, refs, *from, *to, func(tx *firestore.Transaction) error {
+		return tx.Delete(docRef, firestore.Exists)
+	})
+	if status.Code(err) == codes.NotFound {
+		return 0, exceptions.CategoryNotFound(categoryID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+func (r *FirestoreRepository) getUserCategory(ctx context.Context, docRef *firestore.DocumentRef) (*models.UserCategory, error) {
+	doc, err := docRef.Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, exceptions.CategoryNotFound(docRef.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var category models.UserCategory
+	if err := doc.DataTo(&category); err != nil {
+		return nil, err
+	}
+	category.ID = docRef.ID
+	return &category, nil
+}
+
+// categoryCollections are the collections other than transactions whose
+// documents name a category, and whether they record when a document last
+// changed.
+var categoryCollections = []struct {
+	name      string
+	updatedAt bool
+}{
+	{"budgets", true},
+	{"allocations", false},
+	{"rules", true},
+	{"categoryMappings", false},
+}
+
+// categoryReference is the category named by a budget, allocation, rule or
+// learned mapping.
+type categoryReference struct {
+	Category   string `firestore:"category"`
+	CategoryID string `firestore:"categoryId"`
+}
+
+// documentsInCategory finds the user's documents with the category from:
+// transactions, on the transaction itself or on a split line, then budgets,
+// allocations, rules and learned mappings. Split lines can't be queried, so
+// this reads all of the user's transactions. Documents stored before
+// categories had IDs are matched by name.
+func (r *FirestoreRepository) documentsInCategory(ctx context.Context, userID string, from models.UserCategory) ([]*firestore.DocumentRef, error) {
+	user := r.client.Collection("users").Doc(userID)
+	var refs []*firestore.DocumentRef
+	collections := []string{"transactions"}
+	for _, collection := range categoryCollections {
+		collections = append(collections, collection.name)
+	}
+	for _, collection := range collections {
+		iter := user.Collection(collection).Documents(ctx)
+		for {
+			doc, err := iter.Next()
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			if err != nil {
+				iter.Stop()
+				return nil, err
+			}
+			updates, err := categoryUpdates(doc, from, from, time.Time{})
+			if err != nil {
+				iter.Stop()
+				return nil, err
+			}
+			if updates != nil {
+				refs = append(refs, doc.Ref)
+			}
+		}
+		iter.Stop()
+	}
+	return refs, nil
+}
+
+// categoryUpdates returns the writes that move doc from one category to
+// another, or nil if it is not in from.
+func categoryUpdates(doc *firestore.DocumentSnapshot, from, to models.UserCategory, now time.Time) ([]firestore.Update, error) {
+	if doc.Ref.Parent.ID == "transactions" {
+		var transaction models.Transaction
+		if err := doc.DataTo(&transaction); err != nil {
+			return nil, err
+		}
+		if !moveCategory(&transaction, from, to) {
+			return nil, nil
+		}
+		updates := []firestore.Update{
+			{Path: "category", Value: transaction.Category},
+			{Path: "categoryId", Value: transaction.CategoryID},
+			{Path: "updatedAt", Value: now},
+		}
+		if len(transaction.Splits) > 0 {
+			updates = append(updates, firestore.Update{Path: "splits", Value: transaction.Splits})
+		}
+		return updates, nil
+	}
+
+	var reference categoryReference
+	if err := doc.DataTo(&reference); err != nil {
+		return nil, err
+	}
+	if !moveReference(&reference.CategoryID, &reference.Category, from, to) {
+		return nil, nil
+	}
+	updates := []firestore.Update{
+		{Path: "category", Value: reference.Category},
+		{Path: "categoryId", Value: reference.CategoryID},
+	}
+	for _, collection := range categoryCollections {
+		if collection.name == doc.Ref.Parent.ID && collection.updatedAt {
+			updates = append(updates, firestore.Update{Path: "updatedAt", Value: now})
+		}
+	}
+	return updates, nil
+}
+
+// moveDocuments moves the documents at refs from one category to another,
+// committing in batches small enough for a single Firestore commit, and
+// returns how many transactions changed. Each batch reads its documents
+// again inside its own Firestore transaction, so changes made to them since
+// they were found are kept. last runs in the final batch, so the change to
+// the category itself lands only once everything has moved and an
+// interrupted move can simply be retried.
+func (r *FirestoreRepository) moveDocuments(ctx context.Context, refs []*firestore.DocumentRef, from, to models.UserCategory, last func(tx *firestore.Transaction) error) (int, error) {
+	now := time.Now()
+	moved := 0
+	// Leave room in each batch for last's write.
+	batchSize := maxFirestoreWrites - 1
+	for start := 0; start == 0 || start < len(refs); start += batchSize {
+		end := min(start+batchSize, len(refs))
+		batchMoved := 0
+		err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			// The function is run again if the transaction is retried.
+			batchMoved = 0
+			var docs []*firestore.DocumentSnapshot
+			if end > start {
+				var err error
+				if docs, err = tx.GetAll(refs[start:end]); err != nil {
+					return err
+				}
+			}
+			for _, doc := range docs {
+				if !doc.Exists() {
+					continue
+				}
+				updates, err := categoryUpdates(doc, from, to, now)
+				if err != nil {
+					return err
+				}
+				if updates == nil {
+					continue
+				}
+				if err := tx.Update(doc.Ref, updates); err != nil {
+					return err
+				}
+				if doc.Ref.Parent.ID == "transactions" {
+					batchMoved++
+				}
+			}
+			if end == len(refs) {
+				return last(tx)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		moved += batchMoved
+	}
+	return moved, nil
 }
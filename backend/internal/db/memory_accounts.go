@@ -0,0 +1,157 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+func (r *MemoryRepository) AddAccount(ctx context.Context, userID string, account models.Account) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := newDocumentID()
+	account.ID = ""
+	collectionFor(r.accounts, userID).set(id, account)
+	return id, nil
+}
+
+func (r *MemoryRepository) GetAccount(ctx context.Context, userID, accountID string) (*models.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	account, ok := r.getAccount(userID, accountID)
+	if !ok {
+		return nil, exceptions.AccountNotFound(accountID)
+	}
+	return &account, nil
+}
+
+func (r *MemoryRepository) ListAccounts(ctx context.Context, userID string) ([]models.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.accounts[userID]
+	if !ok {
+		return nil, nil
+	}
+	var accounts []models.Account
+	collection.each(func(id string, account models.Account) {
+		account.ID = id
+		accounts = append(accounts, account)
+	})
+	return accounts, nil
+}
+
+func (r *MemoryRepository) UpdateAccount(ctx context.Context, userID, accountID string, updateData models.AccountUpdate) (*models.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.getAccount(userID, accountID)
+	if !ok {
+		return nil, exceptions.AccountNotFound(accountID)
+	}
+	if updateData.Name != nil {
+		account.Name = *updateData.Name
+	}
+	if updateData.Institution != nil {
+		account.Institution = *updateData.Institution
+	}
+	if updateData.Type != nil {
+		account.Type = *updateData.Type
+	}
+	if updateData.Currency != nil {
+		account.Currency = *updateData.Currency
+	}
+	if updateData.OpeningBalance != nil {
+		account.OpeningBalance = *updateData.OpeningBalance
+	}
+	account.UpdatedAt = time.Now()
+
+	account.ID = ""
+	r.accounts[userID].set(accountID, account)
+	account.ID = accountID
+	return &account, nil
+}
+
+func (r *MemoryRepository) DeleteAccount(ctx context.Context, userID, accountID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.getAccount(userID, accountID); !ok {
+		return exceptions.AccountNotFound(accountID)
+	}
+	r.accounts[userID].delete(accountID)
+	delete(r.checkpoints, checkpointKey(userID, accountID))
+	return nil
+}
+
+// getAccount returns a copy of the stored account with its ID set. Callers
+// must hold the lock.
+func (r *MemoryRepository) getAccount(userID, accountID string) (models.Account, bool) {
+	collection, ok := r.accounts[userID]
+	if !ok {
+		return models.Account{}, false
+	}
+	account, ok := collection.get(accountID)
+	if !ok {
+		return models.Account{}, false
+	}
+	account.ID = accountID
+	return account, true
+}
+
+func (r *MemoryRepository) AddBalanceCheckpoint(ctx context.Context, userID, accountID string, checkpoint models.BalanceCheckpoint) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.getAccount(userID, accountID); !ok {
+		return "", exceptions.AccountNotFound(accountID)
+	}
+	id := newDocumentID()
+	checkpoint.ID = ""
+	checkpoint.AccountID = ""
+	collectionFor(r.checkpoints, checkpointKey(userID, accountID)).set(id, checkpoint)
+	return id, nil
+}
+
+func (r *MemoryRepository) ListBalanceCheckpoints(ctx context.Context, userID, accountID string) ([]models.BalanceCheckpoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.checkpoints[checkpointKey(userID, accountID)]
+	if !ok {
+		return nil, nil
+	}
+	var checkpoints []models.BalanceCheckpoint
+	collection.each(func(id string, checkpoint models.BalanceCheckpoint) {
+		checkpoint.ID = id
+		checkpoint.AccountID = accountID
+		checkpoints = append(checkpoints, checkpoint)
+	})
+	sort.SliceStable(checkpoints, func(i, j int) bool { return checkpoints[i].Date.Before(checkpoints[j].Date) })
+	return checkpoints, nil
+}
+
+func (r *MemoryRepository) DeleteBalanceCheckpoint(ctx context.Context, userID, accountID, checkpointID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, ok := r.checkpoints[checkpointKey(userID, accountID)]
+	if !ok {
+		return exceptions.BalanceCheckpointNotFound(checkpointID)
+	}
+	if _, ok := collection.get(checkpointID); !ok {
+		return exceptions.BalanceCheckpointNotFound(checkpointID)
+	}
+	collection.delete(checkpointID)
+	return nil
+}
+
+// checkpointKey mirrors the users/{userID}/accounts/{accountID} document path
+// that checkpoints are nested under in Firestore.
+func checkpointKey(userID, accountID string) string {
+	return userID + "/" + accountID
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+	"sort"
+)
+
+func (r *MemoryRepository) AddAllocations(ctx context.Context, userID string, allocations []models.Allocation) ([]models.Allocation, error) {
+	if len(allocations) == 0 {
+		return nil, fmt.Errorf("no allocations to add")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection := collectionFor(r.allocations, userID)
+	added := make([]models.Allocation, 0, len(allocations))
+	for _, allocation := range allocations {
+		id := newDocumentID()
+		allocation.ID = ""
+		collection.set(id, allocation)
+		allocation.ID = id
+		added = append(added, allocation)
+	}
+	return added, nil
+}
+
+func (r *MemoryRepository) ListAllocations(ctx context.Context, userID, throughMonth string) ([]models.Allocation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.allocations[userID]
+	if !ok {
+		return nil, nil
+	}
+	var allocations []models.Allocation
+	collection.each(func(id string, allocation models.Allocation) {
+		if allocation.Month > throughMonth {
+			return
+		}
+		allocation.ID = id
+		allocations = append(allocations, allocation)
+	})
+	sortAllocations(allocations)
+	return allocations, nil
+}
+
+// sortAllocations orders ledger entries by month, then by when they were made.
+func sortAllocations(allocations []models.Allocation) {
+	sort.SliceStable(allocations, func(i, j int) bool {
+		if allocations[i].Month != allocations[j].Month {
+			return allocations[i].Month < allocations[j].Month
+		}
+		return allocations[i].CreatedAt.Before(allocations[j].CreatedAt)
+	})
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"time"
+)
+
+func (r *MemoryRepository) AddBudget(ctx context.Context, userID string, budget models.Budget) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := newDocumentID()
+	budget.ID = ""
+	collectionFor(r.budgets, userID).set(id, budget)
+	return id, nil
+}
+
+func (r *MemoryRepository) GetBudget(ctx context.Context, userID, budgetID string) (*models.Budget, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	budget, ok := r.getBudget(userID, budgetID)
+	if !ok {
+		return nil, exceptions.BudgetNotFound(budgetID)
+	}
+	return &budget, nil
+}
+
+func (r *MemoryRepository) ListBudgets(ctx context.Context, userID string) ([]models.Budget, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.budgets[userID]
+	if !ok {
+		return nil, nil
+	}
+	var budgets []models.Budget
+	collection.each(func(id string, budget models.Budget) {
+		budget.ID = id
+		budgets = append(budgets, budget)
+	})
+	return budgets, nil
+}
+
+func (r *MemoryRepository) UpdateBudget(ctx context.Context, userID, budgetID string, updateData models.BudgetUpdate) (*models.Budget, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	budget, ok := r.getBudget(userID, budgetID)
+	if !ok {
+		return nil, exceptions.BudgetNotFound(budgetID)
+	}
+	if updateData.Category != nil {
+		budget.Category = *updateData.Category
+	}
+	if updateData.CategoryID != nil {
+		budget.CategoryID = *updateData.CategoryID
+	}
+	if updateData.Period != nil {
+		budget.Period = *updateData.Period
+	}
+	if updateData.Amount != nil {
+		budget.Amount = *updateData.Amount
+	}
+	if updateData.Currency != nil {
+		budget.Currency = *updateData.Currency
+	}
+	if updateData.Rollover != nil {
+		budget.Rollover = *updateData.Rollover
+	}
+	if updateData.StartDate != nil {
+		budget.StartDate = *updateData.StartDate
+	}
+	budget.UpdatedAt = time.Now()
+
+	budget.ID = ""
+	r.budgets[userID].set(budgetID, budget)
+	budget.ID = budgetID
+	return &budget, nil
+}
+
+func (r *MemoryRepository) DeleteBudget(ctx context.Context, userID, budgetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.getBudget(userID, budgetID); !ok {
+		return exceptions.BudgetNotFound(budgetID)
+	}
+	r.budgets[userID].delete(budgetID)
+	return nil
+}
+
+// getBudget returns a copy of the stored budget with its ID set. Callers must
+// hold the lock.
+func (r *MemoryRepository) getBudget(userID, budgetID string) (models.Budget, bool) {
+	collection, ok := r.budgets[userID]
+	if !ok {
+		return models.Budget{}, false
+	}
+	budget, ok := collection.get(budgetID)
+	if !ok {
+		return models.Budget{}, false
+	}
+	budget.ID = budgetID
+	return budget, true
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"sort"
+)
+
+func (r *MemoryRepository) SetCategoryMapping(ctx context.Context, userID string, mapping models.CategoryMapping) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collectionFor(r.mappings, userID).set(mapping.Merchant, mapping)
+	return nil
+}
+
+func (r *MemoryRepository) ListCategoryMappings(ctx context.Context, userID string) ([]models.CategoryMapping, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.mappings[userID]
+	if !ok {
+		return nil, nil
+	}
+	var mappings []models.CategoryMapping
+	collection.each(func(_ string, mapping models.CategoryMapping) {
+		mappings = append(mappings, mapping)
+	})
+	// Match the other drivers, which list in merchant order.
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].Merchant < mappings[j].Merchant })
+	return mappings, nil
+}
+
+func (r *MemoryRepository) DeleteCategoryMapping(ctx context.Context, userID, merchant string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, ok := r.mappings[userID]
+	if !ok {
+		return exceptions.CategoryMappingNotFound(merchant)
+	}
+	if _, ok := collection.get(merchant); !ok {
+		return exceptions.CategoryMappingNotFound(merchant)
+	}
+	collection.delete(merchant)
+	return nil
+}
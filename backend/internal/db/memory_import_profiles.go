@@ -0,0 +1,48 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"sort"
+)
+
+func (r *MemoryRepository) SetImportProfile(ctx context.Context, userID string, profile models.ImportProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collectionFor(r.profiles, userID).set(profile.Name, profile)
+	return nil
+}
+
+func (r *MemoryRepository) ListImportProfiles(ctx context.Context, userID string) ([]models.ImportProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.profiles[userID]
+	if !ok {
+		return nil, nil
+	}
+	var profiles []models.ImportProfile
+	collection.each(func(_ string, profile models.ImportProfile) {
+		profiles = append(profiles, profile)
+	})
+	// Match the other drivers, which list in name order.
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+func (r *MemoryRepository) DeleteImportProfile(ctx context.Context, userID, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, ok := r.profiles[userID]
+	if !ok {
+		return exceptions.ImportProfileNotFound(name)
+	}
+	if _, ok := collection.get(name); !ok {
+		return exceptions.ImportProfileNotFound(name)
+	}
+	collection.delete(name)
+	return nil
+}
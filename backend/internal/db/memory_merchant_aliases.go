@@ -0,0 +1,48 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"sort"
+)
+
+func (r *MemoryRepository) SetMerchantAlias(ctx context.Context, userID string, alias models.MerchantAlias) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collectionFor(r.aliases, userID).set(alias.Pattern, alias)
+	return nil
+}
+
+func (r *MemoryRepository) ListMerchantAliases(ctx context.Context, userID string) ([]models.MerchantAlias, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.aliases[userID]
+	if !ok {
+		return nil, nil
+	}
+	var aliases []models.MerchantAlias
+	collection.each(func(_ string, alias models.MerchantAlias) {
+		aliases = append(aliases, alias)
+	})
+	// Match the other drivers, which list in pattern order.
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Pattern < aliases[j].Pattern })
+	return aliases, nil
+}
+
+func (r *MemoryRepository) DeleteMerchantAlias(ctx context.Context, userID, pattern string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, ok := r.aliases[userID]
+	if !ok {
+		return exceptions.MerchantAliasNotFound(pattern)
+	}
+	if _, ok := collection.get(pattern); !ok {
+		return exceptions.MerchantAliasNotFound(pattern)
+	}
+	collection.delete(pattern)
+	return nil
+}
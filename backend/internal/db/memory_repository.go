@@ -0,0 +1,113 @@
+package db
+
+import (
+	"backend/internal/models"
+	"crypto/rand"
+	"math/big"
+	"sync"
+)
+
+var _ Repository = (*MemoryRepository)(nil)
+
+// MemoryRepository is a thread-safe, non-persistent Repository used for local
+// development and tests. It mirrors the behaviour of FirestoreRepository,
+// including the ownership and not-found errors.
+type MemoryRepository struct {
+	mu           sync.RWMutex
+	users        map[string]map[string]interface{}
+	transactions map[string]*memoryCollection[models.Transaction]
+	accounts     map[string]*memoryCollection[models.Account]
+	checkpoints  map[string]*memoryCollection[models.BalanceCheckpoint]
+	budgets      map[string]*memoryCollection[models.Budget]
+	allocations  map[string]*memoryCollection[models.Allocation]
+	rules        map[string]*memoryCollection[models.CategoryRule]
+	mappings     map[string]*memoryCollection[models.CategoryMapping]
+	aliases      map[string]*memoryCollection[models.MerchantAlias]
+	profiles     map[string]*memoryCollection[models.ImportProfile]
+	categories   map[string]*memoryCollection[models.UserCategory]
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		users:        make(map[string]map[string]interface{}),
+		transactions: make(map[string]*memoryCollection[models.Transaction]),
+		accounts:     make(map[string]*memoryCollection[models.Account]),
+		checkpoints:  make(map[string]*memoryCollection[models.BalanceCheckpoint]),
+		budgets:      make(map[string]*memoryCollection[models.Budget]),
+		allocations:  make(map[string]*memoryCollection[models.Allocation]),
+		rules:        make(map[string]*memoryCollection[models.CategoryRule]),
+		mappings:     make(map[string]*memoryCollection[models.CategoryMapping]),
+		aliases:      make(map[string]*memoryCollection[models.MerchantAlias]),
+		profiles:     make(map[string]*memoryCollection[models.ImportProfile]),
+		categories:   make(map[string]*memoryCollection[models.UserCategory]),
+	}
+}
+
+// memoryCollection keeps documents in insertion order so listings are stable.
+type memoryCollection[T any] struct {
+	ids  []string
+	docs map[string]T
+}
+
+func newMemoryCollection[T any]() *memoryCollection[T] {
+	return &memoryCollection[T]{docs: make(map[string]T)}
+}
+
+func (c *memoryCollection[T]) get(id string) (T, bool) {
+	doc, ok := c.docs[id]
+	return doc, ok
+}
+
+func (c *memoryCollection[T]) set(id string, doc T) {
+	if _, exists := c.docs[id]; !exists {
+		c.ids = append(c.ids, id)
+	}
+	c.docs[id] = doc
+}
+
+func (c *memoryCollection[T]) delete(id string) {
+	if _, exists := c.docs[id]; !exists {
+		return
+	}
+	delete(c.docs, id)
+	for i, existing := range c.ids {
+		if existing == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *memoryCollection[T]) each(fn func(id string, doc T)) {
+	for _, id := range c.ids {
+		fn(id, c.docs[id])
+	}
+}
+
+// collectionFor returns the collection for userID, creating it if needed.
+// Callers must hold the write lock.
+func collectionFor[T any](collections map[string]*memoryCollection[T], userID string) *memoryCollection[T] {
+	c, ok := collections[userID]
+	if !ok {
+		c = newMemoryCollection[T]()
+		collections[userID] = c
+	}
+	return c
+}
+
+const documentIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// newDocumentID generates a random 20 character ID in the same shape as
+// Firestore auto-generated document IDs.
+func newDocumentID() string {
+	id := make([]byte, 20)
+	max := big.NewInt(int64(len(documentIDAlphabet)))
+	for i := range id {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic("failed to generate document ID: " + err.Error())
+		}
+		id[i] = documentIDAlphabet[n.Int64()]
+	}
+	return string(id)
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+func (r *MemoryRepository) AddRule(ctx context.Context, userID string, rule models.CategoryRule) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := newDocumentID()
+	rule.ID = ""
+	collectionFor(r.rules, userID).set(id, rule)
+	return id, nil
+}
+
+func (r *MemoryRepository) GetRule(ctx context.Context, userID, ruleID string) (*models.CategoryRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, ok := r.getRule(userID, ruleID)
+	if !ok {
+		return nil, exceptions.RuleNotFound(ruleID)
+	}
+	return &rule, nil
+}
+
+func (r *MemoryRepository) ListRules(ctx context.Context, userID string) ([]models.CategoryRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.rules[userID]
+	if !ok {
+		return nil, nil
+	}
+	var rules []models.CategoryRule
+	collection.each(func(id string, rule models.CategoryRule) {
+		rule.ID = id
+		rules = append(rules, rule)
+	})
+	sortRules(rules)
+	return rules, nil
+}
+
+func (r *MemoryRepository) UpdateRule(ctx context.Context, userID, ruleID string, updateData models.CategoryRuleUpdate) (*models.CategoryRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule, ok := r.getRule(userID, ruleID)
+	if !ok {
+		return nil, exceptions.RuleNotFound(ruleID)
+	}
+	if updateData.Name != nil {
+		rule.Name = *updateData.Name
+	}
+	if updateData.Priority != nil {
+		rule.Priority = *updateData.Priority
+	}
+	if updateData.Category != nil {
+		rule.Category = *updateData.Category
+	}
+	if updateData.CategoryID != nil {
+		rule.CategoryID = *updateData.CategoryID
+	}
+	if updateData.Conditions != nil {
+		rule.Conditions = *updateData.Conditions
+	}
+	rule.UpdatedAt = time.Now()
+
+	rule.ID = ""
+	r.rules[userID].set(ruleID, rule)
+	rule.ID = ruleID
+	return &rule, nil
+}
+
+func (r *MemoryRepository) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.getRule(userID, ruleID); !ok {
+		return exceptions.RuleNotFound(ruleID)
+	}
+	r.rules[userID].delete(ruleID)
+	return nil
+}
+
+// getRule returns a copy of the stored rule with its ID set. Callers must hold
+// the lock.
+func (r *MemoryRepository) getRule(userID, ruleID string) (models.CategoryRule, bool) {
+	collection, ok := r.rules[userID]
+	if !ok {
+		return models.CategoryRule{}, false
+	}
+	rule, ok := collection.get(ruleID)
+	if !ok {
+		return models.CategoryRule{}, false
+	}
+	rule.ID = ruleID
+	return rule, true
+}
+
+// sortRules puts rules in the order they apply: ascending priority, then
+// oldest first.
+func sortRules(rules []models.CategoryRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+}
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (r *MemoryRepository) AddTransaction(ctx context.Context, userID string, transaction models.Transaction) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := newDocumentID()
+	transaction.ID = ""
+	collectionFor(r.transactions, userID).set(id, transaction)
+	return id, nil
+}
+
+func (r *MemoryRepository) GetTransactionByID(ctx context.Context, userID, transactionID string) (*models.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	transaction, err := r.getOwnedTransaction(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	transaction.ID = transactionID
+	transaction.UserID = ""
+	withLegacyDefaults(&transaction)
+	return &transaction, nil
+}
+
+func (r *MemoryRepository) ListTransactions(ctx context.Context, userID string, filters map[string]string) ([]models.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.transactions[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	var transactions []models.Transaction
+	collection.each(func(id string, transaction models.Transaction) {
+		if !matchesFilters(transaction, filters) {
+			return
+		}
+		transaction.ID = id
+		transaction.UserID = ""
+		withLegacyDefaults(&transaction)
+		transactions = append(transactions, transaction)
+	})
+	return transactions, nil
+}
+
+func (r *MemoryRepository) BulkAddTransactions(ctx context.Context, userID string, transactions []models.Transaction) ([]models.Transaction, error) {
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("no transactions to add")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection := collectionFor(r.transactions, userID)
+	added := make([]models.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		id := newDocumentID()
+		transaction.ID = ""
+		collection.set(id, transaction)
+
+		transaction.ID = id
+		transaction.UserID = ""
+		added = append(added, transaction)
+	}
+	return added, nil
+}
+
+func (r *MemoryRepository) UpdateTransaction(ctx context.Context, userID, transactionID string, updateData models.TransactionUpdate) (*models.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transaction, err := r.getOwnedTransaction(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateData.AccountID != nil {
+		transaction.AccountID = *updateData.AccountID
+	}
+	if updateData.Description != nil {
+		transaction.Description = *updateData.Description
+	}
+	if updateData.Merchant != nil {
+		transaction.Merchant = *updateData.Merchant
+	}
+	if updateData.Amount != nil {
+		transaction.Amount = *updateData.Amount
+	}
+	if updateData.Currency != nil {
+		transaction.Currency = *updateData.Currency
+	}
+	if updateData.Category != nil {
+		transaction.Category = *updateData.Category
+	}
+	if updateData.CategoryID != nil {
+		transaction.CategoryID = *updateData.CategoryID
+	}
+	if updateData.CategoryMatch != nil {
+		match := *updateData.CategoryMatch
+		transaction.CategoryMatch = &match
+	}
+	if updateData.Splits != nil {
+		transaction.Splits = nil
+		if len(*updateData.Splits) > 0 {
+			transaction.Splits = slices.Clone(*updateData.Splits)
+		}
+	}
+	if updateData.TransferID != nil {
+		transaction.TransferID = *updateData.TransferID
+	}
+	if updateData.Type != nil {
+		transaction.Type = *updateData.Type
+	}
+	transaction.UpdatedAt = time.Now()
+	withLegacyDefaults(&transaction)
+	r.transactions[userID].set(transactionID, transaction)
+
+	transaction.ID = transactionID
+	transaction.UserID = ""
+	return &transaction, nil
+}
+
+func (r *MemoryRepository) DeleteTransaction(ctx context.Context, userID, transactionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.getOwnedTransaction(userID, transactionID); err != nil {
+		return err
+	}
+	r.transactions[userID].delete(transactionID)
+	return nil
+}
+
+// getOwnedTransaction looks up a transaction stored under userID and checks
+// that it belongs to them. Callers must hold the lock.
+func (r *MemoryRepository) getOwnedTransaction(userID, transactionID string) (models.Transaction, error) {
+	collection, ok := r.transactions[userID]
+	if !ok {
+		return models.Transaction{}, exceptions.TransactionNotFound(transactionID)
+	}
+	transaction, ok := collection.get(transactionID)
+	if !ok {
+		return models.Transaction{}, exceptions.TransactionNotFound(transactionID)
+	}
+	if transaction.UserID != userID {
+		return models.Transaction{}, exceptions.UserForbidden(userID)
+	}
+	return transaction, nil
+}
+
+// matchesFilters applies the same equality filters as the Firestore query:
+// keys are firestore field names and only string fields can match.
+func matchesFilters(transaction models.Transaction, filters map[string]string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	value := reflect.ValueOf(transaction)
+	fields := value.Type()
+	for key, expected := range filters {
+		matched := false
+		for i := 0; i < fields.NumField(); i++ {
+			name, _, _ := strings.Cut(fields.Field(i).Tag.Get("firestore"), ",")
+			if name != key {
+				continue
+			}
+			field := value.Field(i)
+			matched = field.Kind() == reflect.String && field.String() == expected
+			break
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+func (r *MemoryRepository) SeedNewUser(ctx context.Context, userID string, userData map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := make(map[string]interface{}, len(userData))
+	for key, value := range userData {
+		if value == firestore.ServerTimestamp {
+			value = time.Now()
+		}
+		user[key] = value
+	}
+	r.users[userID] = user
+	return nil
+}
+
+func (r *MemoryRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data, ok := r.users[userID]
+	if !ok {
+		return nil, exceptions.UserNotFound(userID)
+	}
+	return userFromData(userID, data), nil
+}
+
+func (r *MemoryRepository) UpdateUser(ctx context.Context, userID string, updateData models.UserUpdate) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, ok := r.users[userID]
+	if !ok {
+		return nil, exceptions.UserNotFound(userID)
+	}
+	if updateData.FirstName != nil {
+		data["firstName"] = *updateData.FirstName
+	}
+	if updateData.LastName != nil {
+		data["lastName"] = *updateData.LastName
+	}
+	if updateData.BaseCurrency != nil {
+		data["baseCurrency"] = *updateData.BaseCurrency
+	}
+	data["updatedAt"] = time.Now()
+	return userFromData(userID, data), nil
+}
+
+// userFromData decodes a stored profile the way Firestore's DataTo would.
+func userFromData(userID string, data map[string]interface{}) *models.User {
+	user := &models.User{ID: userID}
+	user.Email, _ = data["email"].(string)
+	user.FirstName, _ = data["firstName"].(string)
+	user.LastName, _ = data["lastName"].(string)
+	user.BaseCurrency, _ = data["baseCurrency"].(string)
+	user.CreatedAt, _ = data["createdAt"].(time.Time)
+	user.UpdatedAt, _ = data["updatedAt"].(time.Time)
+	withUserDefaults(user)
+	return user
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"slices"
+	"time"
+)
+
+func (r *MemoryRepository) ListUserCategories(ctx context.Context, userID string) ([]models.UserCategory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.categories[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	var categories []models.UserCategory
+	collection.each(func(id string, cat models.UserCategory) {
+		cat.ID = id
+		categories = append(categories, cloneUserCategory(cat))
+	})
+	return categories, nil
+}
+
+func (r *MemoryRepository) AddUserCategory(ctx context.Context, userID string, category models.UserCategory) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := newDocumentID()
+	collectionFor(r.categories, userID).set(id, cloneUserCategory(category))
+	return id, nil
+}
+
+func (r *MemoryRepository) UpdateUserCategory(ctx context.Context, userID, categoryID string, category models.UserCategory) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, ok := r.categories[userID]
+	if !ok {
+		return 0, exceptions.CategoryNotFound(categoryID)
+	}
+	existing, ok := collection.get(categoryID)
+	if !ok {
+		return 0, exceptions.CategoryNotFound(categoryID)
+	}
+	changed := 0
+	if category.Name != existing.Name {
+		existing.ID = categoryID
+		category.ID = categoryID
+		changed = r.reassignCategory(userID, existing, category)
+	}
+	collection.set(categoryID, cloneUserCategory(category))
+	return changed, nil
+}
+
+func (r *MemoryRepository) DeleteUserCategory(ctx context.Context, userID, categoryID, reassignTo string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, ok := r.categories[userID]
+	if !ok {
+		return 0, exceptions.CategoryNotFound(categoryID)
+	}
+	from, ok := collection.get(categoryID)
+	if !ok {
+		return 0, exceptions.CategoryNotFound(categoryID)
+	}
+	to, ok := collection.get(reassignTo)
+	if !ok || reassignTo == categoryID {
+		return 0, exceptions.CategoryNotFound(reassignTo)
+	}
+	from.ID, to.ID = categoryID, reassignTo
+	changed := r.reassignCategory(userID, from, to)
+	collection.delete(categoryID)
+	return changed, nil
+}
+
+// reassignCategory puts the user's transactions, split lines, budgets,
+// allocations, rules and learned mappings in category from into category to
+// and returns how many transactions changed.
+func (r *MemoryRepository) reassignCategory(userID string, from, to models.UserCategory) int {
+	now := time.Now()
+	moveDocuments(r.budgets, userID, func(budget *models.Budget) bool {
+		if !moveReference(&budget.CategoryID, &budget.Category, from, to) {
+			return false
+		}
+		budget.UpdatedAt = now
+		return true
+	})
+	moveDocuments(r.allocations, userID, func(allocation *models.Allocation) bool {
+		return moveReference(&allocation.CategoryID, &allocation.Category, from, to)
+	})
+	moveDocuments(r.rules, userID, func(rule *models.CategoryRule) bool {
+		if !moveReference(&rule.CategoryID, &rule.Category, from, to) {
+			return false
+		}
+		rule.UpdatedAt = now
+		return true
+	})
+	moveDocuments(r.mappings, userID, func(mapping *models.CategoryMapping) bool {
+		return moveReference(&mapping.CategoryID, &mapping.Category, from, to)
+	})
+	return moveDocuments(r.transactions, userID, func(transaction *models.Transaction) bool {
+		if !moveCategory(transaction, from, to) {
+			return false
+		}
+		transaction.UpdatedAt = now
+		return true
+	})
+}
+
+// moveDocuments saves each of the user's documents in collections that move
+// changes, and returns how many it changed. Callers must hold the write lock.
+func moveDocuments[T any](collections map[string]*memoryCollection[T], userID string, move func(doc *T) bool) int {
+	collection, ok := collections[userID]
+	if !ok {
+		return 0
+	}
+	moved := make(map[string]T)
+	collection.each(func(id string, doc T) {
+		if move(&doc) {
+			moved[id] = doc
+		}
+	})
+	for id, doc := range moved {
+		collection.set(id, doc)
+	}
+	return len(moved)
+}
+
+func cloneUserCategory(category models.UserCategory) models.UserCategory {
+	category.Keywords = slices.Clone(category.Keywords)
+	return category
+}
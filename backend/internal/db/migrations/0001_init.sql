@@ -0,0 +1,32 @@
+CREATE TABLE users (
+    id TEXT PRIMARY KEY,
+    email TEXT NOT NULL DEFAULT '',
+    attributes TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE transactions (
+    id TEXT PRIMARY KEY,
+    owner_id TEXT NOT NULL,
+    user_id TEXT NOT NULL DEFAULT '',
+    transaction_date_time TIMESTAMP NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    amount BIGINT NOT NULL DEFAULT 0,
+    category TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL DEFAULT '',
+    bank_reference TEXT NOT NULL DEFAULT '',
+    inserted_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_transactions_owner ON transactions (owner_id, transaction_date_time);
+
+CREATE TABLE user_categories (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    position BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    keywords TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX idx_user_categories_user ON user_categories (user_id, position);
@@ -0,0 +1 @@
+ALTER TABLE transactions ADD COLUMN currency TEXT NOT NULL DEFAULT 'GBP';
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN first_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN last_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN base_currency TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN updated_at TIMESTAMP;
@@ -0,0 +1,16 @@
+CREATE TABLE accounts (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    institution TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_accounts_user ON accounts (user_id, created_at);
+
+ALTER TABLE transactions ADD COLUMN account_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_transactions_account ON transactions (owner_id, account_id);
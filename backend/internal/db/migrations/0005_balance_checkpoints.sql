@@ -0,0 +1,13 @@
+ALTER TABLE accounts ADD COLUMN opening_balance BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE balance_checkpoints (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    account_id TEXT NOT NULL,
+    balance_date TIMESTAMP NOT NULL,
+    balance BIGINT NOT NULL,
+    source TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_balance_checkpoints_account ON balance_checkpoints (user_id, account_id, balance_date);
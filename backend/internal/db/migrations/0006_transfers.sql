@@ -0,0 +1 @@
+ALTER TABLE transactions ADD COLUMN transfer_id TEXT NOT NULL DEFAULT '';
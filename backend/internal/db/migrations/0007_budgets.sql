@@ -0,0 +1,14 @@
+CREATE TABLE budgets (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    category TEXT NOT NULL,
+    period TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    rollover BOOLEAN NOT NULL DEFAULT FALSE,
+    start_date TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_budgets_user ON budgets (user_id, created_at);
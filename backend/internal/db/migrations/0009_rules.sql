@@ -0,0 +1,12 @@
+CREATE TABLE rules (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    priority BIGINT NOT NULL,
+    category TEXT NOT NULL,
+    conditions TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_rules_user ON rules (user_id, priority, created_at);
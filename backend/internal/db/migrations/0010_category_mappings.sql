@@ -0,0 +1,7 @@
+CREATE TABLE category_mappings (
+    user_id TEXT NOT NULL,
+    merchant TEXT NOT NULL,
+    category TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, merchant)
+);
@@ -0,0 +1 @@
+ALTER TABLE transactions ADD COLUMN category_match TEXT NOT NULL DEFAULT '';
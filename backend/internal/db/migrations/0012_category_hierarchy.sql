@@ -0,0 +1,2 @@
+ALTER TABLE user_categories ADD COLUMN parent_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE transactions ADD COLUMN category_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE transactions ADD COLUMN splits TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,9 @@
+ALTER TABLE transactions ADD COLUMN merchant TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE merchant_aliases (
+    user_id TEXT NOT NULL,
+    pattern TEXT NOT NULL,
+    merchant TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, pattern)
+);
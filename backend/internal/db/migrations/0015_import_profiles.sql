@@ -0,0 +1,7 @@
+CREATE TABLE import_profiles (
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    definition TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, name)
+);
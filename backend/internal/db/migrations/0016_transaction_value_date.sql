@@ -0,0 +1,2 @@
+ALTER TABLE transactions ADD COLUMN value_date TIMESTAMP;
+ALTER TABLE transactions ADD COLUMN remittance TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE budgets ADD COLUMN category_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE allocations ADD COLUMN category_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE rules ADD COLUMN category_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE category_mappings ADD COLUMN category_id TEXT NOT NULL DEFAULT '';
@@ -2,13 +2,18 @@ package db
 
 import (
 	"backend/internal/models"
+	"backend/internal/money"
 	"context"
+	"slices"
+	"strings"
 
 	"cloud.google.com/go/firestore"
 )
 
 type Repository interface {
 	SeedNewUser(ctx context.Context, userID string, userData map[string]interface{}) error
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	UpdateUser(ctx context.Context, userID string, updateData models.UserUpdate) (*models.User, error)
 
 	AddTransaction(ctx context.Context, userID string, transaction models.Transaction) (string, error)
 	GetTransactionByID(ctx context.Context, userID, transactionID string) (*models.Transaction, error)
@@ -17,12 +22,113 @@ type Repository interface {
 	UpdateTransaction(ctx context.Context, userID, transactionID string, updateData models.TransactionUpdate) (*models.Transaction, error)
 	DeleteTransaction(ctx context.Context, userID, transactionID string) error
 
+	AddAccount(ctx context.Context, userID string, account models.Account) (string, error)
+	GetAccount(ctx context.Context, userID, accountID string) (*models.Account, error)
+	ListAccounts(ctx context.Context, userID string) ([]models.Account, error)
+	UpdateAccount(ctx context.Context, userID, accountID string, updateData models.AccountUpdate) (*models.Account, error)
+	DeleteAccount(ctx context.Context, userID, accountID string) error
+	AddBalanceCheckpoint(ctx context.Context, userID, accountID string, checkpoint models.BalanceCheckpoint) (string, error)
+	ListBalanceCheckpoints(ctx context.Context, userID, accountID string) ([]models.BalanceCheckpoint, error)
+	DeleteBalanceCheckpoint(ctx context.Context, userID, accountID, checkpointID string) error
+
+	AddBudget(ctx context.Context, userID string, budget models.Budget) (string, error)
+	GetBudget(ctx context.Context, userID, budgetID string) (*models.Budget, error)
+	ListBudgets(ctx context.Context, userID string) ([]models.Budget, error)
+	UpdateBudget(ctx context.Context, userID, budgetID string, updateData models.BudgetUpdate) (*models.Budget, error)
+	DeleteBudget(ctx context.Context, userID, budgetID string) error
+
+	AddAllocations(ctx context.Context, userID string, allocations []models.Allocation) ([]models.Allocation, error)
+	// ListAllocations returns the ledger entries for every month up to and
+	// including throughMonth (YYYY-MM), oldest first.
+	ListAllocations(ctx context.Context, userID, throughMonth string) ([]models.Allocation, error)
+
+	AddRule(ctx context.Context, userID string, rule models.CategoryRule) (string, error)
+	GetRule(ctx context.Context, userID, ruleID string) (*models.CategoryRule, error)
+	// ListRules returns the user's rules in the order they apply: ascending
+	// priority, then oldest first.
+	ListRules(ctx context.Context, userID string) ([]models.CategoryRule, error)
+	UpdateRule(ctx context.Context, userID, ruleID string, updateData models.CategoryRuleUpdate) (*models.CategoryRule, error)
+	DeleteRule(ctx context.Context, userID, ruleID string) error
+
+	// SetCategoryMapping creates or replaces the mapping for
+	// mapping.Merchant.
+	SetCategoryMapping(ctx context.Context, userID string, mapping models.CategoryMapping) error
+	ListCategoryMappings(ctx context.Context, userID string) ([]models.CategoryMapping, error)
+	DeleteCategoryMapping(ctx context.Context, userID, merchant string) error
+
+	// SetMerchantAlias creates or replaces the alias for alias.Pattern.
+	SetMerchantAlias(ctx context.Context, userID string, alias models.MerchantAlias) error
+	ListMerchantAliases(ctx context.Context, userID string) ([]models.MerchantAlias, error)
+	DeleteMerchantAlias(ctx context.Context, userID, pattern string) error
+
+	// SetImportProfile creates or replaces the user's import profile called
+	// profile.Name.
+	SetImportProfile(ctx context.Context, userID string, profile models.ImportProfile) error
+	ListImportProfiles(ctx context.Context, userID string) ([]models.ImportProfile, error)
+	DeleteImportProfile(ctx context.Context, userID, name string) error
+
 	ListUserCategories(ctx context.Context, userID string) ([]models.UserCategory, error)
 	AddUserCategory(ctx context.Context, userID string, category models.UserCategory) (string, error)
-	UpdateUserCategory(ctx context.Context, userID, categoryID string, category models.UserCategory) error
-	DeleteUserCategory(ctx context.Context, userID, categoryID string) error
+	// UpdateUserCategory replaces a category. Renaming it renames it on its
+	// transactions, budgets, allocations, rules and learned mappings too; it
+	// returns how many transactions changed.
+	UpdateUserCategory(ctx context.Context, userID, categoryID string, category models.UserCategory) (int, error)
+	// DeleteUserCategory moves a category's transactions, budgets,
+	// allocations, rules and learned mappings to the category reassignTo and
+	// then deletes it, returning how many transactions moved.
+	DeleteUserCategory(ctx context.Context, userID, categoryID, reassignTo string) (int, error)
 }
 
 type FirestoreRepository struct {
 	client *firestore.Client
 }
+
+// withLegacyDefaults fills in fields that transactions stored before they
+// were introduced do not have.
+func withLegacyDefaults(transaction *models.Transaction) {
+	if transaction.Currency == "" {
+		transaction.Currency = money.DefaultCurrency
+	}
+}
+
+// inCategory reports whether a transaction or split line with the given
+// category ID and name belongs to category, matching by name, ignoring case,
+// those stored before categories had IDs.
+func inCategory(categoryID, name string, category models.UserCategory) bool {
+	if categoryID != "" {
+		return categoryID == category.ID
+	}
+	return strings.EqualFold(name, category.Name)
+}
+
+// moveReference moves a record that names a category by ID and name from one
+// category to another. It reports whether the record was in from.
+func moveReference(categoryID, name *string, from, to models.UserCategory) bool {
+	if !inCategory(*categoryID, *name, from) {
+		return false
+	}
+	*name, *categoryID = to.Name, to.ID
+	return true
+}
+
+// moveCategory moves transaction, and any of its split lines, from one
+// category to another. It reports whether anything changed.
+func moveCategory(transaction *models.Transaction, from, to models.UserCategory) bool {
+	changed := moveReference(&transaction.CategoryID, &transaction.Category, from, to)
+	// Copy the lines so the caller's transaction is not modified through
+	// a shared slice.
+	transaction.Splits = slices.Clone(transaction.Splits)
+	for i := range transaction.Splits {
+		if moveReference(&transaction.Splits[i].CategoryID, &transaction.Splits[i].Category, from, to) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// withUserDefaults fills in profile fields that older user documents lack.
+func withUserDefaults(user *models.User) {
+	if user.BaseCurrency == "" {
+		user.BaseCurrency = money.DefaultCurrency
+	}
+}
@@ -0,0 +1,68 @@
+package db_test
+
+import (
+	"backend/internal/db"
+	"backend/internal/db/dbtest"
+	"context"
+	"os"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	dbtest.RunRepositoryTests(t, func(t *testing.T) db.Repository {
+		return db.NewMemoryRepository()
+	})
+}
+
+// TestPostgresRepository runs against the database in TEST_POSTGRES_URL.
+func TestPostgresRepository(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_URL")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_URL is not set")
+	}
+	repo := newMigratedSQLRepository(t, db.SQLDialectPostgres, dsn)
+	dbtest.RunRepositoryTests(t, func(t *testing.T) db.Repository {
+		return repo
+	})
+}
+
+// TestFirestoreRepository runs against the Firestore emulator, for example
+// `gcloud emulators firestore start --host-port=localhost:8081` with
+// FIRESTORE_EMULATOR_HOST=localhost:8081.
+func TestFirestoreRepository(t *testing.T) {
+	if os.Getenv("FIRESTORE_EMULATOR_HOST") == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST is not set")
+	}
+	client, err := firestore.NewClient(context.Background(), "demo-budget-tracker")
+	if err != nil {
+		t.Fatalf("firestore.NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	repo := db.NewFirestoreRepository(client)
+	dbtest.RunRepositoryTests(t, func(t *testing.T) db.Repository {
+		return repo
+	})
+}
+
+func newMigratedSQLRepository(t *testing.T, dialect, dsn string) *db.SQLRepository {
+	t.Helper()
+	ctx := context.Background()
+	sqlDB, err := db.NewSQLClient(ctx, dialect, dsn)
+	if err != nil {
+		t.Fatalf("NewSQLClient: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	repo := db.NewSQLRepository(sqlDB, dialect)
+	if err := repo.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	// Running migrations again must be a no-op.
+	if err := repo.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate again: %v", err)
+	}
+	return repo
+}
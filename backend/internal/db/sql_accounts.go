@@ -0,0 +1,169 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const accountColumns = "id, name, institution, type, currency, opening_balance, created_at, updated_at"
+
+func (r *SQLRepository) AddAccount(ctx context.Context, userID string, account models.Account) (string, error) {
+	id := newDocumentID()
+	_, err := r.db.ExecContext(ctx, r.rebind(`INSERT INTO accounts (id, user_id, name, institution, type, currency, opening_balance, created_at, updated_at)
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		id, userID, account.Name, account.Institution, account.Type, account.Currency, account.OpeningBalance, account.CreatedAt.UTC(), account.UpdatedAt.UTC())
+	if err != nil {
+		return "", fmt.Errorf("failed to add account: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SQLRepository) GetAccount(ctx context.Context, userID, accountID string) (*models.Account, error) {
+	row := r.db.QueryRowContext(ctx, r.rebind("SELECT "+accountColumns+" FROM accounts WHERE id = ? AND user_id = ?"), accountID, userID)
+	account, err := scanAccount(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, exceptions.AccountNotFound(accountID)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	return account, nil
+}
+
+func (r *SQLRepository) ListAccounts(ctx context.Context, userID string) ([]models.Account, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind("SELECT "+accountColumns+" FROM accounts WHERE user_id = ? ORDER BY created_at, id"), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []models.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *SQLRepository) UpdateAccount(ctx context.Context, userID, accountID string, updateData models.AccountUpdate) (*models.Account, error) {
+	sets := []string{"updated_at = ?"}
+	args := []any{time.Now().UTC()}
+	if updateData.Name != nil {
+		sets = append(sets, "name = ?")
+		args = append(args, *updateData.Name)
+	}
+	if updateData.Institution != nil {
+		sets = append(sets, "institution = ?")
+		args = append(args, *updateData.Institution)
+	}
+	if updateData.Type != nil {
+		sets = append(sets, "type = ?")
+		args = append(args, *updateData.Type)
+	}
+	if updateData.Currency != nil {
+		sets = append(sets, "currency = ?")
+		args = append(args, *updateData.Currency)
+	}
+	if updateData.OpeningBalance != nil {
+		sets = append(sets, "opening_balance = ?")
+		args = append(args, *updateData.OpeningBalance)
+	}
+	args = append(args, accountID, userID)
+
+	result, err := r.db.ExecContext(ctx, r.rebind("UPDATE accounts SET "+strings.Join(sets, ", ")+" WHERE id = ? AND user_id = ?"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+	if updated, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	} else if updated == 0 {
+		return nil, exceptions.AccountNotFound(accountID)
+	}
+	return r.GetAccount(ctx, userID, accountID)
+}
+
+func (r *SQLRepository) DeleteAccount(ctx context.Context, userID, accountID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, r.rebind("DELETE FROM accounts WHERE id = ? AND user_id = ?"), accountID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	} else if deleted == 0 {
+		return exceptions.AccountNotFound(accountID)
+	}
+	if _, err := tx.ExecContext(ctx, r.rebind("DELETE FROM balance_checkpoints WHERE account_id = ? AND user_id = ?"), accountID, userID); err != nil {
+		return fmt.Errorf("failed to delete balance checkpoints: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *SQLRepository) AddBalanceCheckpoint(ctx context.Context, userID, accountID string, checkpoint models.BalanceCheckpoint) (string, error) {
+	if _, err := r.GetAccount(ctx, userID, accountID); err != nil {
+		return "", err
+	}
+	id := newDocumentID()
+	_, err := r.db.ExecContext(ctx, r.rebind(`INSERT INTO balance_checkpoints (id, user_id, account_id, balance_date, balance, source, created_at)
+    VALUES (?, ?, ?, ?, ?, ?, ?)`),
+		id, userID, accountID, checkpoint.Date.UTC(), checkpoint.Balance, checkpoint.Source, checkpoint.CreatedAt.UTC())
+	if err != nil {
+		return "", fmt.Errorf("failed to add balance checkpoint: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SQLRepository) ListBalanceCheckpoints(ctx context.Context, userID, accountID string) ([]models.BalanceCheckpoint, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind(`SELECT id, account_id, balance_date, balance, source, created_at FROM balance_checkpoints
+    WHERE user_id = ? AND account_id = ? ORDER BY balance_date, created_at`), userID, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list balance checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var checkpoints []models.BalanceCheckpoint
+	for rows.Next() {
+		var checkpoint models.BalanceCheckpoint
+		if err := rows.Scan(&checkpoint.ID, &checkpoint.AccountID, &checkpoint.Date, &checkpoint.Balance, &checkpoint.Source, &checkpoint.CreatedAt); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+func (r *SQLRepository) DeleteBalanceCheckpoint(ctx context.Context, userID, accountID, checkpointID string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind("DELETE FROM balance_checkpoints WHERE id = ? AND user_id = ? AND account_id = ?"), checkpointID, userID, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to delete balance checkpoint: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete balance checkpoint: %w", err)
+	} else if deleted == 0 {
+		return exceptions.BalanceCheckpointNotFound(checkpointID)
+	}
+	return nil
+}
+
+func scanAccount(row rowScanner) (*models.Account, error) {
+	var account models.Account
+	err := row.Scan(&account.ID, &account.Name, &account.Institution, &account.Type, &account.Currency, &account.OpeningBalance, &account.CreatedAt, &account.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"fmt"
+)
+
+func (r *SQLRepository) AddAllocations(ctx context.Context, userID string, allocations []models.Allocation) ([]models.Allocation, error) {
+	if len(allocations) == 0 {
+		return nil, fmt.Errorf("no allocations to add")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add allocations: %w", err)
+	}
+	defer tx.Rollback()
+
+	added := make([]models.Allocation, 0, len(allocations))
+	for _, allocation := range allocations {
+		id := newDocumentID()
+		_, err := tx.ExecContext(ctx, r.rebind(`INSERT INTO allocations (id, user_id, month, category, category_id, amount, kind, created_at)
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+			id, userID, allocation.Month, allocation.Category, allocation.CategoryID, allocation.Amount, allocation.Kind, allocation.CreatedAt.UTC())
+		if err != nil {
+			return nil, fmt.Errorf("failed to add allocations: %w", err)
+		}
+		allocation.ID = id
+		added = append(added, allocation)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to add allocations: %w", err)
+	}
+	return added, nil
+}
+
+func (r *SQLRepository) ListAllocations(ctx context.Context, userID, throughMonth string) ([]models.Allocation, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind(`SELECT id, month, category, category_id, amount, kind, created_at FROM allocations
+    WHERE user_id = ? AND month <= ? ORDER BY month, created_at`), userID, throughMonth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations: %w", err)
+	}
+	defer rows.Close()
+
+	var allocations []models.Allocation
+	for rows.Next() {
+		var allocation models.Allocation
+		if err := rows.Scan(&allocation.ID, &allocation.Month, &allocation.Category, &allocation.CategoryID, &allocation.Amount, &allocation.Kind, &allocation.CreatedAt); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		allocations = append(allocations, allocation)
+	}
+	return allocations, rows.Err()
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const budgetColumns = "id, category, category_id, period, amount, currency, rollover, start_date, created_at, updated_at"
+
+func (r *SQLRepository) AddBudget(ctx context.Context, userID string, budget models.Budget) (string, error) {
+	id := newDocumentID()
+	_, err := r.db.ExecContext(ctx, r.rebind(`INSERT INTO budgets (id, user_id, category, category_id, period, amount, currency, rollover, start_date, created_at, updated_at)
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		id, userID, budget.Category, budget.CategoryID, budget.Period, budget.Amount, budget.Currency, budget.Rollover,
+		budget.StartDate.UTC(), budget.CreatedAt.UTC(), budget.UpdatedAt.UTC())
+	if err != nil {
+		return "", fmt.Errorf("failed to add budget: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SQLRepository) GetBudget(ctx context.Context, userID, budgetID string) (*models.Budget, error) {
+	row := r.db.QueryRowContext(ctx, r.rebind("SELECT "+budgetColumns+" FROM budgets WHERE id = ? AND user_id = ?"), budgetID, userID)
+	budget, err := scanBudget(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, exceptions.BudgetNotFound(budgetID)
+		}
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+	return budget, nil
+}
+
+func (r *SQLRepository) ListBudgets(ctx context.Context, userID string) ([]models.Budget, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind("SELECT "+budgetColumns+" FROM budgets WHERE user_id = ? ORDER BY created_at, id"), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+	defer rows.Close()
+
+	var budgets []models.Budget
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		budgets = append(budgets, *budget)
+	}
+	return budgets, rows.Err()
+}
+
+func (r *SQLRepository) UpdateBudget(ctx context.Context, userID, budgetID string, updateData models.BudgetUpdate) (*models.Budget, error) {
+	sets := []string{"updated_at = ?"}
+	args := []any{time.Now().UTC()}
+	if updateData.Category != nil {
+		sets = append(sets, "category = ?")
+		args = append(args, *updateData.Category)
+	}
+	if updateData.CategoryID != nil {
+		sets = append(sets, "category_id = ?")
+		args = append(args, *updateData.CategoryID)
+	}
+	if updateData.Period != nil {
+		sets = append(sets, "period = ?")
+		args = append(args, *updateData.Period)
+	}
+	if updateData.Amount != nil {
+		sets = append(sets, "amount = ?")
+		args = append(args, *updateData.Amount)
+	}
+	if updateData.Currency != nil {
+		sets = append(sets, "currency = ?")
+		args = append(args, *updateData.Currency)
+	}
+	if updateData.Rollover != nil {
+		sets = append(sets, "rollover = ?")
+		args = append(args, *updateData.Rollover)
+	}
+	if updateData.StartDate != nil {
+		sets = append(sets, "start_date = ?")
+		args = append(args, updateData.StartDate.UTC())
+	}
+	args = append(args, budgetID, userID)
+
+	result, err := r.db.ExecContext(ctx, r.rebind("UPDATE budgets SET "+strings.Join(sets, ", ")+" WHERE id = ? AND user_id = ?"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update budget: %w", err)
+	}
+	if updated, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to update budget: %w", err)
+	} else if updated == 0 {
+		return nil, exceptions.BudgetNotFound(budgetID)
+	}
+	return r.GetBudget(ctx, userID, budgetID)
+}
+
+func (r *SQLRepository) DeleteBudget(ctx context.Context, userID, budgetID string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind("DELETE FROM budgets WHERE id = ? AND user_id = ?"), budgetID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	} else if deleted == 0 {
+		return exceptions.BudgetNotFound(budgetID)
+	}
+	return nil
+}
+
+func scanBudget(row rowScanner) (*models.Budget, error) {
+	var budget models.Budget
+	err := row.Scan(&budget.ID, &budget.Category, &budget.CategoryID, &budget.Period, &budget.Amount, &budget.Currency, &budget.Rollover,
+		&budget.StartDate, &budget.CreatedAt, &budget.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"fmt"
+)
+
+func (r *SQLRepository) SetCategoryMapping(ctx context.Context, userID string, mapping models.CategoryMapping) error {
+	_, err := r.db.ExecContext(ctx, r.rebind(`INSERT INTO category_mappings (user_id, merchant, category, category_id, updated_at)
+    VALUES (?, ?, ?, ?, ?)
+    ON CONFLICT (user_id, merchant) DO UPDATE SET category = excluded.category, category_id = excluded.category_id, updated_at = excluded.updated_at`),
+		userID, mapping.Merchant, mapping.Category, mapping.CategoryID, mapping.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to set category mapping: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLRepository) ListCategoryMappings(ctx context.Context, userID string) ([]models.CategoryMapping, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind("SELECT merchant, category, category_id, updated_at FROM category_mappings WHERE user_id = ? ORDER BY merchant"), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category mappings: %w", err)
+	}
+	defer rows.Close()
+
+	var mappings []models.CategoryMapping
+	for rows.Next() {
+		var mapping models.CategoryMapping
+		if err := rows.Scan(&mapping.Merchant, &mapping.Category, &mapping.CategoryID, &mapping.UpdatedAt); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, rows.Err()
+}
+
+func (r *SQLRepository) DeleteCategoryMapping(ctx context.Context, userID, merchant string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind("DELETE FROM category_mappings WHERE user_id = ? AND merchant = ?"), userID, merchant)
+	if err != nil {
+		return fmt.Errorf("failed to delete category mapping: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete category mapping: %w", err)
+	} else if deleted == 0 {
+		return exceptions.CategoryMappingNotFound(merchant)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Import profiles are stored whole as JSON, since only the importer reads
+// their fields.
+
+func (r *SQLRepository) SetImportProfile(ctx context.Context, userID string, profile models.ImportProfile) error {
+	definition, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("failed to set import profile: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, r.rebind(`INSERT INTO import_profiles (user_id, name, definition, updated_at)
+    VALUES (?, ?, ?, ?)
+    ON CONFLICT (user_id, name) DO UPDATE SET definition = excluded.definition, updated_at = excluded.updated_at`),
+		userID, profile.Name, string(definition), profile.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to set import profile: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLRepository) ListImportProfiles(ctx context.Context, userID string) ([]models.ImportProfile, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind("SELECT name, definition, updated_at FROM import_profiles WHERE user_id = ? ORDER BY name"), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []models.ImportProfile
+	for rows.Next() {
+		var profile models.ImportProfile
+		var name, definition string
+		if err := rows.Scan(&name, &definition, &profile.UpdatedAt); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		updatedAt := profile.UpdatedAt
+		if err := json.Unmarshal([]byte(definition), &profile); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		profile.Name, profile.UpdatedAt = name, updatedAt
+		profiles = append(profiles, profile)
+	}
+	return profiles, rows.Err()
+}
+
+func (r *SQLRepository) DeleteImportProfile(ctx context.Context, userID, name string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind("DELETE FROM import_profiles WHERE user_id = ? AND name = ?"), userID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete import profile: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete import profile: %w", err)
+	} else if deleted == 0 {
+		return exceptions.ImportProfileNotFound(name)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"fmt"
+)
+
+func (r *SQLRepository) SetMerchantAlias(ctx context.Context, userID string, alias models.MerchantAlias) error {
+	_, err := r.db.ExecContext(ctx, r.rebind(`INSERT INTO merchant_aliases (user_id, pattern, merchant, updated_at)
+    VALUES (?, ?, ?, ?)
+    ON CONFLICT (user_id, pattern) DO UPDATE SET merchant = excluded.merchant, updated_at = excluded.updated_at`),
+		userID, alias.Pattern, alias.Merchant, alias.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to set merchant alias: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLRepository) ListMerchantAliases(ctx context.Context, userID string) ([]models.MerchantAlias, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind("SELECT pattern, merchant, updated_at FROM merchant_aliases WHERE user_id = ? ORDER BY pattern"), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merchant aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var aliases []models.MerchantAlias
+	for rows.Next() {
+		var alias models.MerchantAlias
+		if err := rows.Scan(&alias.Pattern, &alias.Merchant, &alias.UpdatedAt); err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, rows.Err()
+}
+
+func (r *SQLRepository) DeleteMerchantAlias(ctx context.Context, userID, pattern string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind("DELETE FROM merchant_aliases WHERE user_id = ? AND pattern = ?"), userID, pattern)
+	if err != nil {
+		return fmt.Errorf("failed to delete merchant alias: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete merchant alias: %w", err)
+	} else if deleted == 0 {
+		return exceptions.MerchantAliasNotFound(pattern)
+	}
+	return nil
+}
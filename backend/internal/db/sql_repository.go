@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	SQLDialectPostgres = "postgres"
+	SQLDialectSQLite   = "sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var _ Repository = (*SQLRepository)(nil)
+
+// SQLRepository stores users, transactions and categories in PostgreSQL or
+// SQLite. Queries are written with ? placeholders and rebound per dialect.
+type SQLRepository struct {
+	db      *sql.DB
+	dialect string
+}
+
+// NewSQLClient opens and pings a database for the given dialect.
+func NewSQLClient(ctx context.Context, dialect, dsn string) (*sql.DB, error) {
+	var driverName string
+	switch dialect {
+	case SQLDialectPostgres:
+		driverName = "pgx"
+	case SQLDialectSQLite:
+		driverName = "sqlite3"
+	default:
+		return nil, fmt.Errorf("unsupported SQL dialect %q", dialect)
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sql.Open: %w", err)
+	}
+	if dialect == SQLDialectSQLite {
+		// SQLite only supports a single writer.
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("db.Ping: %w", err)
+	}
+	return db, nil
+}
+
+func NewSQLRepository(db *sql.DB, dialect string) *SQLRepository {
+	return &SQLRepository{db: db, dialect: dialect}
+}
+
+// Migrate applies any embedded schema migrations that have not yet been run.
+// Migration files are named NNNN_description.sql and applied in order.
+func (r *SQLRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied := make(map[int64]bool)
+	rows, err := r.db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, path := range names {
+		name := strings.TrimPrefix(path, "migrations/")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid migration file name %q", name)
+		}
+		if applied[version] {
+			continue
+		}
+
+		contents, err := migrationFiles.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := r.applyMigration(ctx, version, name, string(contents)); err != nil {
+			return err
+		}
+		log.Printf("Applied migration %s", name)
+	}
+	return nil
+}
+
+func (r *SQLRepository) applyMigration(ctx context.Context, version int64, name, contents string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Both drivers run every statement of a query given without arguments,
+	// so the file is executed whole rather than split on semicolons, which
+	// may also appear inside statements.
+	if _, err := tx.ExecContext(ctx, contents); err != nil {
+		return fmt.Errorf("migration %s failed: %w", name, err)
+	}
+
+	_, err = tx.ExecContext(ctx, r.rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"), version, name, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", name, err)
+	}
+	return tx.Commit()
+}
+
+// rebind rewrites ? placeholders into the dialect's bind parameter syntax.
+func (r *SQLRepository) rebind(query string) string {
+	if r.dialect != SQLDialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const ruleColumns = "id, name, priority, category, category_id, conditions, created_at, updated_at"
+
+func (r *SQLRepository) AddRule(ctx context.Context, userID string, rule models.CategoryRule) (string, error) {
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return "", fmt.Errorf("failed to add rule: %w", err)
+	}
+
+	id := newDocumentID()
+	_, err = r.db.ExecContext(ctx, r.rebind(`INSERT INTO rules (id, user_id, name, priority, category, category_id, conditions, created_at, updated_at)
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		id, userID, rule.Name, rule.Priority, rule.Category, rule.CategoryID, string(conditions), rule.CreatedAt.UTC(), rule.UpdatedAt.UTC())
+	if err != nil {
+		return "", fmt.Errorf("failed to add rule: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SQLRepository) GetRule(ctx context.Context, userID, ruleID string) (*models.CategoryRule, error) {
+	row := r.db.QueryRowContext(ctx, r.rebind("SELECT "+ruleColumns+" FROM rules WHERE id = ? AND user_id = ?"), ruleID, userID)
+	rule, err := scanRule(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, exceptions.RuleNotFound(ruleID)
+		}
+		return nil, fmt.Errorf("failed to get rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *SQLRepository) ListRules(ctx context.Context, userID string) ([]models.CategoryRule, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind("SELECT "+ruleColumns+" FROM rules WHERE user_id = ? ORDER BY priority, created_at, id"), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.CategoryRule
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *SQLRepository) UpdateRule(ctx context.Context, userID, ruleID string, updateData models.CategoryRuleUpdate) (*models.CategoryRule, error) {
+	sets := []string{"updated_at = ?"}
+	args := []any{time.Now().UTC()}
+	if updateData.Name != nil {
+		sets = append(sets, "name = ?")
+		args = append(args, *updateData.Name)
+	}
+	if updateData.Priority != nil {
+		sets = append(sets, "priority = ?")
+		args = append(args, *updateData.Priority)
+	}
+	if updateData.Category != nil {
+		sets = append(sets, "category = ?")
+		args = append(args, *updateData.Category)
+	}
+	if updateData.CategoryID != nil {
+		sets = append(sets, "category_id = ?")
+		args = append(args, *updateData.CategoryID)
+	}
+	if updateData.Conditions != nil {
+		conditions, err := json.Marshal(*updateData.Conditions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update rule: %w", err)
+		}
+		sets = append(sets, "conditions = ?")
+		args = append(args, string(conditions))
+	}
+	args = append(args, ruleID, userID)
+
+	result, err := r.db.ExecContext(ctx, r.rebind("UPDATE rules SET "+strings.Join(sets, ", ")+" WHERE id = ? AND user_id = ?"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rule: %w", err)
+	}
+	if updated, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to update rule: %w", err)
+	} else if updated == 0 {
+		return nil, exceptions.RuleNotFound(ruleID)
+	}
+	return r.GetRule(ctx, userID, ruleID)
+}
+
+func (r *SQLRepository) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind("DELETE FROM rules WHERE id = ? AND user_id = ?"), ruleID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	} else if deleted == 0 {
+		return exceptions.RuleNotFound(ruleID)
+	}
+	return nil
+}
+
+func scanRule(row rowScanner) (*models.CategoryRule, error) {
+	var rule models.CategoryRule
+	var conditions string
+	err := row.Scan(&rule.ID, &rule.Name, &rule.Priority, &rule.Category, &rule.CategoryID, &conditions, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(conditions), &rule.Conditions); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
@@ -0,0 +1,332 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const transactionColumns = "id, user_id, account_id, transaction_date_time, description, merchant, amount, currency, category, category_id, category_match, splits, type, bank_reference, value_date, remittance, transfer_id, inserted_at, updated_at"
+
+// transactionFilterColumns maps the filter keys accepted by ListTransactions
+// (the firestore field names) onto SQL columns.
+var transactionFilterColumns = map[string]string{
+	"userId":        "user_id",
+	"accountId":     "account_id",
+	"description":   "description",
+	"merchant":      "merchant",
+	"currency":      "currency",
+	"category":      "category",
+	"categoryId":    "category_id",
+	"type":          "type",
+	"bankReference": "bank_reference",
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *SQLRepository) AddTransaction(ctx context.Context, userID string, transaction models.Transaction) (string, error) {
+	id := newDocumentID()
+	if err := r.insertTransaction(ctx, r.db, userID, id, transaction); err != nil {
+		return "", fmt.Errorf("failed to add transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SQLRepository) GetTransactionByID(ctx context.Context, userID, transactionID string) (*models.Transaction, error) {
+	transaction, err := r.getOwnedTransaction(ctx, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	transaction.UserID = ""
+	return transaction, nil
+}
+
+func (r *SQLRepository) ListTransactions(ctx context.Context, userID string, filters map[string]string) ([]models.Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM transactions WHERE owner_id = ?"
+	args := []any{userID}
+	for key, value := range filters {
+		column, ok := transactionFilterColumns[key]
+		if !ok {
+			// Unknown fields never match, the same as an equality query in Firestore.
+			return nil, nil
+		}
+		query += " AND " + column + " = ?"
+		args = append(args, value)
+	}
+	query += " ORDER BY transaction_date_time, id"
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToListTransactionsMessage, err)
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf(exceptions.FailedToParseMessage, err)
+		}
+		transaction.UserID = ""
+		transactions = append(transactions, *transaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToListTransactionsMessage, err)
+	}
+	return transactions, nil
+}
+
+func (r *SQLRepository) BulkAddTransactions(ctx context.Context, userID string, transactions []models.Transaction) ([]models.Transaction, error) {
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("no transactions to add")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToCreateTransactionMessage, err)
+	}
+	defer tx.Rollback()
+
+	added := make([]models.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		id := newDocumentID()
+		if err := r.insertTransaction(ctx, tx, userID, id, transaction); err != nil {
+			return nil, fmt.Errorf("%s: %w", exceptions.FailedToCreateTransactionMessage, err)
+		}
+		transaction.ID = id
+		transaction.UserID = ""
+		added = append(added, transaction)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToCreateTransactionMessage, err)
+	}
+	return added, nil
+}
+
+func (r *SQLRepository) UpdateTransaction(ctx context.Context, userID, transactionID string, updateData models.TransactionUpdate) (*models.Transaction, error) {
+	if _, err := r.getOwnedTransaction(ctx, userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	var sets []string
+	var args []any
+	if updateData.AccountID != nil {
+		sets = append(sets, "account_id = ?")
+		args = append(args, *updateData.AccountID)
+	}
+	if updateData.Description != nil {
+		sets = append(sets, "description = ?")
+		args = append(args, *updateData.Description)
+	}
+	if updateData.Merchant != nil {
+		sets = append(sets, "merchant = ?")
+		args = append(args, *updateData.Merchant)
+	}
+	if updateData.Amount != nil {
+		sets = append(sets, "amount = ?")
+		args = append(args, *updateData.Amount)
+	}
+	if updateData.Currency != nil {
+		sets = append(sets, "currency = ?")
+		args = append(args, *updateData.Currency)
+	}
+	if updateData.Category != nil {
+		sets = append(sets, "category = ?")
+		args = append(args, *updateData.Category)
+	}
+	if updateData.CategoryID != nil {
+		sets = append(sets, "category_id = ?")
+		args = append(args, *updateData.CategoryID)
+	}
+	if updateData.CategoryMatch != nil {
+		match, err := encodeCategoryMatch(updateData.CategoryMatch)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", exceptions.FailedToUpdateTransactionMessage, err)
+		}
+		sets = append(sets, "category_match = ?")
+		args = append(args, match)
+	}
+	if updateData.Splits != nil {
+		splits, err := encodeSplits(*updateData.Splits)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", exceptions.FailedToUpdateTransactionMessage, err)
+		}
+		sets = append(sets, "splits = ?")
+		args = append(args, splits)
+	}
+	if updateData.TransferID != nil {
+		sets = append(sets, "transfer_id = ?")
+		args = append(args, *updateData.TransferID)
+	}
+	if updateData.Type != nil {
+		sets = append(sets, "type = ?")
+		args = append(args, *updateData.Type)
+	}
+	sets = append(sets, "updated_at = ?")
+	args = append(args, time.Now().UTC(), transactionID, userID)
+
+	query := "UPDATE transactions SET " + strings.Join(sets, ", ") + " WHERE id = ? AND owner_id = ?"
+	if _, err := r.db.ExecContext(ctx, r.rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToUpdateTransactionMessage, err)
+	}
+
+	transaction, err := r.getOwnedTransaction(ctx, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	transaction.UserID = ""
+	return transaction, nil
+}
+
+func (r *SQLRepository) DeleteTransaction(ctx context.Context, userID, transactionID string) error {
+	if _, err := r.getOwnedTransaction(ctx, userID, transactionID); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, r.rebind("DELETE FROM transactions WHERE id = ? AND owner_id = ?"), transactionID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", exceptions.FailedToDeleteTransactionMessage, err)
+	}
+	return nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (r *SQLRepository) insertTransaction(ctx context.Context, exec execer, ownerID, id string, transaction models.Transaction) error {
+	match, err := encodeCategoryMatch(transaction.CategoryMatch)
+	if err != nil {
+		return err
+	}
+	splits, err := encodeSplits(transaction.Splits)
+	if err != nil {
+		return err
+	}
+	var valueDate sql.NullTime
+	if transaction.ValueDate != nil {
+		valueDate = sql.NullTime{Time: transaction.ValueDate.UTC(), Valid: true}
+	}
+	_, err = exec.ExecContext(ctx, r.rebind(`INSERT INTO transactions
+    (id, owner_id, user_id, account_id, transaction_date_time, description, merchant, amount, currency, category, category_id, category_match, splits, type, bank_reference, value_date, remittance, transfer_id, inserted_at, updated_at)
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		id,
+		ownerID,
+		transaction.UserID,
+		transaction.AccountID,
+		transaction.TransactionDateTime.UTC(),
+		transaction.Description,
+		transaction.Merchant,
+		transaction.Amount,
+		transaction.Currency,
+		transaction.Category,
+		transaction.CategoryID,
+		match,
+		splits,
+		transaction.Type,
+		transaction.BankReference,
+		valueDate,
+		transaction.Remittance,
+		transaction.TransferID,
+		transaction.InsertedAt.UTC(),
+		transaction.UpdatedAt.UTC(),
+	)
+	return err
+}
+
+// getOwnedTransaction loads a transaction stored under userID and checks that
+// it belongs to them.
+func (r *SQLRepository) getOwnedTransaction(ctx context.Context, userID, transactionID string) (*models.Transaction, error) {
+	row := r.db.QueryRowContext(ctx, r.rebind("SELECT "+transactionColumns+" FROM transactions WHERE id = ? AND owner_id = ?"), transactionID, userID)
+	transaction, err := scanTransaction(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, exceptions.TransactionNotFound(transactionID)
+		}
+		return nil, fmt.Errorf("%s: %w", exceptions.FailedToGetTransactionMessage, err)
+	}
+	if transaction.UserID != userID {
+		return nil, exceptions.UserForbidden(userID)
+	}
+	return transaction, nil
+}
+
+func scanTransaction(row rowScanner) (*models.Transaction, error) {
+	var transaction models.Transaction
+	var match, splits string
+	var valueDate sql.NullTime
+	err := row.Scan(
+		&transaction.ID,
+		&transaction.UserID,
+		&transaction.AccountID,
+		&transaction.TransactionDateTime,
+		&transaction.Description,
+		&transaction.Merchant,
+		&transaction.Amount,
+		&transaction.Currency,
+		&transaction.Category,
+		&transaction.CategoryID,
+		&match,
+		&splits,
+		&transaction.Type,
+		&transaction.BankReference,
+		&valueDate,
+		&transaction.Remittance,
+		&transaction.TransferID,
+		&transaction.InsertedAt,
+		&transaction.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if match != "" {
+		transaction.CategoryMatch = &models.CategoryMatch{}
+		if err := json.Unmarshal([]byte(match), transaction.CategoryMatch); err != nil {
+			return nil, err
+		}
+	}
+	if splits != "" {
+		if err := json.Unmarshal([]byte(splits), &transaction.Splits); err != nil {
+			return nil, err
+		}
+	}
+	if valueDate.Valid {
+		transaction.ValueDate = &valueDate.Time
+	}
+	withLegacyDefaults(&transaction)
+	return &transaction, nil
+}
+
+// encodeCategoryMatch stores a category explanation as JSON, or "" when there
+// is none.
+func encodeCategoryMatch(match *models.CategoryMatch) (string, error) {
+	if match == nil {
+		return "", nil
+	}
+	encoded, err := json.Marshal(match)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// encodeSplits stores split lines as JSON, or "" when there are none.
+func encodeSplits(splits []models.Split) (string, error) {
+	if len(splits) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(splits)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
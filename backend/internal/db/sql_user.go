@@ -0,0 +1,122 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// userProfileColumns maps profile fields accepted by SeedNewUser onto columns.
+// Anything else is kept in the attributes JSON column.
+var userProfileColumns = map[string]string{
+	"email":        "email",
+	"firstName":    "first_name",
+	"lastName":     "last_name",
+	"baseCurrency": "base_currency",
+}
+
+func (r *SQLRepository) SeedNewUser(ctx context.Context, userID string, userData map[string]interface{}) error {
+	createdAt := time.Now().UTC()
+	if value, ok := userData["createdAt"].(time.Time); ok {
+		createdAt = value.UTC()
+	}
+
+	columns := []string{"id", "created_at"}
+	args := []any{userID, createdAt}
+	attributes := make(map[string]interface{})
+	for key, value := range userData {
+		if key == "createdAt" || value == firestore.ServerTimestamp {
+			continue
+		}
+		if column, ok := userProfileColumns[key]; ok {
+			text, _ := value.(string)
+			columns = append(columns, column)
+			args = append(args, text)
+			continue
+		}
+		attributes[key] = value
+	}
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return fmt.Errorf("failed to seed new user: %w", err)
+	}
+	columns = append(columns, "attributes")
+	args = append(args, string(encoded))
+
+	// Seeding replaces any existing profile, matching a Firestore Set.
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to seed new user: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.rebind("DELETE FROM users WHERE id = ?"), userID); err != nil {
+		return fmt.Errorf("failed to seed new user: %w", err)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	query := "INSERT INTO users (" + strings.Join(columns, ", ") + ") VALUES (" + placeholders + ")"
+	if _, err := tx.ExecContext(ctx, r.rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to seed new user: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to seed new user: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	row := r.db.QueryRowContext(ctx, r.rebind(`SELECT id, email, first_name, last_name, base_currency, created_at, updated_at
+    FROM users WHERE id = ?`), userID)
+
+	var user models.User
+	var updatedAt sql.NullTime
+	err := row.Scan(&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.BaseCurrency, &user.CreatedAt, &updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, exceptions.UserNotFound(userID)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	user.UpdatedAt = updatedAt.Time
+	withUserDefaults(&user)
+	return &user, nil
+}
+
+func (r *SQLRepository) UpdateUser(ctx context.Context, userID string, updateData models.UserUpdate) (*models.User, error) {
+	sets := []string{"updated_at = ?"}
+	args := []any{time.Now().UTC()}
+	if updateData.FirstName != nil {
+		sets = append(sets, "first_name = ?")
+		args = append(args, *updateData.FirstName)
+	}
+	if updateData.LastName != nil {
+		sets = append(sets, "last_name = ?")
+		args = append(args, *updateData.LastName)
+	}
+	if updateData.BaseCurrency != nil {
+		sets = append(sets, "base_currency = ?")
+		args = append(args, *updateData.BaseCurrency)
+	}
+	args = append(args, userID)
+
+	result, err := r.db.ExecContext(ctx, r.rebind("UPDATE users SET "+strings.Join(sets, ", ")+" WHERE id = ?"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	if updated == 0 {
+		return nil, exceptions.UserNotFound(userID)
+	}
+	return r.GetUser(ctx, userID)
+}
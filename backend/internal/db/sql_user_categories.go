@@ -0,0 +1,202 @@
+package db
+
+import (
+	"backend/internal/exceptions"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+func (r *SQLRepository) ListUserCategories(ctx context.Context, userID string) ([]models.UserCategory, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind("SELECT id, name, parent_id, keywords FROM user_categories WHERE user_id = ? ORDER BY position"), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.UserCategory
+	for rows.Next() {
+		var cat models.UserCategory
+		var keywords string
+		if err := rows.Scan(&cat.ID, &cat.Name, &cat.ParentID, &keywords); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(keywords), &cat.Keywords); err != nil {
+			return nil, err
+		}
+		categories = append(categories, cat)
+	}
+	return categories, rows.Err()
+}
+
+func (r *SQLRepository) AddUserCategory(ctx context.Context, userID string, category models.UserCategory) (string, error) {
+	keywords, err := json.Marshal(category.Keywords)
+	if err != nil {
+		return "", err
+	}
+
+	id := newDocumentID()
+	_, err = r.db.ExecContext(ctx, r.rebind(`INSERT INTO user_categories (id, user_id, position, name, parent_id, keywords)
+    VALUES (?, ?, (SELECT COALESCE(MAX(position), 0) + 1 FROM user_categories WHERE user_id = ?), ?, ?, ?)`),
+		id, userID, userID, category.Name, category.ParentID, string(keywords))
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *SQLRepository) UpdateUserCategory(ctx context.Context, userID, categoryID string, category models.UserCategory) (int, error) {
+	keywords, err := json.Marshal(category.Keywords)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	existing, err := r.getUserCategory(ctx, tx, userID, categoryID)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	if category.Name != existing.Name {
+		category.ID = categoryID
+		if changed, err = r.moveTransactions(ctx, tx, userID, *existing, category); err != nil {
+			return 0, err
+		}
+		if err := r.moveCategoryReferences(ctx, tx, userID, *existing, category); err != nil {
+			return 0, err
+		}
+	}
+	_, err = tx.ExecContext(ctx, r.rebind("UPDATE user_categories SET name = ?, parent_id = ?, keywords = ? WHERE id = ? AND user_id = ?"),
+		category.Name, category.ParentID, string(keywords), categoryID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return changed, tx.Commit()
+}
+
+func (r *SQLRepository) DeleteUserCategory(ctx context.Context, userID, categoryID, reassignTo string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	from, err := r.getUserCategory(ctx, tx, userID, categoryID)
+	if err != nil {
+		return 0, err
+	}
+	if reassignTo == categoryID {
+		return 0, exceptions.CategoryNotFound(reassignTo)
+	}
+	to, err := r.getUserCategory(ctx, tx, userID, reassignTo)
+	if err != nil {
+		return 0, err
+	}
+	changed, err := r.moveTransactions(ctx, tx, userID, *from, *to)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.moveCategoryReferences(ctx, tx, userID, *from, *to); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, r.rebind("DELETE FROM user_categories WHERE id = ? AND user_id = ?"), categoryID, userID); err != nil {
+		return 0, err
+	}
+	return changed, tx.Commit()
+}
+
+func (r *SQLRepository) getUserCategory(ctx context.Context, tx *sql.Tx, userID, categoryID string) (*models.UserCategory, error) {
+	category := models.UserCategory{ID: categoryID}
+	err := tx.QueryRowContext(ctx, r.rebind("SELECT name, parent_id FROM user_categories WHERE id = ? AND user_id = ?"), categoryID, userID).
+		Scan(&category.Name, &category.ParentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, exceptions.CategoryNotFound(categoryID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// moveTransactions puts the user's transactions and split lines in category
+// from into category to and returns how many transactions changed.
+// Transactions stored before categories had IDs are matched by name.
+func (r *SQLRepository) moveTransactions(ctx context.Context, tx *sql.Tx, userID string, from, to models.UserCategory) (int, error) {
+	rows, err := tx.QueryContext(ctx, r.rebind("SELECT "+transactionColumns+` FROM transactions
+    WHERE owner_id = ? AND (category_id = ? OR (category_id = '' AND LOWER(category) = LOWER(?)) OR splits <> '')`),
+		userID, from.ID, from.Name)
+	if err != nil {
+		return 0, err
+	}
+	var moved []*models.Transaction
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if moveCategory(transaction, from, to) {
+			moved = append(moved, transaction)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	for _, transaction := range moved {
+		splits, err := encodeSplits(transaction.Splits)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.ExecContext(ctx, r.rebind("UPDATE transactions SET category = ?, category_id = ?, splits = ?, updated_at = ? WHERE id = ? AND owner_id = ?"),
+			transaction.Category, transaction.CategoryID, splits, now, transaction.ID, userID)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(moved), nil
+}
+
+// categoryReferenceTables are the tables other than transactions whose rows
+// name a category, and whether they record when a row last changed.
+var categoryReferenceTables = []struct {
+	name      string
+	updatedAt bool
+}{
+	{"budgets", true},
+	{"allocations", false},
+	{"rules", true},
+	{"category_mappings", false},
+}
+
+// moveCategoryReferences puts the user's budgets, allocations, rules and
+// learned mappings in category from into category to, matching by name those
+// stored before categories had IDs.
+func (r *SQLRepository) moveCategoryReferences(ctx context.Context, tx *sql.Tx, userID string, from, to models.UserCategory) error {
+	now := time.Now().UTC()
+	for _, table := range categoryReferenceTables {
+		sets := "category = ?, category_id = ?"
+		args := []any{to.Name, to.ID}
+		if table.updatedAt {
+			sets += ", updated_at = ?"
+			args = append(args, now)
+		}
+		args = append(args, userID, from.ID, from.Name)
+		_, err := tx.ExecContext(ctx, r.rebind("UPDATE "+table.name+" SET "+sets+
+			" WHERE user_id = ? AND (category_id = ? OR (category_id = '' AND LOWER(category) = LOWER(?)))"), args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
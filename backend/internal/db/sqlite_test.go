@@ -0,0 +1,18 @@
+//go:build cgo
+
+package db_test
+
+import (
+	"backend/internal/db"
+	"backend/internal/db/dbtest"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteRepository(t *testing.T) {
+	dsn := "file:" + filepath.Join(t.TempDir(), "budget.db")
+	repo := newMigratedSQLRepository(t, db.SQLDialectSQLite, dsn)
+	dbtest.RunRepositoryTests(t, func(t *testing.T) db.Repository {
+		return repo
+	})
+}
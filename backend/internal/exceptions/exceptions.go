@@ -5,19 +5,77 @@ import (
 )
 
 const (
-	MissingTransactionIDMessage        = "missing transaction ID"
-	InvalidRequestBodyMessage          = "invalid request body: %v"
-	FailedToCategoriseMessage          = "failed to categorise transaction: %v"
-	FailedToCreateTransactionMessage   = "failed to create transaction"
-	FailedToBulkAddTransactionsMessage = "failed to bulk add transactions"
-	FailedToListTransactionsMessage    = "failed to list transactions"
-	TransactionNotFoundMessage         = "transaction not found"
-	FailedToGetTransactionMessage      = "failed to get transaction: %w"
-	FailedToUpdateTransactionMessage   = "failed to update transaction: %w"
-	FailedToDeleteTransactionMessage   = "failed to delete transaction: %w"
-	UserForbiddenMessage               = "user is forbidden from accessing this resource"
-	FailedToReadMessage                = "failed to read file: %v"
-	FailedToParseMessage               = "failed to parse data: %v"
+	MissingTransactionIDMessage          = "missing transaction ID"
+	InvalidRequestBodyMessage            = "invalid request body: %v"
+	FailedToCategoriseMessage            = "failed to categorise transaction"
+	FailedToCreateTransactionMessage     = "failed to create transaction"
+	FailedToBulkAddTransactionsMessage   = "failed to bulk add transactions"
+	FailedToListTransactionsMessage      = "failed to list transactions"
+	TransactionNotFoundMessage           = "transaction not found"
+	FailedToGetTransactionMessage        = "failed to get transaction"
+	FailedToUpdateTransactionMessage     = "failed to update transaction"
+	FailedToDeleteTransactionMessage     = "failed to delete transaction"
+	UserForbiddenMessage                 = "user is forbidden from accessing this resource"
+	FailedToReadMessage                  = "failed to read file: %v"
+	FailedToParseMessage                 = "failed to parse data: %v"
+	CategoryNotFoundMessage              = "category not found"
+	InvalidCategoryMessage               = "invalid category: %v"
+	CategoryHasSubcategoriesMessage      = "category still has subcategories"
+	InvalidCurrencyMessage               = "invalid currency: %v"
+	UserNotFoundMessage                  = "user not found"
+	FailedToSummariseMessage             = "failed to summarise transactions"
+	AccountNotFoundMessage               = "account not found"
+	AccountHasTransactionsMessage        = "account still has transactions"
+	InvalidAccountMessage                = "invalid account: %v"
+	FailedToListAccountsMessage          = "failed to list accounts"
+	FailedToCreateAccountMessage         = "failed to create account"
+	FailedToUpdateAccountMessage         = "failed to update account"
+	FailedToDeleteAccountMessage         = "failed to delete account"
+	BalanceCheckpointNotFoundMessage     = "balance checkpoint not found"
+	FailedToComputeBalancesMessage       = "failed to compute balances"
+	TransferNotFoundMessage              = "transaction is not part of a transfer"
+	FailedToListTransfersMessage         = "failed to list transfers"
+	FailedToLinkTransferMessage          = "failed to link transfer"
+	FailedToUnlinkTransferMessage        = "failed to unlink transfer"
+	BudgetNotFoundMessage                = "budget not found"
+	FailedToGetBudgetMessage             = "failed to get budget"
+	FailedToListBudgetsMessage           = "failed to list budgets"
+	FailedToCreateBudgetMessage          = "failed to create budget"
+	FailedToUpdateBudgetMessage          = "failed to update budget"
+	FailedToDeleteBudgetMessage          = "failed to delete budget"
+	FailedToComputeBudgetMessage         = "failed to compute budget progress"
+	FailedToComputeEnvelopesMessage      = "failed to compute envelopes"
+	FailedToUpdateEnvelopesMessage       = "failed to update envelopes"
+	FailedToListAllocationsMessage       = "failed to list allocations"
+	RuleNotFoundMessage                  = "rule not found"
+	FailedToGetRuleMessage               = "failed to get rule"
+	FailedToListRulesMessage             = "failed to list rules"
+	FailedToCreateRuleMessage            = "failed to create rule"
+	FailedToUpdateRuleMessage            = "failed to update rule"
+	FailedToDeleteRuleMessage            = "failed to delete rule"
+	FailedToRecategoriseMessage          = "failed to recategorise transactions"
+	InvalidSplitsMessage                 = "invalid splits: %v"
+	FailedToSplitTransactionMessage      = "failed to split transaction"
+	TransactionIsSplitMessage            = "transaction is split; change its splits to change its amount"
+	UnknownCategoryPackMessage           = "unknown category pack %q"
+	FailedToSeedCategoriesMessage        = "failed to seed categories"
+	CategoryMappingNotFoundMessage       = "category mapping not found"
+	FailedToListCategoryMappingsMessage  = "failed to list category mappings"
+	FailedToDeleteCategoryMappingMessage = "failed to delete category mapping"
+	MerchantAliasNotFoundMessage         = "merchant alias not found"
+	FailedToListMerchantAliasesMessage   = "failed to list merchant aliases"
+	FailedToSetMerchantAliasMessage      = "failed to set merchant alias"
+	FailedToDeleteMerchantAliasMessage   = "failed to delete merchant alias"
+	FailedToSummariseMerchantsMessage    = "failed to summarise merchants"
+	FailedToSuggestCategoriesMessage     = "failed to suggest categories"
+	FailedToAcceptSuggestionMessage      = "failed to accept category suggestion"
+	ImportProfileNotFoundMessage         = "import profile not found"
+	UnknownImportProfileMessage          = "unknown import profile %q"
+	InvalidImportProfileMessage          = "invalid import profile: %v"
+	FailedToListImportProfilesMessage    = "failed to list import profiles"
+	FailedToSaveImportProfileMessage     = "failed to save import profile"
+	FailedToDeleteImportProfileMessage   = "failed to delete import profile"
+	FailedToExportTransactionsMessage    = "failed to export transactions"
 )
 
 // TransactionNotFoundError is returned when a transaction is not found.
@@ -45,3 +103,123 @@ func (e *UserForbiddenError) Error() string {
 func UserForbidden(userID string) error {
 	return &UserForbiddenError{UserID: userID}
 }
+
+// CategoryNotFoundError is returned when a category is not found.
+type CategoryNotFoundError struct {
+	CategoryID string
+}
+
+func (e *CategoryNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", CategoryNotFoundMessage, e.CategoryID)
+}
+
+func CategoryNotFound(categoryID string) error {
+	return &CategoryNotFoundError{CategoryID: categoryID}
+}
+
+// UserNotFoundError is returned when a user profile does not exist.
+type UserNotFoundError struct {
+	UserID string
+}
+
+func (e *UserNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", UserNotFoundMessage, e.UserID)
+}
+
+func UserNotFound(userID string) error {
+	return &UserNotFoundError{UserID: userID}
+}
+
+// AccountNotFoundError is returned when an account is not found.
+type AccountNotFoundError struct {
+	AccountID string
+}
+
+func (e *AccountNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", AccountNotFoundMessage, e.AccountID)
+}
+
+func AccountNotFound(accountID string) error {
+	return &AccountNotFoundError{AccountID: accountID}
+}
+
+// BalanceCheckpointNotFoundError is returned when a balance checkpoint is not found.
+type BalanceCheckpointNotFoundError struct {
+	CheckpointID string
+}
+
+func (e *BalanceCheckpointNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", BalanceCheckpointNotFoundMessage, e.CheckpointID)
+}
+
+func BalanceCheckpointNotFound(checkpointID string) error {
+	return &BalanceCheckpointNotFoundError{CheckpointID: checkpointID}
+}
+
+// BudgetNotFoundError is returned when a budget is not found.
+type BudgetNotFoundError struct {
+	BudgetID string
+}
+
+func (e *BudgetNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", BudgetNotFoundMessage, e.BudgetID)
+}
+
+func BudgetNotFound(budgetID string) error {
+	return &BudgetNotFoundError{BudgetID: budgetID}
+}
+
+// RuleNotFoundError is returned when a categorisation rule is not found.
+type RuleNotFoundError struct {
+	RuleID string
+}
+
+func (e *RuleNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", RuleNotFoundMessage, e.RuleID)
+}
+
+func RuleNotFound(ruleID string) error {
+	return &RuleNotFoundError{RuleID: ruleID}
+}
+
+// CategoryMappingNotFoundError is returned when no category has been learned
+// for a merchant.
+type CategoryMappingNotFoundError struct {
+	Merchant string
+}
+
+func (e *CategoryMappingNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", CategoryMappingNotFoundMessage, e.Merchant)
+}
+
+func CategoryMappingNotFound(merchant string) error {
+	return &CategoryMappingNotFoundError{Merchant: merchant}
+}
+
+// MerchantAliasNotFoundError is returned when the user has no alias for a
+// pattern.
+type MerchantAliasNotFoundError struct {
+	Pattern string
+}
+
+func (e *MerchantAliasNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", MerchantAliasNotFoundMessage, e.Pattern)
+}
+
+func MerchantAliasNotFound(pattern string) error {
+	return &MerchantAliasNotFoundError{Pattern: pattern}
+}
+
+// ImportProfileNotFoundError is returned when the user has no saved import
+// profile with a name.
+type ImportProfileNotFoundError struct {
+	Name string
+}
+
+func (e *ImportProfileNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", ImportProfileNotFoundMessage, e.Name)
+}
+
+func ImportProfileNotFound(name string) error {
+	return &ImportProfileNotFoundError{Name: name}
+}
@@ -0,0 +1,80 @@
+// Package fx converts amounts between currencies using dated exchange rates.
+package fx
+
+import (
+	"backend/internal/money"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// RateProvider looks up the rate for converting one unit of from into to on
+// a given date.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string, on time.Time) (*big.Rat, error)
+}
+
+// Convert converts amount, in minor units of from, into minor units of to
+// using the rate on the given date. Results are rounded half away from zero.
+func Convert(ctx context.Context, rates RateProvider, amount int64, from, to string, on time.Time) (int64, *big.Rat, error) {
+	if from == to {
+		return amount, big.NewRat(1, 1), nil
+	}
+	rate, err := rates.Rate(ctx, from, to, on)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+	scale := money.Exponent(to) - money.Exponent(from)
+	factor := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(scale))), nil))
+	if scale >= 0 {
+		value.Mul(value, factor)
+	} else {
+		value.Quo(value, factor)
+	}
+
+	converted, ok := roundHalfAwayFromZero(value)
+	if !ok {
+		return 0, nil, fmt.Errorf("converted amount for %d %s is out of range", amount, from)
+	}
+	return converted, rate, nil
+}
+
+// FormatRate renders a rate as a decimal string for API responses.
+func FormatRate(rate *big.Rat) string {
+	s := rate.FloatString(8)
+	for len(s) > 1 && s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	if s[len(s)-1] == '.' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func roundHalfAwayFromZero(value *big.Rat) (int64, bool) {
+	num := new(big.Int).Abs(value.Num())
+	quotient, remainder := new(big.Int).QuoRem(num, value.Denom(), new(big.Int))
+	if new(big.Int).Mul(remainder, big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if value.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	if !quotient.IsInt64() {
+		return 0, false
+	}
+	return quotient.Int64(), true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
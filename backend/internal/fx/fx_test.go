@@ -0,0 +1,63 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRates = `date,from,to,rate
+2025-06-02,GBP,EUR,1.18
+2025-06-03,GBP,EUR,1.20
+2025-06-02,GBP,USD,1.35
+2025-06-02,USD,JPY,144.5
+`
+
+func TestConvert(t *testing.T) {
+	table, err := ParseTable(strings.NewReader(testRates))
+	if err != nil {
+		t.Fatalf("ParseTable: %v", err)
+	}
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, time.June, d, 15, 30, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		amount   int64
+		from, to string
+		on       time.Time
+		want     int64
+	}{
+		{"same currency", 1999, "GBP", "GBP", day(1), 1999},
+		{"direct", 1000, "GBP", "EUR", day(2), 1180},
+		{"latest rate", 1000, "GBP", "EUR", day(3), 1200},
+		{"falls back to previous day", 1000, "GBP", "EUR", day(7), 1200},
+		{"inverse rounds half away from zero", -1180, "EUR", "GBP", day(2), -1000},
+		{"cross rate", 1180, "EUR", "USD", day(2), 1350},
+		{"zero decimal currency", 100, "USD", "JPY", day(2), 145},
+	}
+	for _, tt := range tests {
+		got, _, err := Convert(ctx, table, tt.amount, tt.from, tt.to, tt.on)
+		if err != nil {
+			t.Errorf("%s: Convert returned error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Convert = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if _, _, err := Convert(ctx, table, 1000, "GBP", "EUR", day(1)); !errors.Is(err, ErrRateNotFound) {
+		t.Errorf("Convert before the first rate error = %v, want ErrRateNotFound", err)
+	}
+}
+
+func TestParseTableRejectsInvalidRows(t *testing.T) {
+	for _, input := range []string{"2025-06-02,GBP,EUR,abc", "02/06/2025,GBP,EUR,1.1", "2025-06-02,GBP,EU,1.1", "2025-06-02,GBP,EUR,-1"} {
+		if _, err := ParseTable(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseTable(%q) should fail", input)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package fx
+
+import (
+	"backend/internal/money"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rateDateLayout = "2006-01-02"
+
+type datedRate struct {
+	date time.Time
+	rate *big.Rat
+}
+
+// Table is an in-memory RateProvider, typically loaded from a CSV file with
+// the columns date,from,to,rate such as "2025-06-02,GBP,EUR,1.1873". The
+// latest rate on or before the requested date is used, so weekend and
+// holiday lookups fall back to the previous published rate. Inverse pairs
+// and a single hop through a shared currency are derived automatically.
+type Table struct {
+	mu    sync.RWMutex
+	pairs map[string][]datedRate
+}
+
+var _ RateProvider = (*Table)(nil)
+
+func NewTable() *Table {
+	return &Table{pairs: make(map[string][]datedRate)}
+}
+
+// LoadTableFile reads a rate table from a CSV file on disk.
+func LoadTableFile(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rates file: %w", err)
+	}
+	defer f.Close()
+	return ParseTable(f)
+}
+
+// ParseTable reads a rate table in CSV form. A header row is optional.
+func ParseTable(r io.Reader) (*Table, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	table := NewTable()
+	line := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return table, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rates: %w", err)
+		}
+		line++
+		if line == 1 && strings.EqualFold(record[0], "date") {
+			continue
+		}
+
+		date, err := time.Parse(rateDateLayout, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, record[0])
+		}
+		rate, ok := new(big.Rat).SetString(strings.TrimSpace(record[3]))
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, record[3])
+		}
+		if err := table.Add(date, record[1], record[2], rate); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+}
+
+// Add records the rate for converting one unit of from into to on date.
+func (t *Table) Add(date time.Time, from, to string, rate *big.Rat) error {
+	from, err := money.NormaliseCurrency(from)
+	if err != nil {
+		return err
+	}
+	to, err = money.NormaliseCurrency(to)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := pairKey(from, to)
+	rates := append(t.pairs[key], datedRate{date: truncateToDay(date), rate: new(big.Rat).Set(rate)})
+	sort.SliceStable(rates, func(i, j int) bool { return rates[i].date.Before(rates[j].date) })
+	t.pairs[key] = rates
+	return nil
+}
+
+func (t *Table) Rate(ctx context.Context, from, to string, on time.Time) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	on = truncateToDay(on)
+	if rate, ok := t.directRate(from, to, on); ok {
+		return rate, nil
+	}
+	for _, via := range t.currencies() {
+		if via == from || via == to {
+			continue
+		}
+		first, ok := t.directRate(from, via, on)
+		if !ok {
+			continue
+		}
+		second, ok := t.directRate(via, to, on)
+		if !ok {
+			continue
+		}
+		return new(big.Rat).Mul(first, second), nil
+	}
+	return nil, fmt.Errorf("%w: %s to %s on %s", ErrRateNotFound, from, to, on.Format(rateDateLayout))
+}
+
+// directRate looks up a stored pair or its inverse. Callers must hold the lock.
+func (t *Table) directRate(from, to string, on time.Time) (*big.Rat, bool) {
+	if rate, ok := latestOnOrBefore(t.pairs[pairKey(from, to)], on); ok {
+		return new(big.Rat).Set(rate), true
+	}
+	if rate, ok := latestOnOrBefore(t.pairs[pairKey(to, from)], on); ok {
+		return new(big.Rat).Inv(rate), true
+	}
+	return nil, false
+}
+
+// currencies lists every currency in the table. Callers must hold the lock.
+func (t *Table) currencies() []string {
+	seen := make(map[string]bool)
+	for key := range t.pairs {
+		from, to, _ := strings.Cut(key, "/")
+		seen[from] = true
+		seen[to] = true
+	}
+	currencies := make([]string, 0, len(seen))
+	for currency := range seen {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+func latestOnOrBefore(rates []datedRate, on time.Time) (*big.Rat, bool) {
+	i := sort.Search(len(rates), func(i int) bool { return rates[i].date.After(on) })
+	if i == 0 {
+		return nil, false
+	}
+	return rates[i-1].rate, true
+}
+
+func pairKey(from, to string) string {
+	return from + "/" + to
+}
+
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
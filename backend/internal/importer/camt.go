@@ -0,0 +1,283 @@
+package importer
+
+import (
+	"backend/internal/models"
+	"backend/internal/money"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// camtNotProvided is what camt files put in reference fields they must fill
+// but have no value for.
+const camtNotProvided = "NOTPROVIDED"
+
+// IsCAMT reports whether data looks like an ISO 20022 camt.053 bank to
+// customer statement.
+func IsCAMT(data []byte) bool {
+	head := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	return bytes.HasPrefix(head, []byte("<")) &&
+		(bytes.Contains(head, []byte("camt.053")) || bytes.Contains(head, []byte("BkToCstmrStmt")))
+}
+
+// camtDocument is the part of a camt.053 document that is read. Element
+// names match in any namespace, so every version of the message is read the
+// same way.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	Currency string        `xml:"Acct>Ccy"`
+	Balances []camtBalance `xml:"Bal"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtBalance struct {
+	Code   string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount camtAmount `xml:"Amt"`
+	Credit string     `xml:"CdtDbtInd"`
+	Date   camtDate   `xml:"Dt"`
+}
+
+type camtEntry struct {
+	Amount         camtAmount    `xml:"Amt"`
+	Credit         string        `xml:"CdtDbtInd"`
+	Status         camtStatus    `xml:"Sts"`
+	BookingDate    camtDate      `xml:"BookgDt"`
+	ValueDate      camtDate      `xml:"ValDt"`
+	Reference      string        `xml:"AcctSvcrRef"`
+	EntryReference string        `xml:"NtryRef"`
+	Details        []camtDetails `xml:"NtryDtls>TxDtls"`
+	AdditionalInfo string        `xml:"AddtlNtryInf"`
+}
+
+// camtDetails is one transaction within an entry. Parties are named directly
+// in older versions of the message and within Pty in newer ones.
+type camtDetails struct {
+	EndToEndID      string   `xml:"Refs>EndToEndId"`
+	Reference       string   `xml:"Refs>AcctSvcrRef"`
+	Creditor        string   `xml:"RltdPties>Cdtr>Nm"`
+	CreditorParty   string   `xml:"RltdPties>Cdtr>Pty>Nm"`
+	Debtor          string   `xml:"RltdPties>Dbtr>Nm"`
+	DebtorParty     string   `xml:"RltdPties>Dbtr>Pty>Nm"`
+	Unstructured    []string `xml:"RmtInf>Ustrd"`
+	CreditorRefInfo []string `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	AdditionalInfo  string   `xml:"AddtlTxInf"`
+}
+
+// camtStatus is an entry's status, written directly in older versions of
+// the message and as a code in newer ones.
+type camtStatus struct {
+	Value string `xml:",chardata"`
+	Code  string `xml:"Cd"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+// ParseCAMT reads the booked entries of the statements in an ISO 20022
+// camt.053 file, leaving the transactions uncategorised. Pending entries are
+// skipped. Each entry becomes a transaction dated by its booking date, with
+// its value date, the counterparty's name as the description and the
+// remittance information kept apart. Entries that batch several payments are
+// read as one transaction for the entry's total. An entry's servicer or
+// entry reference is unique within the account; without either, a
+// transaction's own references are used. Each statement's closing
+// booked balance is returned as a checkpoint.
+func ParseCAMT(r io.Reader, userID, currency string) (*Statement, error) {
+	var document camtDocument
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid camt.053: %w", err)
+	}
+
+	statement := &Statement{Profile: "camt.053", Transactions: []models.Transaction{}, UniqueReferences: map[string]bool{}}
+	now := time.Now()
+	for _, stmt := range document.Statements {
+		statementCurrency := currencyOr(stmt.Currency, currency)
+		for _, entry := range stmt.Entries {
+			if status := strings.TrimSpace(entry.Status.Code + entry.Status.Value); status != "" && !strings.EqualFold(status, "BOOK") {
+				continue
+			}
+			transaction, err := camtTransaction(entry, userID, statementCurrency, now)
+			if err != nil {
+				return nil, err
+			}
+			statement.Transactions = append(statement.Transactions, transaction)
+			if reference := entry.reference(); reference != "" {
+				statement.UniqueReferences[reference] = true
+			}
+		}
+		for _, balance := range stmt.Balances {
+			if balance.Code != "CLBD" {
+				continue
+			}
+			checkpoint, err := camtCheckpoint(balance, statementCurrency, now)
+			if err != nil {
+				return nil, err
+			}
+			statement.Checkpoints = append(statement.Checkpoints, checkpoint)
+		}
+	}
+	return statement, nil
+}
+
+// reference is the account servicer's reference for the entry, or failing
+// that its entry reference.
+func (entry camtEntry) reference() string {
+	return firstNonEmpty(entry.Reference, entry.EntryReference)
+}
+
+func camtTransaction(entry camtEntry, userID, currency string, now time.Time) (models.Transaction, error) {
+	reference := entry.reference()
+	var details camtDetails
+	if len(entry.Details) > 0 {
+		details = entry.Details[0]
+	}
+	if reference == "" {
+		reference = details.Reference
+	}
+	if reference == "" && details.EndToEndID != camtNotProvided {
+		reference = details.EndToEndID
+	}
+
+	currency = currencyOr(entry.Amount.Currency, currency)
+	amount, err := camtSignedAmount(entry.Amount.Value, entry.Credit, currency)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("invalid camt.053 entry %s: %w", reference, err)
+	}
+	bookingDate, err := entry.BookingDate.parse()
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("invalid camt.053 entry %s: booking date: %w", reference, err)
+	}
+	valueDate, err := entry.ValueDate.parse()
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("invalid camt.053 entry %s: value date: %w", reference, err)
+	}
+	if bookingDate == nil {
+		if valueDate == nil {
+			return models.Transaction{}, fmt.Errorf("invalid camt.053 entry %s: no booking or value date", reference)
+		}
+		bookingDate = valueDate
+	}
+
+	// The counterparty is whoever is paid for a debit and whoever paid for a
+	// credit.
+	counterparty := firstNonEmpty(details.Creditor, details.CreditorParty)
+	if amount > 0 {
+		counterparty = firstNonEmpty(details.Debtor, details.DebtorParty)
+	}
+	remittance := strings.Join(append(trimAll(details.Unstructured), trimAll(details.CreditorRefInfo)...), " ")
+	description := firstNonEmpty(counterparty, remittance, details.AdditionalInfo, entry.AdditionalInfo)
+
+	return models.Transaction{
+		UserID:              userID,
+		TransactionDateTime: *bookingDate,
+		ValueDate:           valueDate,
+		Description:         description,
+		Remittance:          remittance,
+		Amount:              amount,
+		Currency:            currency,
+		Type:                transactionType(amount),
+		BankReference:       reference,
+		InsertedAt:          now,
+		UpdatedAt:           now,
+	}, nil
+}
+
+func camtCheckpoint(balance camtBalance, currency string, now time.Time) (models.BalanceCheckpoint, error) {
+	amount, err := camtSignedAmount(balance.Amount.Value, balance.Credit, currencyOr(balance.Amount.Currency, currency))
+	if err != nil {
+		return models.BalanceCheckpoint{}, fmt.Errorf("invalid camt.053 closing balance: %w", err)
+	}
+	date, err := balance.Date.parse()
+	if err != nil || date == nil {
+		return models.BalanceCheckpoint{}, errors.New("invalid camt.053 closing balance date")
+	}
+	return models.BalanceCheckpoint{Date: *date, Balance: amount, Source: models.BalanceSourceStatement, CreatedAt: now}, nil
+}
+
+// camtSignedAmount reads an unsigned camt amount, negating it when the
+// indicator is DBIT.
+func camtSignedAmount(value, indicator, currency string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "-") {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	amount, err := money.ParseMinorUnits(value, currency)
+	if err != nil {
+		return 0, err
+	}
+	switch strings.TrimSpace(indicator) {
+	case "DBIT":
+		return -amount, nil
+	case "CRDT":
+		return amount, nil
+	}
+	return 0, fmt.Errorf("invalid credit or debit indicator %q", indicator)
+}
+
+// currencyOr returns code when it is a valid currency and fallback
+// otherwise.
+func currencyOr(code, fallback string) string {
+	if code == "" {
+		return fallback
+	}
+	if normalised, err := money.NormaliseCurrency(code); err == nil {
+		return normalised
+	}
+	return fallback
+}
+
+// parse returns the date, ignoring any time, or nil when there is none.
+func (d camtDate) parse() (*time.Time, error) {
+	value := strings.TrimSpace(d.Date)
+	if value == "" {
+		value = strings.TrimSpace(d.DateTime)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	if len(value) > len(time.DateOnly) {
+		value = value[:len(time.DateOnly)]
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", value)
+	}
+	return &date, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// trimAll returns values trimmed of space, leaving out the empty ones.
+func trimAll(values []string) []string {
+	var trimmed []string
+	for _, value := range values {
+		if value = strings.Join(strings.Fields(value), " "); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
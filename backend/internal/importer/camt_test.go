@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleCAMT = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">
+  <BkToCstmrStmt>
+    <GrpHdr><MsgId>STMT-2025-06</MsgId><CreDtTm>2025-06-04T08:00:00</CreDtTm></GrpHdr>
+    <Stmt>
+      <Id>STMT-2025-06-01</Id>
+      <Acct><Id><IBAN>DE89370400440532013000</IBAN></Id><Ccy>EUR</Ccy></Acct>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="EUR">1000.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2025-06-01</Dt></Dt>
+      </Bal>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="EUR">2376.65</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2025-06-03</Dt></Dt>
+      </Bal>
+      <Ntry>
+        <Amt Ccy="EUR">123.45</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><Dt>2025-06-02</Dt></BookgDt>
+        <ValDt><Dt>2025-06-01</Dt></ValDt>
+        <AcctSvcrRef>2025060200001</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+          <RltdPties>
+            <Dbtr><Pty><Nm>Example Ltd</Nm></Pty></Dbtr>
+            <Cdtr><Pty><Nm>Stadtwerke Musterstadt</Nm></Pty></Cdtr>
+          </RltdPties>
+          <RmtInf><Ustrd>Kundennr 4711</Ustrd><Ustrd>Abschlag Juni</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">1500.10</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><DtTm>2025-06-03T10:15:00+02:00</DtTm></BookgDt>
+        <ValDt><Dt>2025-06-03</Dt></ValDt>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>INV-117</EndToEndId></Refs>
+          <RltdPties><Dbtr><Pty><Nm>ACME GmbH</Nm></Pty></Dbtr></RltdPties>
+          <RmtInf><Strd><CdtrRefInf><Ref>RF18539007547034</Ref></CdtrRefInf></Strd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">9.99</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts><Cd>PDNG</Cd></Sts>
+        <BookgDt><Dt>2025-06-04</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>
+`
+
+func TestParseCAMT(t *testing.T) {
+	statement, err := Parse(strings.NewReader(sampleCAMT), "user", "GBP", BuiltInProfiles(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Transactions) != 2 {
+		t.Fatalf("Transactions = %+v, want the 2 booked entries", statement.Transactions)
+	}
+	june := func(day int) time.Time { return time.Date(2025, time.June, day, 0, 0, 0, 0, time.UTC) }
+	bill, invoice := statement.Transactions[0], statement.Transactions[1]
+
+	if bill.Amount != -12345 || bill.Currency != "EUR" || bill.Description != "Stadtwerke Musterstadt" ||
+		bill.Remittance != "Kundennr 4711 Abschlag Juni" || bill.BankReference != "2025060200001" ||
+		!bill.TransactionDateTime.Equal(june(2)) || bill.ValueDate == nil || !bill.ValueDate.Equal(june(1)) ||
+		bill.Type != "Debit" || bill.UserID != "user" {
+		t.Errorf("Transactions[0] = %+v, want the creditor paid on 2 June, valued 1 June", bill)
+	}
+	if invoice.Amount != 150010 || invoice.Description != "ACME GmbH" || invoice.Remittance != "RF18539007547034" ||
+		invoice.BankReference != "INV-117" || !invoice.TransactionDateTime.Equal(june(3)) {
+		t.Errorf("Transactions[1] = %+v, want the debtor who paid", invoice)
+	}
+	// End-to-end IDs are the payer's and can repeat.
+	if len(statement.UniqueReferences) != 1 || !statement.UniqueReferences["2025060200001"] {
+		t.Errorf("UniqueReferences = %v, want only the servicer's reference", statement.UniqueReferences)
+	}
+	if len(statement.Checkpoints) != 1 || statement.Checkpoints[0].Balance != 237665 || !statement.Checkpoints[0].Date.Equal(june(3)) {
+		t.Errorf("Checkpoints = %+v, want the closing booked balance", statement.Checkpoints)
+	}
+}
+
+func TestParseCAMTOlderVersion(t *testing.T) {
+	camt := `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"><BkToCstmrStmt><Stmt>
+<Ntry><Amt Ccy="CHF">20.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>BOOK</Sts><BookgDt><Dt>2025-06-02</Dt></BookgDt>
+<NtryDtls><TxDtls><RltdPties><Cdtr><Nm>Migros</Nm></Cdtr></RltdPties></TxDtls></NtryDtls></Ntry>
+<Ntry><Amt Ccy="CHF">5.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>PDNG</Sts><BookgDt><Dt>2025-06-03</Dt></BookgDt></Ntry>
+</Stmt></BkToCstmrStmt></Document>`
+	statement, err := Parse(strings.NewReader(camt), "user", "GBP", BuiltInProfiles(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Transactions) != 1 {
+		t.Fatalf("Transactions = %+v, want the booked entry", statement.Transactions)
+	}
+	if migros := statement.Transactions[0]; migros.Description != "Migros" || migros.Amount != -2000 || migros.Currency != "CHF" || migros.ValueDate != nil {
+		t.Errorf("Transactions[0] = %+v, want the creditor named directly", migros)
+	}
+}
+
+func TestParseCAMTInvalidAmount(t *testing.T) {
+	camt := strings.Replace(sampleCAMT, "<CdtDbtInd>DBIT</CdtDbtInd>", "<CdtDbtInd>X</CdtDbtInd>", 1)
+	if _, err := ParseCAMT(strings.NewReader(camt), "user", "GBP"); err == nil {
+		t.Error("ParseCAMT with an unknown credit or debit indicator succeeded")
+	}
+}
@@ -0,0 +1,284 @@
+package importer
+
+import (
+	"backend/internal/models"
+	"backend/internal/money"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrUnrecognised is returned when none of the profiles fit a file.
+var ErrUnrecognised = errors.New("unrecognised statement layout; choose an import profile or save one for this bank")
+
+// headerSearchRows is how far into a file the header is looked for, past the
+// account details some banks put above it.
+const headerSearchRows = 10
+
+// StatementBalance is the balance a statement row reports after its
+// transaction.
+type StatementBalance struct {
+	Date    time.Time
+	Amount  int64
+	Balance int64
+}
+
+// Statement is what was read from an export. Profile names the layout or
+// format it was read with. Balances are present when the export has a
+// balance per row, Checkpoints when it states balances of its own and
+// Categories when it names the categories of its transactions.
+// UniqueReferences holds the bank references the format promises are never
+// reused within an account, such as OFX FITIDs; other references, like
+// cheque numbers or a payment's free-text reference, can repeat.
+type Statement struct {
+	Profile          string
+	Transactions     []models.Transaction
+	Balances         []StatementBalance
+	Checkpoints      []models.BalanceCheckpoint
+	Categories       []models.PackCategory
+	UniqueReferences map[string]bool
+}
+
+// Options are the caller's choices about how to read a file.
+type Options struct {
+	// Sheet names the worksheet to read from a workbook; the first is read
+	// when it is empty.
+	Sheet string
+	// HeaderRow is the row, counting from 1, that holds the column names, or
+	// 0 to look for them.
+	HeaderRow int
+}
+
+// ParseCSV reads a bank statement export using whichever of profiles fits its
+// header, leaving the transactions uncategorised. Profiles are tried in
+// order, and where several fit the one that uses the most columns wins. Rows
+// without a valid date and amount, such as totals, are skipped.
+func ParseCSV(r io.Reader, userID, currency string, profiles []models.ImportProfile, options Options) (*Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	csvReader := csv.NewReader(bytes.NewReader(decodeText(data)))
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	return parseRecords(records, userID, currency, profiles, options, nil)
+}
+
+// parseRecords reads the rows of a CSV file or worksheet with whichever of
+// profiles fits them. Dates are read in the profile's format or as ISO
+// dates, which is how worksheets' date cells are given. Numbers in the date
+// column are read with fromSerial when it is set, for the serial dates
+// spreadsheets store.
+func parseRecords(records [][]string, userID, currency string, profiles []models.ImportProfile, options Options, fromSerial func(float64) (time.Time, bool)) (*Statement, error) {
+	offset, searchRows := 0, headerSearchRows
+	if options.HeaderRow > 0 {
+		if options.HeaderRow > len(records) {
+			return nil, fmt.Errorf("header row %d is past the last row, %d", options.HeaderRow, len(records))
+		}
+		offset, searchRows = options.HeaderRow-1, 1
+	}
+	profile, columns, start, ok := detect(records[offset:], profiles, searchRows)
+	if !ok {
+		return nil, ErrUnrecognised
+	}
+	layout, err := dateLayout(profile.DateFormat)
+	if err != nil {
+		return nil, fmt.Errorf("import profile %s: %w", profile.Name, err)
+	}
+
+	statement := &Statement{Profile: profile.Name, Transactions: []models.Transaction{}}
+	now := time.Now()
+	for _, record := range records[offset+start:] {
+		date, err := parseDate(layout, columns.value(record, profile.Columns.Date), fromSerial)
+		if err != nil {
+			continue
+		}
+		amount, err := columns.amount(record, profile, currency)
+		if err != nil {
+			continue
+		}
+		// Spreadsheet exports quote references with apostrophes to keep
+		// them as text.
+		reference := strings.Trim(columns.value(record, profile.Columns.Reference), "'")
+		statement.Transactions = append(statement.Transactions, models.Transaction{
+			UserID:              userID,
+			TransactionDateTime: date,
+			Description:         columns.value(record, profile.Columns.Description),
+			Amount:              amount,
+			Currency:            currency,
+			Type:                transactionType(amount),
+			BankReference:       reference,
+			InsertedAt:          now,
+			UpdatedAt:           now,
+		})
+		if profile.Columns.Balance != "" {
+			if balance, err := money.ParseMinorUnits(columns.value(record, profile.Columns.Balance), currency); err == nil {
+				statement.Balances = append(statement.Balances, StatementBalance{Date: date, Amount: amount, Balance: balance})
+			}
+		}
+	}
+	return statement, nil
+}
+
+// detect finds the profile that fits records, the positions of its columns
+// and the first data row. A header is looked for in the first searchRows
+// rows; failing that, the first row is checked against the profiles for
+// exports without one.
+func detect(records [][]string, profiles []models.ImportProfile, searchRows int) (models.ImportProfile, columnIndex, int, bool) {
+	for i := 0; i < len(records) && i < searchRows; i++ {
+		best, bestScore := -1, 0
+		for j, profile := range profiles {
+			if len(profile.Header) > 0 {
+				continue
+			}
+			if score := headerScore(records[i], profile); score > bestScore {
+				best, bestScore = j, score
+			}
+		}
+		if best >= 0 {
+			return profiles[best], newColumnIndex(records[i]), i + 1, true
+		}
+	}
+	if len(records) == 0 {
+		return models.ImportProfile{}, nil, 0, false
+	}
+	for _, profile := range profiles {
+		if len(profile.Header) != len(records[0]) {
+			continue
+		}
+		layout, err := dateLayout(profile.DateFormat)
+		if err != nil {
+			continue
+		}
+		columns := newColumnIndex(profile.Header)
+		if _, err := time.Parse(layout, columns.value(records[0], profile.Columns.Date)); err == nil {
+			return profile, columns, 0, true
+		}
+	}
+	return models.ImportProfile{}, nil, 0, false
+}
+
+// headerScore is how many of profile's columns and identifiers header has,
+// or 0 unless it has all of them.
+func headerScore(header []string, profile models.ImportProfile) int {
+	names := append(mappedColumns(profile.Columns), profile.Identifiers...)
+	for _, name := range names {
+		if headerIndex(header, name) < 0 {
+			return 0
+		}
+	}
+	return len(names)
+}
+
+// parseDate reads a date in layout, or as an ISO date, or as a spreadsheet
+// serial number when fromSerial is set.
+func parseDate(layout, value string, fromSerial func(float64) (time.Time, bool)) (time.Time, error) {
+	date, err := time.Parse(layout, value)
+	if err == nil {
+		return date, nil
+	}
+	if date, isoErr := time.Parse(time.DateOnly, value); isoErr == nil {
+		return date, nil
+	}
+	if fromSerial != nil {
+		if serial, serialErr := strconv.ParseFloat(value, 64); serialErr == nil {
+			if date, ok := fromSerial(serial); ok {
+				return date, nil
+			}
+		}
+	}
+	return time.Time{}, err
+}
+
+func headerIndex(header []string, name string) int {
+	for i, cell := range header {
+		if strings.EqualFold(strings.TrimSpace(cell), strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// columnIndex maps lowercased header names to their positions.
+type columnIndex map[string]int
+
+func newColumnIndex(header []string) columnIndex {
+	columns := make(columnIndex, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[key]; !ok {
+			columns[key] = i
+		}
+	}
+	return columns
+}
+
+// value returns the trimmed cell under the header name, or "" when the
+// column is unmapped or the row is short.
+func (c columnIndex) value(record []string, name string) string {
+	i, ok := c[strings.ToLower(strings.TrimSpace(name))]
+	if name == "" || !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// amount reads a row's amount, negative for money out, from either the
+// amount column or the debit and credit columns.
+func (c columnIndex) amount(record []string, profile models.ImportProfile, currency string) (int64, error) {
+	if profile.Columns.Amount != "" {
+		amount, err := money.ParseMinorUnits(c.value(record, profile.Columns.Amount), currency)
+		if profile.InvertSign {
+			amount = -amount
+		}
+		return amount, err
+	}
+	if debit := c.value(record, profile.Columns.Debit); debit != "" {
+		amount, err := money.ParseMinorUnits(debit, currency)
+		return -abs(amount), err
+	}
+	amount, err := money.ParseMinorUnits(c.value(record, profile.Columns.Credit), currency)
+	return abs(amount), err
+}
+
+func abs(amount int64) int64 {
+	if amount < 0 {
+		return -amount
+	}
+	return amount
+}
+
+// decodeText drops a UTF-8 byte order mark and reads text that isn't UTF-8
+// as Latin-1, which some banks still export in.
+func decodeText(data []byte) []byte {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if utf8.Valid(data) {
+		return data
+	}
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
+
+func transactionType(amount int64) string {
+	switch {
+	case amount < 0:
+		return "Debit"
+	case amount > 0:
+		return "Credit"
+	default:
+		return "-" // shouldn't have zero amounts
+	}
+}
@@ -0,0 +1,160 @@
+package importer
+
+import (
+	"backend/internal/models"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSVDetectsBank(t *testing.T) {
+	june := func(day int) time.Time { return time.Date(2025, time.June, day, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		profile      string
+		csv          string
+		descriptions []string
+		amounts      []int64
+		references   []string
+		balances     int
+	}{
+		{
+			profile: "monzo",
+			csv: "Transaction ID,Date,Time,Type,Name,Emoji,Category,Amount,Currency,Local amount,Local currency,Notes and #tags,Address,Receipt,Description,Category split,Money Out,Money In\n" +
+				"tx_0001,02/06/2025,09:14:02,Card payment,Pret A Manger,,Eating out,-4.50,GBP,-4.50,GBP,,,,PRET A MANGER LONDON,,-4.50,\n" +
+				"tx_0002,03/06/2025,12:00:00,Faster payment,Acme Ltd,,Income,2500.00,GBP,2500.00,GBP,,,,ACME LTD SALARY,,,2500.00\n",
+			descriptions: []string{"Pret A Manger", "Acme Ltd"},
+			amounts:      []int64{-450, 250000},
+			references:   []string{"tx_0001", "tx_0002"},
+		},
+		{
+			profile: "starling",
+			csv: "Date,Counter Party,Reference,Type,Amount (GBP),Balance (GBP),Spending Category,Notes\n" +
+				"02/06/2025,Tesco,TESCO STORES 3021,CONTACTLESS,-23.45,976.55,GROCERIES,\n" +
+				"03/06/2025,J Smith,RENT,FASTER PAYMENT,-800.00,176.55,BILLS,\n",
+			descriptions: []string{"Tesco", "J Smith"},
+			amounts:      []int64{-2345, -80000},
+			references:   []string{"TESCO STORES 3021", "RENT"},
+			balances:     2,
+		},
+		{
+			profile: "barclays",
+			csv: "Number,Date,Account,Amount,Subcategory,Memo\n" +
+				",02/06/2025,20-00-00 12345678,-23.45,PAYMENT,TESCO STORES 3021\n" +
+				",03/06/2025,20-00-00 12345678,1500.00,DIRECTDEP,ACME LTD\n",
+			descriptions: []string{"TESCO STORES 3021", "ACME LTD"},
+			amounts:      []int64{-2345, 150000},
+			references:   []string{"", ""},
+		},
+		{
+			profile:      "hsbc",
+			csv:          "02/06/2025,TESCO STORES 3021,-23.45\n03/06/2025,\"ACME LTD, SALARY\",\"1,500.00\"\n",
+			descriptions: []string{"TESCO STORES 3021", "ACME LTD, SALARY"},
+			amounts:      []int64{-2345, 150000},
+			references:   []string{"", ""},
+		},
+		{
+			profile: "nationwide",
+			csv: "\"Account Name:\",\"FlexAccount ****1234\"\n" +
+				"\"Account Balance:\",\"\xa31,176.55\"\n" +
+				"\"Available Balance: \",\"\xa31,176.55\"\n" +
+				"\n" +
+				"\"Date\",\"Transaction type\",\"Description\",\"Paid out\",\"Paid in\",\"Balance\"\n" +
+				"\"02 Jun 2025\",\"Contactless payment\",\"TESCO STORES 3021\",\"\xa323.45\",\"\",\"\xa3976.55\"\n" +
+				"\"03 Jun 2025\",\"Bank credit\",\"ACME LTD\",\"\",\"\xa3200.00\",\"\xa31,176.55\"\n",
+			descriptions: []string{"TESCO STORES 3021", "ACME LTD"},
+			amounts:      []int64{-2345, 20000},
+			references:   []string{"", ""},
+			balances:     2,
+		},
+		{
+			profile: "lloyds",
+			csv: "\xef\xbb\xbfTransaction Date,Transaction Type,Sort Code,Account Number,Transaction Description,Debit Amount,Credit Amount,Balance\n" +
+				"02/06/2025,DEB,'30-00-00,12345678,TESCO STORES 3021,23.45,,976.55\n" +
+				"03/06/2025,BGC,'30-00-00,12345678,ACME LTD,,200.00,1176.55\n",
+			descriptions: []string{"TESCO STORES 3021", "ACME LTD"},
+			amounts:      []int64{-2345, 20000},
+			references:   []string{"", ""},
+			balances:     2,
+		},
+		{
+			profile: "amex",
+			csv: "Date,Description,Amount,Extended Details,Appears On Your Statement As,Address,Town/City,Postcode,Country,Reference,Category\n" +
+				"02/06/2025,TESCO STORES 3021,23.45,,TESCO STORES,,LONDON,,UNITED KINGDOM,'AT251530001',General Purchases-Groceries\n" +
+				"03/06/2025,PAYMENT RECEIVED - THANK YOU,-100.00,,,,,,,'AT251540002',\n",
+			descriptions: []string{"TESCO STORES 3021", "PAYMENT RECEIVED - THANK YOU"},
+			amounts:      []int64{-2345, 10000},
+			references:   []string{"AT251530001", "AT251540002"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			statement, err := ParseCSV(strings.NewReader(tt.csv), "user", "GBP", BuiltInProfiles(), Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if statement.Profile != tt.profile {
+				t.Errorf("Profile = %q, want %q", statement.Profile, tt.profile)
+			}
+			if len(statement.Transactions) != len(tt.amounts) {
+				t.Fatalf("Transactions = %+v, want %d", statement.Transactions, len(tt.amounts))
+			}
+			for i, transaction := range statement.Transactions {
+				if !transaction.TransactionDateTime.Equal(june(2+i)) || transaction.Description != tt.descriptions[i] ||
+					transaction.Amount != tt.amounts[i] || transaction.BankReference != tt.references[i] ||
+					transaction.UserID != "user" || transaction.Currency != "GBP" {
+					t.Errorf("Transactions[%d] = %+v, want %s %q %d ref %q", i, transaction, june(2+i).Format("02/01"), tt.descriptions[i], tt.amounts[i], tt.references[i])
+				}
+			}
+			if len(statement.Balances) != tt.balances {
+				t.Errorf("Balances = %+v, want %d", statement.Balances, tt.balances)
+			}
+		})
+	}
+}
+
+func TestParseCSVUnrecognised(t *testing.T) {
+	csv := "When,What,How much\n02/06/2025,TESCO,-23.45\n"
+	if _, err := ParseCSV(strings.NewReader(csv), "user", "GBP", BuiltInProfiles(), Options{}); !errors.Is(err, ErrUnrecognised) {
+		t.Fatalf("ParseCSV = %v, want ErrUnrecognised", err)
+	}
+
+	custom := models.ImportProfile{
+		Name:       "credit union",
+		Columns:    models.ImportColumns{Date: "when", Description: "what", Amount: "how much"},
+		DateFormat: "DD/MM/YYYY",
+	}
+	statement, err := ParseCSV(strings.NewReader(csv), "user", "GBP", Merge([]models.ImportProfile{custom}), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.Profile != "credit union" || len(statement.Transactions) != 1 || statement.Transactions[0].Amount != -2345 {
+		t.Errorf("ParseCSV with a saved profile = %+v", statement)
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	valid := models.ImportProfile{
+		Name:       " My Bank ",
+		Columns:    models.ImportColumns{Date: "Date", Description: "Payee", Debit: "Out", Credit: "In"},
+		DateFormat: "YYYY-MM-DD",
+	}
+	if err := ValidateProfile(&valid); err != nil || valid.Name != "my bank" {
+		t.Errorf("ValidateProfile = %v with name %q, want ok and my bank", err, valid.Name)
+	}
+	for name, change := range map[string]func(*models.ImportProfile){
+		"no name":          func(p *models.ImportProfile) { p.Name = "" },
+		"slash in name":    func(p *models.ImportProfile) { p.Name = "a/b" },
+		"no amount":        func(p *models.ImportProfile) { p.Columns.Debit, p.Columns.Credit = "", "" },
+		"amount and debit": func(p *models.ImportProfile) { p.Columns.Amount = "Amount" },
+		"no date format":   func(p *models.ImportProfile) { p.DateFormat = "" },
+		"bad date format":  func(p *models.ImportProfile) { p.DateFormat = "2006-01-02" },
+		"header missing":   func(p *models.ImportProfile) { p.Header = []string{"Date", "Payee", "Out"} },
+	} {
+		profile := valid
+		change(&profile)
+		if err := ValidateProfile(&profile); err == nil {
+			t.Errorf("ValidateProfile with %s succeeded", name)
+		}
+	}
+}
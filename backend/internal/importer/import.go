@@ -0,0 +1,37 @@
+package importer
+
+import (
+	"backend/internal/models"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// Parse reads a statement export in whichever format its content is in:
+// OFX or QFX, camt.053, MT940, QIF, or otherwise a CSV file or Excel
+// workbook using profiles and options.
+func Parse(r io.Reader, userID, currency string, profiles []models.ImportProfile, options Options) (*Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if IsOFX(data) {
+		return ParseOFX(bytes.NewReader(data), userID, currency)
+	}
+	if IsCAMT(data) {
+		return ParseCAMT(bytes.NewReader(data), userID, currency)
+	}
+	if IsMT940(data) {
+		return ParseMT940(bytes.NewReader(data), userID, currency)
+	}
+	if IsQIF(data) {
+		return ParseQIF(bytes.NewReader(data), userID, currency)
+	}
+	if IsXLSX(data) {
+		return ParseXLSX(bytes.NewReader(data), userID, currency, profiles, options)
+	}
+	if bytes.HasPrefix(data, xlsSignature) {
+		return nil, errors.New("old .xls workbooks are not supported; save the workbook as .xlsx")
+	}
+	return ParseCSV(bytes.NewReader(data), userID, currency, profiles, options)
+}
@@ -0,0 +1,313 @@
+package importer
+
+import (
+	"backend/internal/models"
+	"backend/internal/money"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// mt940Tag matches the tag that starts a field, such as ":61:" or ":60F:".
+	mt940Tag = regexp.MustCompile(`^:(\d{2}[A-Z]?):`)
+	// mt940StatementLine splits a :61: field into its value date, entry date,
+	// debit or credit mark, funds code, amount, transaction type, customer
+	// and bank references and supplementary details.
+	mt940StatementLine = regexp.MustCompile(`(?s)^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d*)([NFS][A-Z0-9]{3})([^\n]*?)(?://([^\n]*))?(?:\n(.*))?$`)
+	// mt940Balance splits a balance field into its debit or credit mark,
+	// date, currency and amount.
+	mt940Balance = regexp.MustCompile(`^(C|D)(\d{6})([A-Z]{3})(\d+,\d*)$`)
+	// mt940Subfield matches the "?20" style subfield codes German banks use
+	// in :86: fields.
+	mt940Subfield = regexp.MustCompile(`\?(\d{2})`)
+)
+
+// mt940InfoKeys are the keywords of the "/NAME/.../REMI/..." style :86:
+// fields that Dutch and Belgian banks use.
+var mt940InfoKeys = map[string]bool{
+	"TRTP": true, "IBAN": true, "BIC": true, "NAME": true, "REMI": true, "EREF": true, "MARF": true,
+	"CSID": true, "CNTP": true, "ORDP": true, "BENM": true, "ADDR": true, "ISDT": true, "ULTC": true,
+	"ULTD": true, "PURP": true, "RTRN": true, "SVCL": true, "ID": true, "PREF": true, "ACCW": true,
+}
+
+// IsMT940 reports whether data looks like a SWIFT MT940 statement, which
+// starts with its :20: reference and names its account in :25:.
+func IsMT940(data []byte) bool {
+	head := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	return (bytes.HasPrefix(head, []byte(":20:")) || bytes.HasPrefix(head, []byte("{1:"))) &&
+		bytes.Contains(head, []byte(":25:"))
+}
+
+// mt940Field is a tagged field, with its continuation lines joined by "\n".
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+// ParseMT940 reads the statement lines of a SWIFT MT940 file, leaving the
+// transactions uncategorised. Each :61: line becomes a transaction dated by
+// its entry date, or its value date when it has none, with the bank's
+// reference. The counterparty's name and the remittance information are
+// read from the :86: field that follows it when it is in the German "?20"
+// or the "/NAME/" style, and otherwise the whole field is taken as
+// remittance information. Amounts are in each statement's currency, and
+// each final closing balance is returned as a checkpoint.
+func ParseMT940(r io.Reader, userID, currency string) (*Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := mt940Fields(decodeText(data))
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &Statement{Profile: "mt940", Transactions: []models.Transaction{}}
+	statementCurrency := currency
+	var pending *models.Transaction
+	flush := func() {
+		if pending != nil {
+			statement.Transactions = append(statement.Transactions, *pending)
+			pending = nil
+		}
+	}
+	now := time.Now()
+	for _, field := range fields {
+		switch field.tag {
+		case "20":
+			flush()
+			statementCurrency = currency
+		case "60F", "60M":
+			_, _, balanceCurrency, err := mt940ParseBalance(field.value, currency)
+			if err != nil {
+				return nil, fmt.Errorf("invalid MT940 opening balance: %w", err)
+			}
+			statementCurrency = balanceCurrency
+		case "61":
+			flush()
+			transaction, err := mt940Transaction(field.value, userID, statementCurrency, now)
+			if err != nil {
+				return nil, err
+			}
+			pending = &transaction
+		case "86":
+			if pending != nil {
+				mt940ApplyInfo(pending, field.value)
+				flush()
+			}
+		case "62F":
+			flush()
+			date, balance, _, err := mt940ParseBalance(field.value, statementCurrency)
+			if err != nil {
+				return nil, fmt.Errorf("invalid MT940 closing balance: %w", err)
+			}
+			statement.Checkpoints = append(statement.Checkpoints, models.BalanceCheckpoint{
+				Date: date, Balance: balance, Source: models.BalanceSourceStatement, CreatedAt: now,
+			})
+		default:
+			flush()
+		}
+	}
+	flush()
+	return statement, nil
+}
+
+// mt940Fields splits the text into its tagged fields, skipping the SWIFT
+// header and trailer blocks some banks wrap the statement in.
+func mt940Fields(text []byte) ([]mt940Field, error) {
+	var fields []mt940Field
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r ")
+		if line == "" || line == "-" || line == "-}" || strings.HasPrefix(line, "{") {
+			continue
+		}
+		if match := mt940Tag.FindStringSubmatch(line); match != nil {
+			fields = append(fields, mt940Field{tag: match[1], value: line[len(match[0]):]})
+			continue
+		}
+		if len(fields) == 0 {
+			return nil, errors.New("invalid MT940: text before the first field")
+		}
+		fields[len(fields)-1].value += "\n" + line
+	}
+	return fields, scanner.Err()
+}
+
+func mt940Transaction(value, userID, currency string, now time.Time) (models.Transaction, error) {
+	match := mt940StatementLine.FindStringSubmatch(value)
+	if match == nil {
+		return models.Transaction{}, fmt.Errorf("invalid MT940 statement line %q", firstLine(value))
+	}
+	valueDate, err := time.Parse("060102", match[1])
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("invalid MT940 statement line %q: %w", firstLine(value), err)
+	}
+	bookingDate := valueDate
+	if match[2] != "" {
+		if bookingDate, err = mt940EntryDate(valueDate, match[2]); err != nil {
+			return models.Transaction{}, fmt.Errorf("invalid MT940 statement line %q: %w", firstLine(value), err)
+		}
+	}
+	amount, err := money.ParseMinorUnitsDecimalComma(strings.TrimSuffix(match[5], ","), currency)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("invalid MT940 statement line %q: %w", firstLine(value), err)
+	}
+	// RC, the reversal of a credit, takes money out; RD puts it back.
+	if match[3] == "D" || match[3] == "RC" {
+		amount = -amount
+	}
+	reference := strings.TrimSpace(match[8])
+	if customerReference := strings.TrimSpace(match[7]); reference == "" && customerReference != "NONREF" {
+		reference = customerReference
+	}
+	return models.Transaction{
+		UserID:              userID,
+		TransactionDateTime: bookingDate,
+		ValueDate:           &valueDate,
+		Description:         strings.Join(strings.Fields(match[9]), " "),
+		Amount:              amount,
+		Currency:            currency,
+		Type:                transactionType(amount),
+		BankReference:       reference,
+		InsertedAt:          now,
+		UpdatedAt:           now,
+	}, nil
+}
+
+// mt940EntryDate reads an MMDD entry date in the year that puts it nearest
+// the value date, which may be across a new year.
+func mt940EntryDate(valueDate time.Time, monthDay string) (time.Time, error) {
+	date, err := time.Parse("0102", monthDay)
+	if err != nil {
+		return time.Time{}, err
+	}
+	year := valueDate.Year()
+	switch months := int(date.Month()) - int(valueDate.Month()); {
+	case months > 6:
+		year--
+	case months < -6:
+		year++
+	}
+	return time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// mt940ParseBalance reads a balance field such as "C250630EUR1234,56",
+// returning its date, signed amount and currency, or fallback when the
+// currency is not one we know.
+func mt940ParseBalance(value, fallback string) (time.Time, int64, string, error) {
+	match := mt940Balance.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return time.Time{}, 0, "", fmt.Errorf("invalid balance %q", value)
+	}
+	date, err := time.Parse("060102", match[2])
+	if err != nil {
+		return time.Time{}, 0, "", fmt.Errorf("invalid balance %q: %w", value, err)
+	}
+	currency := currencyOr(match[3], fallback)
+	amount, err := money.ParseMinorUnitsDecimalComma(strings.TrimSuffix(match[4], ","), currency)
+	if err != nil {
+		return time.Time{}, 0, "", fmt.Errorf("invalid balance %q: %w", value, err)
+	}
+	if match[1] == "D" {
+		amount = -amount
+	}
+	return date, amount, currency, nil
+}
+
+// mt940ApplyInfo sets the transaction's description and remittance from its
+// :86: field, keeping the statement line's supplementary details as the
+// description when the field names no counterparty and has no remittance.
+func mt940ApplyInfo(transaction *models.Transaction, value string) {
+	var counterparty, remittance string
+	joined := strings.ReplaceAll(value, "\n", "")
+	switch {
+	case len(joined) > 3 && joined[3] == '?' && isDigits(joined[:3]):
+		counterparty, remittance = mt940Subfields(joined[3:])
+	case strings.HasPrefix(joined, "/") && mt940InfoKeys[strings.SplitN(joined[1:], "/", 2)[0]]:
+		counterparty, remittance = mt940Keywords(joined)
+	default:
+		remittance = strings.Join(strings.Fields(value), " ")
+	}
+	transaction.Remittance = remittance
+	transaction.Description = firstNonEmpty(counterparty, remittance, transaction.Description)
+}
+
+// mt940Subfields reads a field such as "?00GUTSCHRIFT?20SVWZ+Invoice 1?32ACME
+// GMBH", where ?20 to ?29 and ?60 to ?63 hold the remittance information and
+// ?32 and ?33 the counterparty's name. Any SEPA tags ahead of the remittance
+// text, such as "EREF+", are dropped.
+func mt940Subfields(value string) (string, string) {
+	var name, remittance strings.Builder
+	codes := mt940Subfield.FindAllStringSubmatchIndex(value, -1)
+	for i, code := range codes {
+		end := len(value)
+		if i+1 < len(codes) {
+			end = codes[i+1][0]
+		}
+		text := value[code[1]:end]
+		switch number := value[code[2]:code[3]]; {
+		case number == "32" || number == "33":
+			name.WriteString(text)
+		case (number >= "20" && number <= "29") || (number >= "60" && number <= "63"):
+			remittance.WriteString(text)
+		}
+	}
+	purpose := remittance.String()
+	if _, after, ok := strings.Cut(purpose, "SVWZ+"); ok {
+		purpose = after
+	}
+	return strings.Join(strings.Fields(name.String()), " "), strings.Join(strings.Fields(purpose), " ")
+}
+
+// mt940Keywords reads a field such as "/TRTP/SEPA OVERBOEKING/NAME/ACME
+// BV/REMI/USTD//Invoice 1/". The counterparty is in NAME, or is the third
+// part of CNTP, after the account and BIC.
+func mt940Keywords(value string) (string, string) {
+	values := make(map[string][]string)
+	key := ""
+	for _, part := range strings.Split(value, "/") {
+		if mt940InfoKeys[part] {
+			key = part
+			continue
+		}
+		if key != "" {
+			values[key] = append(values[key], part)
+		}
+	}
+	name := strings.Join(trimAll(values["NAME"]), " ")
+	if counterparty := values["CNTP"]; name == "" && len(counterparty) > 2 {
+		name = strings.TrimSpace(counterparty[2])
+	}
+	var remittance []string
+	for _, part := range trimAll(values["REMI"]) {
+		if part != "USTD" && part != "STRD" {
+			remittance = append(remittance, part)
+		}
+	}
+	return name, strings.Join(remittance, "/")
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
+
+func firstLine(value string) string {
+	line, _, _ := strings.Cut(value, "\n")
+	return line
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleMT940 = `{1:F01DEUTDEFFAXXX0000000000}{2:O9401200250604DEUTDEFFAXXX00000000002506041200N}{4:
+:20:STARTUMSE
+:25:37040044/0532013000
+:28C:00106/001
+:60F:C250601EUR1000,00
+:61:2506010602DR123,45NDDTNONREF//2025060200001
+:86:105?00SEPA-LASTSCHRIFT?20EREF+NOTPROVIDED?21SVWZ+Kundennr 4711 Absc
+?22hlag Juni?32Stadtwerke Musters?33tadt
+:61:250603C1500,10NTRFINV-117
+:86:/TRTP/SEPA OVERBOEKING/IBAN/NL91ABNA0417164300/BIC/ABNANL2A/NAME/A
+CME BV/REMI/USTD//Invoice 117/EREF/INV-117
+:61:2506030603RC9,99NMSC//REV1
+CARD REVERSAL
+:62F:C250603EUR2366,66
+-}`
+
+func TestParseMT940(t *testing.T) {
+	statement, err := Parse(strings.NewReader(sampleMT940), "user", "GBP", BuiltInProfiles(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Transactions) != 3 {
+		t.Fatalf("Transactions = %+v, want 3", statement.Transactions)
+	}
+	june := func(day int) time.Time { return time.Date(2025, time.June, day, 0, 0, 0, 0, time.UTC) }
+	bill, invoice, reversal := statement.Transactions[0], statement.Transactions[1], statement.Transactions[2]
+
+	if bill.Amount != -12345 || bill.Currency != "EUR" || bill.Description != "Stadtwerke Musterstadt" ||
+		bill.Remittance != "Kundennr 4711 Abschlag Juni" || bill.BankReference != "2025060200001" ||
+		!bill.TransactionDateTime.Equal(june(2)) || bill.ValueDate == nil || !bill.ValueDate.Equal(june(1)) || bill.Type != "Debit" {
+		t.Errorf("Transactions[0] = %+v, want the ?32 name booked 2 June, valued 1 June", bill)
+	}
+	if invoice.Amount != 150010 || invoice.Description != "ACME BV" || invoice.Remittance != "Invoice 117" ||
+		invoice.BankReference != "INV-117" || !invoice.TransactionDateTime.Equal(june(3)) {
+		t.Errorf("Transactions[1] = %+v, want the /NAME/ counterparty", invoice)
+	}
+	if reversal.Amount != -999 || reversal.Description != "CARD REVERSAL" || reversal.BankReference != "REV1" {
+		t.Errorf("Transactions[2] = %+v, want a reversed credit taking money out", reversal)
+	}
+	if len(statement.Checkpoints) != 1 || statement.Checkpoints[0].Balance != 236666 || !statement.Checkpoints[0].Date.Equal(june(3)) {
+		t.Errorf("Checkpoints = %+v, want the closing balance", statement.Checkpoints)
+	}
+}
+
+func TestParseMT940EntryDateAcrossYear(t *testing.T) {
+	mt940 := ":20:REF\n:25:ACCOUNT\n:60F:C241231GBP0,\n:61:2501011231D10,NTRFNONREF\n:86:Card payment\n:62F:D250101GBP10,00\n"
+	statement, err := ParseMT940(strings.NewReader(mt940), "user", "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Transactions) != 1 {
+		t.Fatalf("Transactions = %+v, want 1", statement.Transactions)
+	}
+	transaction := statement.Transactions[0]
+	if !transaction.TransactionDateTime.Equal(time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)) ||
+		transaction.Currency != "GBP" || transaction.Amount != -1000 || transaction.BankReference != "" ||
+		transaction.Description != "Card payment" || transaction.Remittance != "Card payment" {
+		t.Errorf("Transactions[0] = %+v, want booked on 31 December 2024 in GBP", transaction)
+	}
+	if statement.Checkpoints[0].Balance != -1000 {
+		t.Errorf("Checkpoints = %+v, want an overdrawn balance", statement.Checkpoints)
+	}
+}
+
+func TestParseMT940InvalidLine(t *testing.T) {
+	mt940 := ":20:REF\n:25:ACCOUNT\n:60F:C250601EUR0,\n:61:25060X\n"
+	if _, err := ParseMT940(strings.NewReader(mt940), "user", "EUR"); err == nil {
+		t.Error("ParseMT940 with an invalid statement line succeeded")
+	}
+}
@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"backend/internal/models"
+	"backend/internal/money"
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// ofxDateLayout is the date part of an OFX datetime such as
+// "20250602120000.000[+1:BST]".
+const ofxDateLayout = "20060102"
+
+// IsOFX reports whether data looks like an OFX or QFX file, either the SGML
+// form of OFX 1.x with its "OFXHEADER:" preamble or the XML form of 2.x.
+func IsOFX(data []byte) bool {
+	head := bytes.ToUpper(bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	return bytes.HasPrefix(head, []byte("OFXHEADER")) || bytes.Contains(head, []byte("<OFX>"))
+}
+
+// ParseOFX reads the bank and credit card statements in an OFX or QFX file,
+// leaving the transactions uncategorised. Each STMTTRN record becomes a
+// transaction with its FITID as the bank reference, which is unique within
+// the account. Amounts are in the
+// statement's CURDEF, or currency when it has none, and each statement's
+// LEDGERBAL is returned as a checkpoint.
+func ParseOFX(r io.Reader, userID, currency string) (*Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := string(decodeText(data))
+	start := strings.Index(strings.ToUpper(text), "<OFX>")
+	if start < 0 {
+		return nil, errors.New("invalid OFX: no <OFX> element")
+	}
+
+	statement := &Statement{Profile: "ofx", Transactions: []models.Transaction{}, UniqueReferences: map[string]bool{}}
+	statementCurrency := currency
+	var record, ledger map[string]string
+	now := time.Now()
+	for _, element := range ofxElements(text[start:]) {
+		switch {
+		case element.name == "STMTRS" || element.name == "CCSTMTRS":
+			statementCurrency = currency
+		case element.name == "CURDEF" && element.value != "":
+			if normalised, err := money.NormaliseCurrency(element.value); err == nil {
+				statementCurrency = normalised
+			}
+		case element.name == "STMTTRN":
+			record = make(map[string]string)
+		case element.name == "/STMTTRN" && record != nil:
+			transaction, err := ofxTransaction(record, userID, statementCurrency, now)
+			if err != nil {
+				return nil, err
+			}
+			statement.Transactions = append(statement.Transactions, transaction)
+			if transaction.BankReference != "" {
+				statement.UniqueReferences[transaction.BankReference] = true
+			}
+			record = nil
+		case element.name == "LEDGERBAL":
+			ledger = make(map[string]string)
+		case element.name == "/LEDGERBAL" && ledger != nil:
+			checkpoint, err := ofxBalance(ledger, statementCurrency, now)
+			if err != nil {
+				return nil, err
+			}
+			statement.Checkpoints = append(statement.Checkpoints, checkpoint)
+			ledger = nil
+		case record != nil && element.value != "":
+			// NAME may also sit inside a PAYEE aggregate; the first value of
+			// each element wins.
+			if _, ok := record[element.name]; !ok {
+				record[element.name] = element.value
+			}
+		case ledger != nil && element.value != "":
+			ledger[element.name] = element.value
+		}
+	}
+	return statement, nil
+}
+
+func ofxTransaction(record map[string]string, userID, currency string, now time.Time) (models.Transaction, error) {
+	date, err := ofxDate(record["DTPOSTED"])
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("invalid OFX transaction %s: %w", record["FITID"], err)
+	}
+	amount, err := ofxAmount(record["TRNAMT"], currency)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("invalid OFX transaction %s: %w", record["FITID"], err)
+	}
+	// Some banks cut NAME short and carry the rest of the description in
+	// MEMO.
+	description := record["NAME"]
+	if description == "" {
+		description = record["MEMO"]
+	}
+	return models.Transaction{
+		UserID:              userID,
+		TransactionDateTime: date,
+		Description:         description,
+		Amount:              amount,
+		Currency:            currency,
+		Type:                transactionType(amount),
+		BankReference:       record["FITID"],
+		InsertedAt:          now,
+		UpdatedAt:           now,
+	}, nil
+}
+
+func ofxBalance(ledger map[string]string, currency string, now time.Time) (models.BalanceCheckpoint, error) {
+	date, err := ofxDate(ledger["DTASOF"])
+	if err != nil {
+		return models.BalanceCheckpoint{}, fmt.Errorf("invalid OFX ledger balance: %w", err)
+	}
+	balance, err := ofxAmount(ledger["BALAMT"], currency)
+	if err != nil {
+		return models.BalanceCheckpoint{}, fmt.Errorf("invalid OFX ledger balance: %w", err)
+	}
+	return models.BalanceCheckpoint{Date: date, Balance: balance, Source: models.BalanceSourceStatement, CreatedAt: now}, nil
+}
+
+// ofxDate reads the date from an OFX datetime, ignoring the time and zone.
+func ofxDate(value string) (time.Time, error) {
+	if len(value) < len(ofxDateLayout) {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return time.Parse(ofxDateLayout, value[:len(ofxDateLayout)])
+}
+
+// ofxAmount parses an OFX amount, which may use a decimal comma.
+func ofxAmount(value, currency string) (int64, error) {
+	if strings.Contains(value, ",") && !strings.Contains(value, ".") {
+		return money.ParseMinorUnitsDecimalComma(value, currency)
+	}
+	return money.ParseMinorUnits(value, currency)
+}
+
+// ofxElement is a start or end tag, with the text that follows a start tag.
+// End tag names start with "/".
+type ofxElement struct {
+	name  string
+	value string
+}
+
+// ofxElements splits OFX into its tags. It reads both SGML, where elements
+// holding a value have no end tag, and XML, since a value is just the text
+// after a start tag either way.
+func ofxElements(text string) []ofxElement {
+	var elements []ofxElement
+	for {
+		open := strings.IndexByte(text, '<')
+		if open < 0 {
+			return elements
+		}
+		end := strings.IndexByte(text[open:], '>')
+		if end < 0 {
+			return elements
+		}
+		tag := strings.TrimSpace(text[open+1 : open+end])
+		text = text[open+end+1:]
+		if strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!") {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, " ")
+		element := ofxElement{name: strings.ToUpper(strings.TrimSuffix(name, "/"))}
+		if !strings.HasPrefix(name, "/") {
+			next := strings.IndexByte(text, '<')
+			if next < 0 {
+				next = len(text)
+			}
+			element.value = html.UnescapeString(strings.TrimSpace(text[:next]))
+		}
+		elements = append(elements, element)
+	}
+}
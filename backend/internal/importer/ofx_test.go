@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sgmlOFX = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20250604120000<LANGUAGE>ENG</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<TRNUID>1
+<STMTRS>
+<CURDEF>GBP
+<BANKACCTFROM><BANKID>200000<ACCTID>12345678<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20250601<DTEND>20250604
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250602000000.000[+1:BST]
+<TRNAMT>-23.45
+<FITID>202506020001
+<NAME>TESCO STORES 3021
+<MEMO>CONTACTLESS
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20250603
+<TRNAMT>1500.10
+<FITID>202506030002
+<PAYEE><NAME>ACME LTD &amp; CO<ADDR1>1 HIGH ST</PAYEE>
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>2476.65<DTASOF>20250604</LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+
+const xmlOFX = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <CREDITCARDMSGSRSV1>
+    <CCSTMTTRNRS>
+      <CCSTMTRS>
+        <CURDEF>USD</CURDEF>
+        <CCACCTFROM><ACCTID>4111111111111111</ACCTID></CCACCTFROM>
+        <BANKTRANLIST>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20250602</DTPOSTED>
+            <TRNAMT>-0.10</TRNAMT>
+            <FITID>A1</FITID>
+            <NAME></NAME>
+            <MEMO>COFFEE</MEMO>
+          </STMTTRN>
+        </BANKTRANLIST>
+        <LEDGERBAL><BALAMT>-100,50</BALAMT><DTASOF>20250603120000</DTASOF></LEDGERBAL>
+      </CCSTMTRS>
+    </CCSTMTTRNRS>
+  </CREDITCARDMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFXSGML(t *testing.T) {
+	statement, err := Parse(strings.NewReader(sgmlOFX), "user", "EUR", BuiltInProfiles(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Transactions) != 2 {
+		t.Fatalf("Transactions = %+v, want 2", statement.Transactions)
+	}
+	tesco, acme := statement.Transactions[0], statement.Transactions[1]
+	if tesco.Description != "TESCO STORES 3021" || tesco.Amount != -2345 || tesco.Currency != "GBP" || tesco.BankReference != "202506020001" ||
+		!tesco.TransactionDateTime.Equal(time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC)) || tesco.Type != "Debit" {
+		t.Errorf("Transactions[0] = %+v", tesco)
+	}
+	if acme.Description != "ACME LTD & CO" || acme.Amount != 150010 || acme.BankReference != "202506030002" || acme.UserID != "user" {
+		t.Errorf("Transactions[1] = %+v, want the payee's name", acme)
+	}
+	if len(statement.UniqueReferences) != 2 || !statement.UniqueReferences["202506020001"] || !statement.UniqueReferences["202506030002"] {
+		t.Errorf("UniqueReferences = %v, want both FITIDs", statement.UniqueReferences)
+	}
+	if len(statement.Checkpoints) != 1 || statement.Checkpoints[0].Balance != 247665 ||
+		!statement.Checkpoints[0].Date.Equal(time.Date(2025, time.June, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Checkpoints = %+v, want the ledger balance", statement.Checkpoints)
+	}
+}
+
+func TestParseOFXXML(t *testing.T) {
+	statement, err := Parse(strings.NewReader(xmlOFX), "user", "GBP", BuiltInProfiles(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Transactions) != 1 {
+		t.Fatalf("Transactions = %+v, want 1", statement.Transactions)
+	}
+	if coffee := statement.Transactions[0]; coffee.Description != "COFFEE" || coffee.Amount != -10 || coffee.Currency != "USD" || coffee.BankReference != "A1" {
+		t.Errorf("Transactions[0] = %+v, want the memo as description in USD", coffee)
+	}
+	if len(statement.Checkpoints) != 1 || statement.Checkpoints[0].Balance != -10050 {
+		t.Errorf("Checkpoints = %+v, want the decimal comma balance", statement.Checkpoints)
+	}
+}
+
+func TestParseOFXInvalidAmount(t *testing.T) {
+	ofx := strings.Replace(sgmlOFX, "<TRNAMT>-23.45", "<TRNAMT>-23.456", 1)
+	if _, err := ParseOFX(strings.NewReader(ofx), "user", "GBP"); err == nil {
+		t.Error("ParseOFX with a sub-penny amount succeeded, want it rejected rather than rounded")
+	}
+}
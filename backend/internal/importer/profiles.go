@@ -0,0 +1,171 @@
+// Package importer reads bank statement exports into transactions.
+package importer
+
+import (
+	"backend/internal/models"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// builtInProfiles are the CSV layouts of the common UK banks' exports.
+var builtInProfiles = []models.ImportProfile{
+	{
+		Name:       "monzo",
+		Columns:    models.ImportColumns{Date: "Date", Description: "Name", Amount: "Amount", Reference: "Transaction ID"},
+		DateFormat: "DD/MM/YYYY",
+	},
+	{
+		Name: "starling",
+		Columns: models.ImportColumns{
+			Date: "Date", Description: "Counter Party", Amount: "Amount (GBP)", Reference: "Reference", Balance: "Balance (GBP)",
+		},
+		DateFormat: "DD/MM/YYYY",
+	},
+	{
+		// Barclays puts the sort code and account number in every row's
+		// Account column, so it is no use as a reference.
+		Name:       "barclays",
+		Columns:    models.ImportColumns{Date: "Date", Description: "Memo", Amount: "Amount", Reference: "Number"},
+		DateFormat: "DD/MM/YYYY",
+	},
+	{
+		Name:       "hsbc",
+		Columns:    models.ImportColumns{Date: "Date", Description: "Description", Amount: "Amount"},
+		DateFormat: "DD/MM/YYYY",
+		Header:     []string{"Date", "Description", "Amount"},
+	},
+	{
+		Name: "nationwide",
+		Columns: models.ImportColumns{
+			Date: "Date", Description: "Description", Debit: "Paid out", Credit: "Paid in", Balance: "Balance",
+		},
+		DateFormat: "DD MMM YYYY",
+	},
+	{
+		Name: "lloyds",
+		Columns: models.ImportColumns{
+			Date: "Transaction Date", Description: "Transaction Description", Debit: "Debit Amount", Credit: "Credit Amount", Balance: "Balance",
+		},
+		DateFormat: "DD/MM/YYYY",
+	},
+	{
+		Name:        "amex",
+		Columns:     models.ImportColumns{Date: "Date", Description: "Description", Amount: "Amount", Reference: "Reference"},
+		DateFormat:  "DD/MM/YYYY",
+		InvertSign:  true,
+		Identifiers: []string{"Extended Details"},
+	},
+}
+
+// BuiltInProfiles returns the built-in profiles.
+func BuiltInProfiles() []models.ImportProfile {
+	profiles := make([]models.ImportProfile, len(builtInProfiles))
+	for i, profile := range builtInProfiles {
+		profile.BuiltIn = true
+		profiles[i] = profile
+	}
+	return profiles
+}
+
+// Merge returns the user's profiles followed by the built-in ones they don't
+// replace, which is the order detection tries them in.
+func Merge(saved []models.ImportProfile) []models.ImportProfile {
+	profiles := append([]models.ImportProfile{}, saved...)
+	replaced := make(map[string]bool, len(saved))
+	for _, profile := range saved {
+		replaced[profile.Name] = true
+	}
+	for _, profile := range BuiltInProfiles() {
+		if !replaced[profile.Name] {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// Find returns the profile called name from profiles, ignoring case.
+func Find(profiles []models.ImportProfile, name string) (models.ImportProfile, bool) {
+	name = ProfileName(name)
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return models.ImportProfile{}, false
+}
+
+// ProfileName is the stored form of a profile name.
+func ProfileName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ValidateProfile checks that profile names its columns and has a usable
+// date format, and lowercases its name.
+func ValidateProfile(profile *models.ImportProfile) error {
+	profile.Name = ProfileName(profile.Name)
+	if profile.Name == "" {
+		return errors.New("name is required")
+	}
+	for _, r := range profile.Name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != ' ' {
+			return fmt.Errorf("name may only contain letters, digits, spaces, hyphens and underscores")
+		}
+	}
+	columns := profile.Columns
+	if columns.Date == "" || columns.Description == "" {
+		return errors.New("date and description columns are required")
+	}
+	if columns.Amount == "" && columns.Debit == "" && columns.Credit == "" {
+		return errors.New("an amount column, or debit and credit columns, is required")
+	}
+	if columns.Amount != "" && (columns.Debit != "" || columns.Credit != "") {
+		return errors.New("use either an amount column or debit and credit columns, not both")
+	}
+	if _, err := dateLayout(profile.DateFormat); err != nil {
+		return err
+	}
+	if len(profile.Header) > 0 {
+		for _, name := range mappedColumns(columns) {
+			if headerIndex(profile.Header, name) < 0 {
+				return fmt.Errorf("column %q is not in the header", name)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateDateFormat checks a date format written the way import profiles
+// write them.
+func ValidateDateFormat(format string) error {
+	_, err := dateLayout(format)
+	return err
+}
+
+// dateLayout turns a format such as "DD/MM/YYYY" into a time layout.
+func dateLayout(format string) (string, error) {
+	if format == "" {
+		return "", errors.New("date format is required")
+	}
+	layout := strings.NewReplacer(
+		"YYYY", "2006", "YY", "06", "MMM", "Jan", "MM", "01", "M", "1", "DD", "02", "D", "2",
+	).Replace(format)
+	if layout == format {
+		return "", fmt.Errorf("date format %q has no DD, MM or YYYY", format)
+	}
+	return layout, nil
+}
+
+// mappedColumns returns the header names profile reads from.
+func mappedColumns(columns models.ImportColumns) []string {
+	var names []string
+	for _, name := range []string{
+		columns.Date, columns.Description, columns.Amount, columns.Debit, columns.Credit, columns.Reference, columns.Balance,
+	} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,333 @@
+package importer
+
+import (
+	"backend/internal/models"
+	"backend/internal/money"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// qifSplitCategory is what some programs put in a split transaction's own
+// category field.
+const qifSplitCategory = "--Split--"
+
+// qifTransactionTypes are the QIF sections read for transactions.
+var qifTransactionTypes = map[string]bool{"bank": true, "ccard": true, "cash": true}
+
+// IsQIF reports whether data looks like a QIF file, which starts with a
+// "!Type:", "!Account" or "!Option" line.
+func IsQIF(data []byte) bool {
+	head := bytes.ToLower(bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	return bytes.HasPrefix(head, []byte("!type:")) || bytes.HasPrefix(head, []byte("!account")) ||
+		bytes.HasPrefix(head, []byte("!option"))
+}
+
+// qifRecord is a transaction as read, before its date is known to be day or
+// month first.
+type qifRecord struct {
+	date        [3]int
+	transaction models.Transaction
+}
+
+// ParseQIF reads the Bank, CCard and Cash transactions in a QIF file; other
+// sections, such as investments, are skipped. Categories written as
+// "Parent:Child" name the child category, and the hierarchy they and any
+// category list describe is returned in Categories. Transfers, written as
+// "[Account]", are left uncategorised. Split lines become the transaction's
+// splits when every line has a category and they add up to its amount.
+//
+// QIF dates carry no order, so they are read day first unless one of them
+// can only be month first.
+func ParseQIF(r io.Reader, userID, currency string) (*Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	statement := &Statement{Profile: "qif", Transactions: []models.Transaction{}}
+	categories := &categoryCollector{}
+	var records []qifRecord
+	var record *qifRecord
+	var split *models.Split
+	section := ""
+	now := time.Now()
+
+	scanner := bufio.NewScanner(bytes.NewReader(decodeText(data)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if line[0] == '!' {
+			header := strings.ToLower(strings.TrimSpace(line))
+			if name, ok := strings.CutPrefix(header, "!type:"); ok {
+				section = strings.TrimSpace(name)
+			} else if header == "!account" {
+				section = "account"
+			}
+			continue
+		}
+		code, value := line[0], strings.TrimSpace(line[1:])
+
+		if section == "cat" {
+			if code == 'N' {
+				categories.add(value)
+			}
+			continue
+		}
+		if !qifTransactionTypes[section] {
+			continue
+		}
+
+		if record == nil {
+			record = &qifRecord{transaction: models.Transaction{
+				UserID: userID, Currency: currency, InsertedAt: now, UpdatedAt: now,
+			}}
+		}
+		transaction := &record.transaction
+		switch code {
+		case 'D':
+			if record.date, err = parseQIFDate(value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+		case 'T', 'U':
+			if transaction.Amount, err = money.ParseMinorUnits(value, currency); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+		case 'P':
+			transaction.Description = value
+		case 'M':
+			if transaction.Description == "" {
+				transaction.Description = value
+			}
+		case 'N':
+			transaction.BankReference = value
+		case 'L':
+			transaction.Category = categories.add(value)
+		case 'S':
+			transaction.Splits = append(transaction.Splits, models.Split{Category: categories.add(value)})
+			split = &transaction.Splits[len(transaction.Splits)-1]
+		case 'E':
+			if split != nil {
+				split.Note = value
+			}
+		case '$':
+			if split != nil {
+				if split.Amount, err = money.ParseMinorUnits(value, currency); err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+				}
+			}
+		case '^':
+			if record.date == [3]int{} {
+				return nil, fmt.Errorf("line %d: transaction has no date", lineNumber)
+			}
+			records = append(records, finishQIFRecord(*record))
+			record, split = nil, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// Some programs leave out the last end-of-record line.
+	if record != nil && record.date != [3]int{} {
+		records = append(records, finishQIFRecord(*record))
+	}
+
+	monthFirst, err := qifMonthFirst(records)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		day, month := record.date[0], record.date[1]
+		if monthFirst {
+			day, month = month, day
+		}
+		record.transaction.TransactionDateTime = time.Date(record.date[2], time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		statement.Transactions = append(statement.Transactions, record.transaction)
+	}
+	statement.Categories = categories.categories
+	return statement, nil
+}
+
+// finishQIFRecord sets the transaction's type and keeps its split lines only
+// when they are a valid split, otherwise giving it the first line's
+// category.
+func finishQIFRecord(record qifRecord) qifRecord {
+	transaction := &record.transaction
+	transaction.Type = transactionType(transaction.Amount)
+	if len(transaction.Splits) == 0 {
+		return record
+	}
+	var total int64
+	valid := len(transaction.Splits) >= 2
+	for _, line := range transaction.Splits {
+		total += line.Amount
+		valid = valid && line.Category != "" && line.Amount != 0
+	}
+	if !valid || total != transaction.Amount {
+		if transaction.Category == "" {
+			transaction.Category = transaction.Splits[0].Category
+		}
+		transaction.Splits = nil
+	}
+	return record
+}
+
+// parseQIFDate reads the numbers of a date such as "02/06/2025", "6/ 2/25"
+// or "6/ 2'25", where an apostrophe marks a year from 2000, as day or month,
+// the other, and year. Two-digit years before 70 are taken to be from 2000
+// too.
+func parseQIFDate(value string) ([3]int, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == '\'' || r == ' '
+	})
+	var date [3]int
+	if len(fields) != 3 {
+		return date, fmt.Errorf("invalid date %q", value)
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return date, fmt.Errorf("invalid date %q", value)
+		}
+		date[i] = n
+	}
+	if len(fields[0]) == 4 {
+		// Year first, as in "2025-06-02".
+		date[0], date[2] = date[2], date[0]
+		return date, nil
+	}
+	switch {
+	case len(fields[2]) > 2:
+	case strings.Contains(value, "'") || date[2] < 70:
+		date[2] += 2000
+	default:
+		date[2] += 1900
+	}
+	return date, nil
+}
+
+// qifMonthFirst decides whether dates are month first from the ones that
+// can only be read one way.
+func qifMonthFirst(records []qifRecord) (bool, error) {
+	dayFirst, monthFirst := false, false
+	for _, record := range records {
+		first, second := record.date[0], record.date[1]
+		if first < 1 || second < 1 || (first > 12 && second > 12) || first > 31 || second > 31 {
+			return false, fmt.Errorf("invalid date %d/%d/%d", first, second, record.date[2])
+		}
+		dayFirst = dayFirst || first > 12
+		monthFirst = monthFirst || second > 12
+	}
+	if dayFirst && monthFirst {
+		return false, errors.New("dates are written both day first and month first")
+	}
+	return monthFirst, nil
+}
+
+// categoryCollector gathers the category hierarchy named by QIF category
+// fields, in the order categories first appear.
+type categoryCollector struct {
+	categories []models.PackCategory
+}
+
+// add records a category field such as "Food:Groceries/Holiday" and returns
+// the name of the category it sets, "Groceries". Classes, after the slash,
+// are dropped, and transfers, in square brackets, give "".
+func (c *categoryCollector) add(field string) string {
+	field, _, _ = strings.Cut(field, "/")
+	field = strings.TrimSpace(field)
+	if field == "" || field == qifSplitCategory || strings.HasPrefix(field, "[") {
+		return ""
+	}
+	var names []string
+	for _, name := range strings.Split(field, ":") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	level := &c.categories
+	for _, name := range names {
+		i := 0
+		for i < len(*level) && !strings.EqualFold((*level)[i].Name, name) {
+			i++
+		}
+		if i == len(*level) {
+			*level = append(*level, models.PackCategory{Name: name})
+		}
+		level = &(*level)[i].Subcategories
+	}
+	return names[len(names)-1]
+}
+
+// WriteQIF writes transactions as a QIF file of the given account type.
+// Categories are written as "Parent:Child" using parents, which maps each
+// subcategory's name to its parent's, and split transactions as split lines.
+// Dates use dateFormat, in the form import profiles use.
+func WriteQIF(w io.Writer, accountType string, transactions []models.Transaction, parents map[string]string, dateFormat string) error {
+	layout, err := dateLayout(dateFormat)
+	if err != nil {
+		return err
+	}
+	qifType := "Bank"
+	switch accountType {
+	case models.AccountTypeCreditCard:
+		qifType = "CCard"
+	case models.AccountTypeCash:
+		qifType = "Cash"
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "!Type:%s\n", qifType)
+	for _, transaction := range transactions {
+		fmt.Fprintf(b, "D%s\n", transaction.TransactionDateTime.Format(layout))
+		fmt.Fprintf(b, "T%s\n", money.FormatMinorUnits(transaction.Amount, transaction.Currency))
+		writeQIFField(b, 'P', transaction.Description)
+		writeQIFField(b, 'N', transaction.BankReference)
+		if len(transaction.Splits) > 0 {
+			for _, line := range transaction.Splits {
+				writeQIFField(b, 'S', qifCategory(line.Category, parents))
+				writeQIFField(b, 'E', line.Note)
+				fmt.Fprintf(b, "$%s\n", money.FormatMinorUnits(line.Amount, transaction.Currency))
+			}
+		} else {
+			writeQIFField(b, 'L', qifCategory(transaction.Category, parents))
+		}
+		b.WriteString("^\n")
+	}
+	return b.Flush()
+}
+
+func writeQIFField(b *bufio.Writer, code byte, value string) {
+	// A line break would start a new field.
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return
+	}
+	b.WriteByte(code)
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// qifCategory writes name with its ancestors, as in "Food:Groceries".
+func qifCategory(name string, parents map[string]string) string {
+	if name == "" {
+		return ""
+	}
+	path := name
+	seen := map[string]bool{name: true}
+	for parent, ok := parents[name]; ok && !seen[parent]; parent, ok = parents[parent] {
+		seen[parent] = true
+		path = parent + ":" + path
+	}
+	return path
+}
@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"backend/internal/models"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleQIF = `!Type:Cat
+NFood
+D
+E
+^
+NFood:Groceries
+E
+^
+!Account
+NCurrent Account
+TBank
+^
+!Type:Bank
+D06/ 2'25
+T-23.45
+PTESCO STORES 3021
+N1001
+LFood:Groceries/Holiday
+^
+D06/13/2025
+U-60.00
+T-60.00
+PSAINSBURYS
+L--Split--
+SFood:Groceries
+EWeekly shop
+$-45.00
+SHousehold
+$-15.00
+^
+D06/14/25
+T-500.00
+PTransfer to savings
+L[Savings]
+^
+D06/15/25
+T-9.99
+MNetflix subscription
+SEntertainment
+$-9.99
+^
+!Type:Invst
+D06/16/25
+NBuy
+^
+!Type:CCard
+D06/17/25
+T-5.00
+PCOFFEE
+`
+
+func TestParseQIF(t *testing.T) {
+	statement, err := Parse(strings.NewReader(sampleQIF), "user", "GBP", BuiltInProfiles(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Transactions) != 5 {
+		t.Fatalf("Transactions = %+v, want 5 from the bank and card sections", statement.Transactions)
+	}
+	june := func(day int) time.Time { return time.Date(2025, time.June, day, 0, 0, 0, 0, time.UTC) }
+	tesco, shop, transfer, netflix, coffee := statement.Transactions[0], statement.Transactions[1], statement.Transactions[2], statement.Transactions[3], statement.Transactions[4]
+
+	if !tesco.TransactionDateTime.Equal(june(2)) || tesco.Amount != -2345 || tesco.Description != "TESCO STORES 3021" ||
+		tesco.Category != "Groceries" || tesco.BankReference != "1001" || tesco.UserID != "user" || tesco.Type != "Debit" {
+		t.Errorf("tesco = %+v, want 2 June read month first, in Groceries", tesco)
+	}
+	if !shop.TransactionDateTime.Equal(june(13)) || shop.Category != "" || len(shop.Splits) != 2 ||
+		shop.Splits[0] != (models.Split{Amount: -4500, Category: "Groceries", Note: "Weekly shop"}) ||
+		shop.Splits[1] != (models.Split{Amount: -1500, Category: "Household"}) {
+		t.Errorf("shop = %+v, want its two split lines", shop)
+	}
+	if transfer.Category != "" || transfer.Amount != -50000 {
+		t.Errorf("transfer = %+v, want no category", transfer)
+	}
+	if netflix.Description != "Netflix subscription" || netflix.Category != "Entertainment" || len(netflix.Splits) != 0 {
+		t.Errorf("netflix = %+v, want a single split line taken as its category", netflix)
+	}
+	if !coffee.TransactionDateTime.Equal(june(17)) || coffee.Amount != -500 {
+		t.Errorf("coffee = %+v, want the card transaction without a closing ^", coffee)
+	}
+
+	var names []string
+	var walk func([]models.PackCategory, string)
+	walk = func(categories []models.PackCategory, prefix string) {
+		for _, category := range categories {
+			names = append(names, prefix+category.Name)
+			walk(category.Subcategories, prefix+category.Name+":")
+		}
+	}
+	walk(statement.Categories, "")
+	if got := strings.Join(names, ","); got != "Food,Food:Groceries,Household,Entertainment" {
+		t.Errorf("Categories = %s, want the category list and the categories used", got)
+	}
+}
+
+func TestParseQIFDates(t *testing.T) {
+	qif := "!Type:Bank\nD02/06/2025\nT-1.00\n^\nD25/12/2024\nT-1.00\n^\nD2025-01-31\nT-1.00\n^\n"
+	statement, err := ParseQIF(strings.NewReader(qif), "user", "GBP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []time.Time{
+		time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC),
+	} {
+		if got := statement.Transactions[i].TransactionDateTime; !got.Equal(want) {
+			t.Errorf("Transactions[%d] date = %v, want %v read day first", i, got, want)
+		}
+	}
+
+	mixed := "!Type:Bank\nD13/06/2025\nT-1.00\n^\nD06/13/2025\nT-1.00\n^\n"
+	if _, err := ParseQIF(strings.NewReader(mixed), "user", "GBP"); err == nil {
+		t.Error("ParseQIF with day-first and month-first dates succeeded")
+	}
+}
+
+func TestWriteQIFRoundTrip(t *testing.T) {
+	transactions := []models.Transaction{
+		{TransactionDateTime: time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC), Amount: -2345, Currency: "GBP",
+			Description: "TESCO STORES 3021", Category: "Groceries", BankReference: "1001"},
+		{TransactionDateTime: time.Date(2025, time.June, 13, 0, 0, 0, 0, time.UTC), Amount: -6000, Currency: "GBP",
+			Description: "SAINSBURYS\nLONDON", Category: "Groceries", Splits: []models.Split{
+				{Amount: -4500, Category: "Groceries", Note: "Weekly shop"},
+				{Amount: -1500, Category: "Household"},
+			}},
+	}
+	var b bytes.Buffer
+	if err := WriteQIF(&b, models.AccountTypeCreditCard, transactions, map[string]string{"Groceries": "Food"}, "DD/MM/YYYY"); err != nil {
+		t.Fatal(err)
+	}
+	want := "!Type:CCard\nD02/06/2025\nT-23.45\nPTESCO STORES 3021\nN1001\nLFood:Groceries\n^\n" +
+		"D13/06/2025\nT-60.00\nPSAINSBURYS LONDON\nSFood:Groceries\nEWeekly shop\n$-45.00\nSHousehold\n$-15.00\n^\n"
+	if b.String() != want {
+		t.Fatalf("WriteQIF =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	statement, err := ParseQIF(&b, "user", "GBP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Transactions) != 2 || statement.Transactions[0].Category != "Groceries" || len(statement.Transactions[1].Splits) != 2 ||
+		!statement.Transactions[1].TransactionDateTime.Equal(transactions[1].TransactionDateTime) {
+		t.Errorf("ParseQIF of written QIF = %+v", statement.Transactions)
+	}
+}
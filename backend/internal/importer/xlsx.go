@@ -0,0 +1,410 @@
+package importer
+
+import (
+	"archive/zip"
+	"backend/internal/models"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxXLSXPart is the most that is read of any one part of a workbook, so a
+// small file can't unpack into an enormous one.
+const maxXLSXPart = 64 << 20
+
+var (
+	// zipSignature starts every zip file, which .xlsx workbooks are.
+	zipSignature = []byte("PK\x03\x04")
+	// xlsSignature starts the compound files of Excel 97-2003 workbooks.
+	xlsSignature = []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")
+)
+
+// IsXLSX reports whether data looks like an Excel workbook, which is a zip
+// file holding xl/workbook.xml.
+func IsXLSX(data []byte) bool {
+	if !bytes.HasPrefix(data, zipSignature) {
+		return false
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+	for _, file := range archive.File {
+		if file.Name == "xl/workbook.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseXLSX reads a worksheet of an Excel workbook as ParseCSV reads a CSV
+// file, using whichever of profiles fits it. The sheet is options.Sheet, or
+// the first one. Date cells, and numbers in the date column, are read as
+// Excel's serial dates, and numbers are read to Excel's 15 significant
+// digits so amounts come out as they are shown.
+func ParseXLSX(r io.Reader, userID, currency string, profiles []models.ImportProfile, options Options) (*Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid workbook: %w", err)
+	}
+	workbook, err := openWorkbook(archive)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workbook: %w", err)
+	}
+	records, err := workbook.sheet(options.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	return parseRecords(records, userID, currency, profiles, options, workbook.serialDate)
+}
+
+// workbook holds the parts of an .xlsx file that cells are read with.
+type workbook struct {
+	files         map[string]*zip.File
+	sheets        []xlsxSheet
+	sharedStrings []string
+	// dateStyles marks the cell styles whose number format shows a date.
+	dateStyles []bool
+	date1904   bool
+}
+
+type xlsxSheet struct {
+	name string
+	path string
+}
+
+type xlsxWorkbook struct {
+	Properties struct {
+		Date1904 bool `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		// ID is the r:id attribute, which names the sheet's part in the
+		// workbook's relationships.
+		ID string `xml:"id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is a shared or inline string, either plain or as runs of rich
+// text.
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type xlsxStyles struct {
+	NumberFormats []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellFormats []struct {
+		NumberFormatID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Style  int      `xml:"s,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func openWorkbook(archive *zip.Reader) (*workbook, error) {
+	w := &workbook{files: make(map[string]*zip.File, len(archive.File))}
+	for _, file := range archive.File {
+		w.files[file.Name] = file
+	}
+
+	var book xlsxWorkbook
+	if err := w.decode("xl/workbook.xml", &book); err != nil {
+		return nil, err
+	}
+	var relationships xlsxRelationships
+	if err := w.decode("xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(relationships.Relationships))
+	for _, relationship := range relationships.Relationships {
+		target := relationship.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+		targets[relationship.ID] = target
+	}
+	for _, sheet := range book.Sheets {
+		if target, ok := targets[sheet.ID]; ok {
+			w.sheets = append(w.sheets, xlsxSheet{name: sheet.Name, path: target})
+		}
+	}
+	if len(w.sheets) == 0 {
+		return nil, errors.New("no worksheets")
+	}
+	w.date1904 = book.Properties.Date1904
+
+	// Workbooks with no text or no formatting leave these parts out.
+	if _, ok := w.files["xl/sharedStrings.xml"]; ok {
+		var shared struct {
+			Items []xlsxText `xml:"si"`
+		}
+		if err := w.decode("xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+		for _, item := range shared.Items {
+			w.sharedStrings = append(w.sharedStrings, item.String())
+		}
+	}
+	if _, ok := w.files["xl/styles.xml"]; ok {
+		var styles xlsxStyles
+		if err := w.decode("xl/styles.xml", &styles); err != nil {
+			return nil, err
+		}
+		custom := make(map[int]string, len(styles.NumberFormats))
+		for _, format := range styles.NumberFormats {
+			custom[format.ID] = format.Code
+		}
+		for _, cellFormat := range styles.CellFormats {
+			id := cellFormat.NumberFormatID
+			code, ok := custom[id]
+			w.dateStyles = append(w.dateStyles, (ok && isDateFormat(code)) || (!ok && isBuiltInDateFormat(id)))
+		}
+	}
+	return w, nil
+}
+
+// decode unmarshals the XML part called name.
+func (w *workbook) decode(name string, v any) error {
+	file, ok := w.files[name]
+	if !ok {
+		return fmt.Errorf("missing %s", name)
+	}
+	part, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer part.Close()
+	if err := xml.NewDecoder(io.LimitReader(part, maxXLSXPart)).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// sheet returns the rows of the sheet called name, ignoring case, or of the
+// first sheet when name is "". Rows and cells the sheet leaves out are
+// empty, so rows are numbered as Excel shows them.
+func (w *workbook) sheet(name string) ([][]string, error) {
+	sheet := w.sheets[0]
+	if name != "" {
+		found := false
+		var names []string
+		for _, candidate := range w.sheets {
+			names = append(names, candidate.name)
+			if strings.EqualFold(strings.TrimSpace(candidate.name), strings.TrimSpace(name)) {
+				sheet, found = candidate, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no sheet called %q; the workbook has %s", name, strings.Join(names, ", "))
+		}
+	}
+
+	var worksheet xlsxWorksheet
+	if err := w.decode(sheet.path, &worksheet); err != nil {
+		return nil, fmt.Errorf("invalid workbook: %w", err)
+	}
+	var records [][]string
+	for _, row := range worksheet.Rows {
+		number := row.Number
+		if number == 0 {
+			number = len(records) + 1
+		}
+		if number < len(records)+1 {
+			return nil, fmt.Errorf("invalid workbook: sheet %s has row %d out of order", sheet.name, number)
+		}
+		for len(records) < number {
+			records = append(records, nil)
+		}
+		var record []string
+		for _, cell := range row.Cells {
+			column := len(record)
+			if cell.Ref != "" {
+				var err error
+				if column, err = cellColumn(cell.Ref); err != nil {
+					return nil, fmt.Errorf("invalid workbook: sheet %s: %w", sheet.name, err)
+				}
+			}
+			for len(record) <= column {
+				record = append(record, "")
+			}
+			record[column] = w.cellText(cell.Type, cell.Style, cell.Value, cell.Inline)
+		}
+		records[number-1] = record
+	}
+	return records, nil
+}
+
+// cellText returns a cell's value as text: dates as ISO dates, numbers as
+// decimals and booleans as TRUE or FALSE. Error values such as #N/A are "".
+func (w *workbook) cellText(cellType string, style int, value string, inline xlsxText) string {
+	switch cellType {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || i < 0 || i >= len(w.sharedStrings) {
+			return ""
+		}
+		return w.sharedStrings[i]
+	case "inlineStr":
+		return inline.String()
+	case "str":
+		return value
+	case "b":
+		if strings.TrimSpace(value) == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case "e":
+		return ""
+	case "d":
+		// Strict workbooks can store dates as ISO 8601 text.
+		if len(value) > len(time.DateOnly) {
+			return value[:len(time.DateOnly)]
+		}
+		return value
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return value
+	}
+	if style >= 0 && style < len(w.dateStyles) && w.dateStyles[style] {
+		if date, ok := w.serialDate(number); ok {
+			return date.Format(time.DateOnly)
+		}
+	}
+	return formatCellNumber(number)
+}
+
+// serialDate converts a serial date in the workbook's date system.
+func (w *workbook) serialDate(serial float64) (time.Time, bool) {
+	return serialDate(serial, w.date1904)
+}
+
+// formatCellNumber writes number to Excel's precision of 15 significant
+// digits, so a stored 23.449999999999999 is read as the 23.45 it shows.
+func formatCellNumber(number float64) string {
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(number, 'g', 15, 64), 64)
+	if err != nil {
+		rounded = number
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// serialDate converts an Excel serial date to a date, ignoring any time of
+// day. Serials count days from 1 January 1904 in the 1904 date system and
+// otherwise from 1 January 1900, which Excel wrongly treats as a leap year.
+func serialDate(serial float64, date1904 bool) (time.Time, bool) {
+	// Serials past 31 December 9999 are not dates.
+	if serial < 1 || serial >= 2958466 || math.IsNaN(serial) {
+		return time.Time{}, false
+	}
+	days := int(serial)
+	if date1904 {
+		return time.Date(1904, time.January, 1+days, 0, 0, 0, 0, time.UTC), true
+	}
+	if days < 61 {
+		// Before the 29 February 1900 that never was.
+		days++
+	}
+	return time.Date(1899, time.December, 30+days, 0, 0, 0, 0, time.UTC), true
+}
+
+// cellColumn returns the zero-based column of a cell reference such as
+// "C12".
+func cellColumn(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, r := range strings.ToUpper(ref) {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+		letters++
+	}
+	// Excel's last column is XFD.
+	if letters == 0 || letters > 3 || column > 16384 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return column - 1, nil
+}
+
+// isBuiltInDateFormat reports whether a built-in number format shows a date.
+func isBuiltInDateFormat(id int) bool {
+	return (id >= 14 && id <= 17) || id == 22 || (id >= 27 && id <= 36) || (id >= 50 && id <= 58)
+}
+
+// isDateFormat reports whether a custom number format code such as
+// "dd/mm/yyyy" or "[$-809]d mmm yy" shows a date. Quoted text, escaped
+// characters and bracketed sections such as colours and locales are not
+// part of the date.
+func isDateFormat(code string) bool {
+	// Only the format for positive numbers matters.
+	code, _, _ = strings.Cut(code, ";")
+	inQuotes, inBrackets, escaped := false, false, false
+	for _, r := range code {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes:
+			inQuotes = r != '"'
+		case inBrackets:
+			inBrackets = r != ']'
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = true
+		case r == '[':
+			inBrackets = true
+		case r == 'd' || r == 'D' || r == 'y' || r == 'Y':
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package importer
+
+import (
+	"archive/zip"
+	"backend/internal/models"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildXLSX zips parts into a workbook.
+func buildXLSX(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	archive := zip.NewWriter(&b)
+	for name, content := range parts {
+		part, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// ledgerParts is a workbook with a notes sheet, then a hand-kept ledger with
+// a title above its header. Its first cell style shows dates.
+func ledgerParts(workbookProperties string) map[string]string {
+	return map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			workbookProperties + `<sheets><sheet name="Notes" sheetId="1" r:id="rId1"/><sheet name="Ledger" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>Date</t></si><si><t>Payee</t></si><si><t>Amount</t></si><si><t>TESCO</t></si>` +
+			`<si><r><t>Coffee </t></r><r><rPr><b/></rPr><t>Shop</t></r></si><si><t>04/06/2025</t></si></sst>`,
+		"xl/styles.xml": `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<cellXfs count="2"><xf numFmtId="0"/><xf numFmtId="14"/></cellXfs></styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="inlineStr"><is><t>Kept by hand</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="inlineStr"><is><t>Household ledger</t></is></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>0</v></c><c r="B3" t="s"><v>1</v></c><c r="C3" t="s"><v>2</v></c></row>` +
+			`<row r="4"><c r="A4" s="1"><v>45810</v></c><c r="B4" t="s"><v>3</v></c><c r="C4"><v>-23.449999999999999</v></c></row>` +
+			`<row r="5"><c r="A5"><v>45811</v></c><c r="B5" t="inlineStr"><is><t>Salary</t></is></c><c r="C5"><v>2500</v></c></row>` +
+			`<row r="6"><c r="A6" t="s"><v>5</v></c><c r="B6" t="s"><v>4</v></c><c r="C6" t="str"><v>-3.1</v></c></row>` +
+			`<row r="8"><c r="A8" t="inlineStr"><is><t>Total</t></is></c><c r="C8"><f>SUM(C4:C6)</f><v>2473.45</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+}
+
+var ledgerProfile = models.ImportProfile{
+	Name:       "ledger",
+	Columns:    models.ImportColumns{Date: "Date", Description: "Payee", Amount: "Amount"},
+	DateFormat: "DD/MM/YYYY",
+}
+
+func TestParseXLSX(t *testing.T) {
+	data := buildXLSX(t, ledgerParts(""))
+	if !IsXLSX(data) {
+		t.Fatal("IsXLSX = false, want true")
+	}
+	statement, err := Parse(bytes.NewReader(data), "user", "GBP", []models.ImportProfile{ledgerProfile}, Options{Sheet: "ledger"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.Profile != "ledger" || len(statement.Transactions) != 3 {
+		t.Fatalf("statement = %+v, want 3 transactions read with the ledger profile", statement)
+	}
+	june := func(day int) time.Time { return time.Date(2025, time.June, day, 0, 0, 0, 0, time.UTC) }
+	for i, want := range []struct {
+		date        time.Time
+		description string
+		amount      int64
+	}{
+		{june(2), "TESCO", -2345},
+		{june(3), "Salary", 250000},
+		{june(4), "Coffee Shop", -310},
+	} {
+		got := statement.Transactions[i]
+		if !got.TransactionDateTime.Equal(want.date) || got.Description != want.description || got.Amount != want.amount || got.UserID != "user" {
+			t.Errorf("Transactions[%d] = %+v, want %s %s %d", i, got, want.date.Format(time.DateOnly), want.description, want.amount)
+		}
+	}
+}
+
+func TestParseXLSXOptions(t *testing.T) {
+	data := buildXLSX(t, ledgerParts(""))
+	profiles := []models.ImportProfile{ledgerProfile}
+
+	if _, err := ParseXLSX(bytes.NewReader(data), "user", "GBP", profiles, Options{}); !errors.Is(err, ErrUnrecognised) {
+		t.Errorf("ParseXLSX of the first sheet = %v, want ErrUnrecognised", err)
+	}
+	_, err := ParseXLSX(bytes.NewReader(data), "user", "GBP", profiles, Options{Sheet: "Accounts"})
+	if err == nil || !strings.Contains(err.Error(), "Notes, Ledger") {
+		t.Errorf("ParseXLSX of an unknown sheet = %v, want an error naming the sheets", err)
+	}
+
+	statement, err := ParseXLSX(bytes.NewReader(data), "user", "GBP", profiles, Options{Sheet: "Ledger", HeaderRow: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Transactions) != 3 {
+		t.Errorf("Transactions = %+v, want 3 below the header row", statement.Transactions)
+	}
+	if _, err := ParseXLSX(bytes.NewReader(data), "user", "GBP", profiles, Options{Sheet: "Ledger", HeaderRow: 1}); !errors.Is(err, ErrUnrecognised) {
+		t.Errorf("ParseXLSX with the title as the header row = %v, want ErrUnrecognised", err)
+	}
+	if _, err := ParseXLSX(bytes.NewReader(data), "user", "GBP", profiles, Options{Sheet: "Ledger", HeaderRow: 20}); err == nil {
+		t.Error("ParseXLSX with a header row past the end succeeded")
+	}
+}
+
+func TestParseXLSXDate1904(t *testing.T) {
+	data := buildXLSX(t, ledgerParts(`<workbookPr date1904="1"/>`))
+	statement, err := ParseXLSX(bytes.NewReader(data), "user", "GBP", []models.ImportProfile{ledgerProfile}, Options{Sheet: "Ledger"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The same serials are four years and a day later in the 1904 system.
+	for i, want := range []time.Time{
+		time.Date(2029, time.June, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2029, time.June, 4, 0, 0, 0, 0, time.UTC),
+	} {
+		if got := statement.Transactions[i].TransactionDateTime; !got.Equal(want) {
+			t.Errorf("Transactions[%d] date = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestSerialDate(t *testing.T) {
+	tests := []struct {
+		serial   float64
+		date1904 bool
+		want     string
+	}{
+		{1, false, "1900-01-01"},
+		{59, false, "1900-02-28"},
+		{61, false, "1900-03-01"},
+		{45810.75, false, "2025-06-02"},
+		{1, true, "1904-01-02"},
+		{-1, false, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if date, ok := serialDate(tt.serial, tt.date1904); ok {
+			got = date.Format(time.DateOnly)
+		}
+		if got != tt.want {
+			t.Errorf("serialDate(%v, %v) = %q, want %q", tt.serial, tt.date1904, got, tt.want)
+		}
+	}
+}
+
+func TestIsDateFormat(t *testing.T) {
+	for code, want := range map[string]bool{
+		"dd/mm/yyyy":       true,
+		"[$-809]d mmm yy":  true,
+		"#,##0.00":         false,
+		`"Day "0`:          false,
+		"[Red]0.00;[Blue]": false,
+		`0.00\d`:           false,
+	} {
+		if got := isDateFormat(code); got != want {
+			t.Errorf("isDateFormat(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestParseOldXLS(t *testing.T) {
+	if _, err := Parse(bytes.NewReader(xlsSignature), "user", "GBP", BuiltInProfiles(), Options{}); err == nil || !strings.Contains(err.Error(), ".xlsx") {
+		t.Errorf("Parse of an .xls workbook = %v, want an error asking for .xlsx", err)
+	}
+}
@@ -0,0 +1,218 @@
+// Package merchants turns bank statement descriptions into clean merchant
+// names, so "CARD PAYMENT TO TESCO STORES 3021 ON 02/06" becomes "Tesco".
+package merchants
+
+import (
+	"backend/internal/models"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// processorPrefix matches the card processor in front of a merchant, as in
+// "SQ *THE COFFEE SHOP" or "PAYPAL *NETFLIX", or a leading "www.".
+var processorPrefix = regexp.MustCompile(`(?i)^\s*((sq|sumup|iz|zettle|paypal|pp|sp)\s*\*|www\.)\s*`)
+
+var (
+	dayMonth  = regexp.MustCompile(`^\d{1,2}[/.-]\d{1,2}([/.-]\d{2,4})?$`)
+	dayMonths = regexp.MustCompile(`^\d{1,2}(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\d{0,4}$`)
+)
+
+// prefixes are the phrases banks put in front of the merchant, longest first
+// within each group so "card payment to" is stripped whole.
+var prefixes = [][]string{
+	{"debit", "card", "payment", "to"}, {"card", "payment", "to"}, {"card", "payment"}, {"card", "purchase"},
+	{"contactless", "payment", "to"}, {"contactless", "payment"}, {"contactless"},
+	{"direct", "debit", "payment", "to"}, {"direct", "debit", "to"}, {"direct", "debit"},
+	{"faster", "payments", "to"}, {"faster", "payment", "to"}, {"faster", "payments"}, {"faster", "payment"},
+	{"standing", "order", "to"}, {"standing", "order"}, {"bill", "payment", "to"}, {"bill", "payment"},
+	{"payment", "to"}, {"purchase", "at"}, {"pos"}, {"visa"}, {"vis"}, {"cpt"}, {"dd"}, {"so"}, {"bgc"},
+	{"fpo"}, {"fpi"},
+}
+
+// suffixCodes are transaction type codes and country codes that follow the
+// merchant name.
+var suffixCodes = map[string]bool{
+	"dd": true, "so": true, "bgc": true, "fpo": true, "fpi": true, "cpt": true, "pos": true,
+	"vis": true, "gb": true, "gbr": true, "ref": true, "reference": true,
+}
+
+var months = map[string]bool{
+	"jan": true, "feb": true, "mar": true, "apr": true, "may": true, "jun": true,
+	"jul": true, "aug": true, "sep": true, "oct": true, "nov": true, "dec": true,
+}
+
+// Normaliser maps descriptions to merchant names using one user's aliases
+// and the built-in merchant table.
+type Normaliser struct {
+	aliases []pattern
+}
+
+type pattern struct {
+	words []string
+	name  string
+}
+
+// New builds a Normaliser from the user's aliases. Longer patterns are tried
+// first so "amazon prime" beats "amazon".
+func New(aliases []models.MerchantAlias) *Normaliser {
+	n := &Normaliser{}
+	for _, alias := range aliases {
+		if words := Words(alias.Pattern); len(words) > 0 && alias.Merchant != "" {
+			n.aliases = append(n.aliases, pattern{words: words, name: alias.Merchant})
+		}
+	}
+	sortLongestFirst(n.aliases)
+	return n
+}
+
+func sortLongestFirst(patterns []pattern) {
+	sort.SliceStable(patterns, func(i, j int) bool { return len(patterns[i].words) > len(patterns[j].words) })
+}
+
+// Normalise returns the merchant name for description. A user alias found
+// anywhere in the description wins, then a built-in merchant the description
+// starts with; failing those, the description is cleaned and title-cased. It
+// returns "" when nothing identifiable is left.
+func (n *Normaliser) Normalise(description string) string {
+	fields := stripPrefixes(fields(description))
+	var words []string
+	for _, field := range fields {
+		words = append(words, Words(field)...)
+	}
+	for _, alias := range n.aliases {
+		if indexOf(words, alias.words) >= 0 {
+			return alias.name
+		}
+	}
+	for _, known := range builtIn {
+		if indexOf(words, known.words) == 0 {
+			return known.name
+		}
+	}
+	return titleCase(clean(fields))
+}
+
+// Key reduces an alias pattern to the lowercase words it matches, joined by
+// single spaces.
+func Key(text string) string {
+	return strings.Join(Words(text), " ")
+}
+
+// Words splits text into lowercase words of letters and digits. Apostrophes
+// are dropped so "Sainsbury's" and "SAINSBURYS" give the same word.
+func Words(text string) []string {
+	text = strings.NewReplacer("'", "", "’", "").Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// fields splits a description on whitespace after removing any card
+// processor prefix.
+func fields(description string) []string {
+	description = processorPrefix.ReplaceAllString(description, "")
+	return strings.Fields(strings.ReplaceAll(strings.ToLower(description), "*", " "))
+}
+
+func stripPrefixes(fields []string) []string {
+	for stripped := true; stripped; {
+		stripped = false
+		for _, prefix := range prefixes {
+			if len(fields) > len(prefix) && hasPrefix(fields, prefix) {
+				fields = fields[len(prefix):]
+				stripped = true
+				break
+			}
+		}
+	}
+	return fields
+}
+
+func hasPrefix(fields, prefix []string) bool {
+	for i, word := range prefix {
+		if strings.Trim(fields[i], ":,.") != word {
+			return false
+		}
+	}
+	return true
+}
+
+// clean keeps the fields that make up the merchant name: noise before it is
+// skipped, and the first noise after it ends it, taking any location or
+// reference that follows with it.
+func clean(fields []string) []string {
+	var kept []string
+	for i, field := range fields {
+		noise := isNoise(field)
+		switch {
+		case field == "on" && i+1 < len(fields) && isNoise(fields[i+1]):
+			noise = true
+		case months[strings.Trim(field, ",.")] && i > 0 && isNoise(fields[i-1]):
+			noise = true
+		}
+		if noise {
+			if len(kept) > 0 {
+				break
+			}
+			continue
+		}
+		kept = append(kept, field)
+	}
+	return kept
+}
+
+// isNoise reports whether field is a date, store number, amount, reference
+// code or type code rather than part of a name.
+func isNoise(field string) bool {
+	field = strings.Trim(field, ":,.()")
+	if field == "" || suffixCodes[field] || dayMonth.MatchString(field) || dayMonths.MatchString(field) {
+		return true
+	}
+	hasLetter := false
+	for _, r := range field {
+		if unicode.IsDigit(r) {
+			return true
+		}
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+	return !hasLetter
+}
+
+func titleCase(fields []string) string {
+	var b strings.Builder
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		previous := ' '
+		for _, r := range strings.Trim(field, ":,.") {
+			if unicode.IsLetter(r) && !unicode.IsLetter(previous) && previous != '\'' && previous != '’' {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			previous = r
+		}
+	}
+	return b.String()
+}
+
+// indexOf returns where needle starts in words, or -1.
+func indexOf(words, needle []string) int {
+	for i := 0; i+len(needle) <= len(words); i++ {
+		match := true
+		for j, word := range needle {
+			if words[i+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,63 @@
+package merchants
+
+import (
+	"backend/internal/models"
+	"testing"
+)
+
+func TestNormalise(t *testing.T) {
+	n := New([]models.MerchantAlias{
+		{Pattern: "bobs", Merchant: "Bob's Barbers"},
+		{Pattern: "amazon prime", Merchant: "Prime Video"},
+		{Pattern: "ignored", Merchant: ""},
+	})
+
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"CARD PAYMENT TO TESCO STORES 3021 ON 02/06", "Tesco"},
+		{"Tesco Express", "Tesco"},
+		{"SAINSBURY'S S/MKT", "Sainsbury's"},
+		{"JS ONLINE GROCERY 0800 636262", "Sainsbury's"},
+		{"CONTACTLESS M&S SIMPLY FOOD", "M&S"},
+		{"AMZNMKTPLACE*AB12CD34E", "Amazon"},
+		{"PAYPAL *NETFLIX", "Netflix"},
+		{"www.spotify.com", "Spotify"},
+		{"UBER *EATS HELP.UBER.COM", "Uber Eats"},
+		{"UBER *TRIP", "Uber"},
+		{"DIRECT DEBIT O2 UK DD", "O2"},
+		{"PRET A MANGER 0412", "Pret A Manger"},
+		{"TFL TRAVEL CH", "Transport for London"},
+		{"CARD PAYMENT TO THE BLUE DOOR CAFE 44 ON 12 JUN", "The Blue Door Cafe"},
+		{"SQ *GAIL'S BAKERY", "Gail's Bakery"},
+		{"02JUN CORNER SHOP LONDON", "Corner Shop London"},
+		{"FASTER PAYMENT TO J SMITH REF RENT JUNE", "J Smith"},
+		{"ACME LTD AB12345 GB", "Acme Ltd"},
+		{"BOBS BARBERS 123", "Bob's Barbers"},
+		{"CARD PAYMENT TO CUTS BY BOBS", "Bob's Barbers"},
+		{"AMAZON PRIME*RT4SD", "Prime Video"},
+		{"AMAZON.CO.UK", "Amazon"},
+		{"12/06/2025 3021", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := n.Normalise(tt.description); got != tt.want {
+				t.Errorf("Normalise(%q) = %q, want %q", tt.description, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	for text, want := range map[string]string{
+		"  Bob's   BARBERS ": "bobs barbers",
+		"M&S":                "m s",
+		"***":                "",
+	} {
+		if got := Key(text); got != want {
+			t.Errorf("Key(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
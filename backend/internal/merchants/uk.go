@@ -0,0 +1,91 @@
+package merchants
+
+// ukMerchants lists common UK merchants and the ways their names start on
+// statements. Variants are written as they appear and reduced with Words.
+var ukMerchants = []struct {
+	name     string
+	variants []string
+}{
+	// Supermarkets
+	{"Tesco", []string{"tesco"}},
+	{"Sainsbury's", []string{"sainsburys", "sainsbury", "js online", "sacat sainsburys"}},
+	{"Asda", []string{"asda"}},
+	{"Morrisons", []string{"morrisons", "wm morrison", "wm morrisons"}},
+	{"Waitrose", []string{"waitrose"}},
+	{"Aldi", []string{"aldi"}},
+	{"Lidl", []string{"lidl"}},
+	{"Co-op", []string{"co-op", "coop", "the co operative"}},
+	{"M&S", []string{"m&s", "marks & spencer", "marks and spencer"}},
+	{"Iceland", []string{"iceland"}},
+	{"Ocado", []string{"ocado"}},
+	// Food and drink
+	{"Greggs", []string{"greggs"}},
+	{"Pret A Manger", []string{"pret"}},
+	{"Costa Coffee", []string{"costa"}},
+	{"Starbucks", []string{"starbucks"}},
+	{"Caffè Nero", []string{"caffe nero", "cafe nero"}},
+	{"McDonald's", []string{"mcdonalds"}},
+	{"KFC", []string{"kfc"}},
+	{"Nando's", []string{"nandos"}},
+	{"Wetherspoon", []string{"jd wetherspoon", "wetherspoon", "wetherspoons"}},
+	{"Domino's", []string{"dominos"}},
+	{"Deliveroo", []string{"deliveroo"}},
+	{"Just Eat", []string{"just eat", "justeat"}},
+	{"Uber Eats", []string{"uber eats", "ubereats"}},
+	// Travel
+	{"Uber", []string{"uber"}},
+	{"Transport for London", []string{"tfl", "transport for london"}},
+	{"Trainline", []string{"trainline", "thetrainline"}},
+	{"National Rail", []string{"national rail"}},
+	{"Shell", []string{"shell"}},
+	{"BP", []string{"bp"}},
+	{"Esso", []string{"esso"}},
+	// Shopping
+	{"Amazon", []string{"amazon", "amzn", "amznmktplace"}},
+	{"Argos", []string{"argos"}},
+	{"Boots", []string{"boots"}},
+	{"Superdrug", []string{"superdrug"}},
+	{"Currys", []string{"currys"}},
+	{"John Lewis", []string{"john lewis"}},
+	{"IKEA", []string{"ikea"}},
+	{"B&Q", []string{"b&q", "b and q"}},
+	{"Screwfix", []string{"screwfix"}},
+	{"Wickes", []string{"wickes"}},
+	{"Primark", []string{"primark"}},
+	{"WHSmith", []string{"whsmith", "wh smith"}},
+	{"Waterstones", []string{"waterstones"}},
+	// Subscriptions and bills
+	{"Netflix", []string{"netflix"}},
+	{"Spotify", []string{"spotify"}},
+	{"Disney+", []string{"disney plus", "disneyplus"}},
+	{"Apple", []string{"apple com"}},
+	{"Google", []string{"google"}},
+	{"Sky", []string{"sky"}},
+	{"BT", []string{"bt"}},
+	{"Virgin Media", []string{"virgin media"}},
+	{"Vodafone", []string{"vodafone"}},
+	{"EE", []string{"ee"}},
+	{"O2", []string{"o2", "telefonica"}},
+	{"Three", []string{"three mobile", "h3g", "hutchison 3g"}},
+	{"British Gas", []string{"british gas"}},
+	{"Octopus Energy", []string{"octopus energy"}},
+	{"EDF Energy", []string{"edf"}},
+	{"E.ON", []string{"e on", "eon"}},
+	{"Thames Water", []string{"thames water"}},
+	{"TV Licence", []string{"tv licence", "tv licensing"}},
+	{"PureGym", []string{"puregym", "pure gym"}},
+	{"The Gym Group", []string{"the gym group", "gym group"}},
+}
+
+// builtIn is ukMerchants as patterns, longest first so "uber eats" beats
+// "uber".
+var builtIn = func() []pattern {
+	var patterns []pattern
+	for _, merchant := range ukMerchants {
+		for _, variant := range merchant.variants {
+			patterns = append(patterns, pattern{words: Words(variant), name: merchant.name})
+		}
+	}
+	sortLongestFirst(patterns)
+	return patterns
+}()
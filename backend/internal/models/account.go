@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	AccountTypeCurrent    = "current"
+	AccountTypeSavings    = "savings"
+	AccountTypeCreditCard = "credit_card"
+	AccountTypeCash       = "cash"
+	AccountTypeLoan       = "loan"
+)
+
+// AccountTypes lists the accepted values of Account.Type.
+var AccountTypes = []string{AccountTypeCurrent, AccountTypeSavings, AccountTypeCreditCard, AccountTypeCash, AccountTypeLoan}
+
+// Account is a bank account or card that transactions belong to.
+type Account struct {
+	ID          string `json:"id" firestore:"-"`
+	Name        string `json:"name" firestore:"name"`
+	Institution string `json:"institution,omitempty" firestore:"institution"`
+	Type        string `json:"type" firestore:"type"`
+	Currency    string `json:"currency" firestore:"currency"`
+	// OpeningBalance is the balance, in minor units, before the account's
+	// first transaction.
+	OpeningBalance int64     `json:"openingBalance" firestore:"openingBalance"`
+	CreatedAt      time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+type AccountUpdate struct {
+	Name           *string `json:"name,omitempty" firestore:"name,omitempty"`
+	Institution    *string `json:"institution,omitempty" firestore:"institution,omitempty"`
+	Type           *string `json:"type,omitempty" firestore:"type,omitempty"`
+	Currency       *string `json:"currency,omitempty" firestore:"currency,omitempty"`
+	OpeningBalance *int64  `json:"openingBalance,omitempty" firestore:"openingBalance,omitempty"`
+}
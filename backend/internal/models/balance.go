@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+const (
+	BalanceSourceManual    = "manual"
+	BalanceSourceStatement = "statement"
+)
+
+// BalanceCheckpoint is a balance stated by the bank for the end of Date,
+// either entered by hand or read from an imported statement.
+type BalanceCheckpoint struct {
+	ID        string    `json:"id" firestore:"-"`
+	AccountID string    `json:"accountId" firestore:"-"`
+	Date      time.Time `json:"date" firestore:"date"`
+	Balance   int64     `json:"balance" firestore:"balance"`
+	Source    string    `json:"source" firestore:"source"`
+	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
+}
+
+// BalanceEntry is a transaction with the account balance after it.
+type BalanceEntry struct {
+	TransactionID       string    `json:"transactionId"`
+	TransactionDateTime time.Time `json:"transactionDateTime"`
+	Description         string    `json:"description"`
+	Amount              int64     `json:"amount"`
+	Balance             int64     `json:"balance"`
+}
+
+type AccountBalances struct {
+	AccountID      string `json:"accountId"`
+	Currency       string `json:"currency"`
+	OpeningBalance int64  `json:"openingBalance"`
+	// ClosingBalance is the balance after every transaction in the account,
+	// regardless of the requested range.
+	ClosingBalance int64          `json:"closingBalance"`
+	Entries        []BalanceEntry `json:"entries"`
+	// Excluded counts transactions in a currency other than the account's,
+	// which are left out of the running balance.
+	Excluded int `json:"excluded,omitempty"`
+}
+
+type ReconciliationEntry struct {
+	CheckpointID    string    `json:"checkpointId"`
+	Date            time.Time `json:"date"`
+	Source          string    `json:"source"`
+	StatedBalance   int64     `json:"statedBalance"`
+	ComputedBalance int64     `json:"computedBalance"`
+	// Difference is StatedBalance minus ComputedBalance.
+	Difference int64 `json:"difference"`
+	// Gap is the part of Difference that appeared since the previous
+	// checkpoint, i.e. the total of transactions missing from that period.
+	Gap int64 `json:"gap"`
+	// PeriodStart is the date of the previous checkpoint, nil for the first.
+	PeriodStart *time.Time `json:"periodStart,omitempty"`
+	Flagged     bool       `json:"flagged"`
+}
+
+type ReconciliationReport struct {
+	AccountID      string                `json:"accountId"`
+	Currency       string                `json:"currency"`
+	OpeningBalance int64                 `json:"openingBalance"`
+	Reconciled     bool                  `json:"reconciled"`
+	Checkpoints    []ReconciliationEntry `json:"checkpoints"`
+	Excluded       int                   `json:"excluded,omitempty"`
+}
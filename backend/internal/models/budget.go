@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+const (
+	BudgetPeriodWeekly  = "weekly"
+	BudgetPeriodMonthly = "monthly"
+	BudgetPeriodYearly  = "yearly"
+)
+
+// BudgetPeriods lists the accepted values of Budget.Period.
+var BudgetPeriods = []string{BudgetPeriodWeekly, BudgetPeriodMonthly, BudgetPeriodYearly}
+
+// Budget limits spending in a category to Amount, in minor units of
+// Currency, per calendar period. Weeks start on Monday. With Rollover set,
+// whatever is left over (or overspent) carries into the next period, counting
+// from the period containing StartDate. The category can be given by
+// CategoryID or by name; both are stored.
+type Budget struct {
+	ID         string    `json:"id" firestore:"-"`
+	Category   string    `json:"category" firestore:"category"`
+	CategoryID string    `json:"categoryId,omitempty" firestore:"categoryId,omitempty"`
+	Period     string    `json:"period" firestore:"period"`
+	Amount     int64     `json:"amount" firestore:"amount"`
+	Currency   string    `json:"currency" firestore:"currency"`
+	Rollover   bool      `json:"rollover" firestore:"rollover"`
+	StartDate  time.Time `json:"startDate" firestore:"startDate"`
+	CreatedAt  time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+type BudgetUpdate struct {
+	Category   *string    `json:"category,omitempty" firestore:"category,omitempty"`
+	CategoryID *string    `json:"categoryId,omitempty" firestore:"categoryId,omitempty"`
+	Period     *string    `json:"period,omitempty" firestore:"period,omitempty"`
+	Amount     *int64     `json:"amount,omitempty" firestore:"amount,omitempty"`
+	Currency   *string    `json:"currency,omitempty" firestore:"currency,omitempty"`
+	Rollover   *bool      `json:"rollover,omitempty" firestore:"rollover,omitempty"`
+	StartDate  *time.Time `json:"startDate,omitempty" firestore:"startDate,omitempty"`
+}
+
+// BudgetProgress reports a budget's position in the period containing a date.
+// All amounts are in the budget's currency; spending is positive.
+type BudgetProgress struct {
+	BudgetID    string    `json:"budgetId"`
+	Category    string    `json:"category"`
+	CategoryID  string    `json:"categoryId,omitempty"`
+	Currency    string    `json:"currency"`
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+	Budgeted    int64     `json:"budgeted"`
+	// RolledOver is the amount carried in from earlier periods, negative when
+	// they were overspent. Always zero without rollover.
+	RolledOver int64 `json:"rolledOver"`
+	Available  int64 `json:"available"`
+	Spent      int64 `json:"spent"`
+	Remaining  int64 `json:"remaining"`
+	// Projected is the spending expected by the end of the period if it
+	// continues at the rate seen so far.
+	Projected     int64 `json:"projected"`
+	ProjectedOver bool  `json:"projectedOver"`
+	// Unconverted holds spending that could not be converted for lack of a
+	// rate and is excluded from Spent.
+	Unconverted []CurrencyTotal `json:"unconverted,omitempty"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// CategoryMapping records the category a user last gave a merchant when
+// correcting a transaction, so later transactions from the same merchant get
+// it too. Merchant is the key derived from the transaction's merchant name,
+// or its description for transactions without one. CategoryID identifies
+// the category; Category holds its name.
+type CategoryMapping struct {
+	Merchant   string    `json:"merchant" firestore:"-"`
+	Category   string    `json:"category" firestore:"category"`
+	CategoryID string    `json:"categoryId,omitempty" firestore:"categoryId,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
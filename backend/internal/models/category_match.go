@@ -0,0 +1,34 @@
+package models
+
+// Sources of a transaction's category.
+const (
+	CategorySourceManual  = "manual"
+	CategorySourceLearned = "learned"
+	CategorySourceRule    = "rule"
+	CategorySourceKeyword = "keyword"
+	CategorySourceDefault = "default"
+)
+
+// CategoryMatch explains how a transaction got its category: set by the user,
+// learned from their corrections to the same merchant, set by one of their
+// rules, found by a category keyword, or the default when nothing matched.
+type CategoryMatch struct {
+	Source   string `json:"source" firestore:"source"`
+	Category string `json:"category" firestore:"category"`
+	RuleID   string `json:"ruleId,omitempty" firestore:"ruleId,omitempty"`
+	RuleName string `json:"ruleName,omitempty" firestore:"ruleName,omitempty"`
+	// Keyword is the category keyword found in the description.
+	Keyword string `json:"keyword,omitempty" firestore:"keyword,omitempty"`
+	// Merchant is the merchant key a learned category was recorded against.
+	Merchant string `json:"merchant,omitempty" firestore:"merchant,omitempty"`
+	// Span is the part of the description that matched, when the match was
+	// on the description.
+	Span *TextSpan `json:"span,omitempty" firestore:"span,omitempty"`
+}
+
+// TextSpan is the byte range [Start, End) of a string, and the text in it.
+type TextSpan struct {
+	Start int    `json:"start" firestore:"start"`
+	End   int    `json:"end" firestore:"end"`
+	Text  string `json:"text" firestore:"text"`
+}
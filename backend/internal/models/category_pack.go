@@ -0,0 +1,26 @@
+package models
+
+// CategoryPack is a named set of starter categories, such as the defaults for
+// UK users. New users are seeded from one and existing users can merge one
+// into their categories.
+type CategoryPack struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Categories  []PackCategory `json:"categories"`
+}
+
+// PackCategory is a category in a pack together with its subcategories.
+type PackCategory struct {
+	Name          string         `json:"name"`
+	Keywords      []string       `json:"keywords,omitempty"`
+	Subcategories []PackCategory `json:"subcategories,omitempty"`
+}
+
+// CategorySeedResult reports what merging a pack into a user's categories
+// changed. Added lists the new categories and Updated the existing ones that
+// gained keywords, by name.
+type CategorySeedResult struct {
+	Pack    string   `json:"pack"`
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+}
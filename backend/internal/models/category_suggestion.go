@@ -0,0 +1,26 @@
+package models
+
+// CategorySuggestion is a group of uncategorised transactions that look like
+// they belong together, with a name and keywords for a category to hold them.
+// Total is their spend in the report's base currency, as a positive amount;
+// transactions with no rate to it are counted but not totalled.
+type CategorySuggestion struct {
+	Name             string   `json:"name"`
+	Keywords         []string `json:"keywords"`
+	Total            int64    `json:"total"`
+	TransactionCount int      `json:"transactionCount"`
+	TransactionIDs   []string `json:"transactionIds"`
+}
+
+// CategorySuggestions lists suggestions biggest spend first.
+type CategorySuggestions struct {
+	BaseCurrency string               `json:"baseCurrency"`
+	Suggestions  []CategorySuggestion `json:"suggestions"`
+}
+
+// AcceptedSuggestion is the category created from a suggestion and the
+// transactions moved into it.
+type AcceptedSuggestion struct {
+	Category      UserCategory       `json:"category"`
+	Recategorised RecategoriseResult `json:"recategorised"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+const (
+	AllocationKindAssign   = "assign"
+	AllocationKindMove     = "move"
+	AllocationKindAutoFund = "autofund"
+)
+
+// Allocation is an entry in a user's envelope ledger. It assigns Amount, in
+// minor units of the user's base currency, to a category's envelope for
+// Month (YYYY-MM). Negative amounts take money back out; a move is recorded
+// as a pair of entries. CategoryID identifies the category; Category holds
+// its name.
+type Allocation struct {
+	ID         string    `json:"id" firestore:"-"`
+	Month      string    `json:"month" firestore:"month"`
+	Category   string    `json:"category" firestore:"category"`
+	CategoryID string    `json:"categoryId,omitempty" firestore:"categoryId,omitempty"`
+	Amount     int64     `json:"amount" firestore:"amount"`
+	Kind       string    `json:"kind" firestore:"kind"`
+	CreatedAt  time.Time `json:"createdAt" firestore:"createdAt"`
+}
+
+// Envelope is one category's position in a month. Available is what is left
+// to spend: CarriedOver + Assigned + Activity, where Activity is negative for
+// spending. A negative Available is overspending that needs covering from
+// another envelope.
+type Envelope struct {
+	Category    string `json:"category"`
+	CarriedOver int64  `json:"carriedOver"`
+	Assigned    int64  `json:"assigned"`
+	Activity    int64  `json:"activity"`
+	Available   int64  `json:"available"`
+}
+
+// EnvelopeMonth is the state of a user's envelopes for a month. Everything is
+// counted from the first month with an allocation.
+type EnvelopeMonth struct {
+	Month    string `json:"month"`
+	Currency string `json:"currency"`
+	// Income is the month's Credit transactions, excluding transfers.
+	Income   int64 `json:"income"`
+	Assigned int64 `json:"assigned"`
+	// ReadyToAssign is income not yet given to an envelope, including any
+	// left over from earlier months. Negative when more has been assigned
+	// than has come in.
+	ReadyToAssign int64      `json:"readyToAssign"`
+	Envelopes     []Envelope `json:"envelopes"`
+	// Unconverted holds amounts that could not be converted for lack of a
+	// rate and are excluded from the figures above.
+	Unconverted []CurrencyTotal `json:"unconverted,omitempty"`
+}
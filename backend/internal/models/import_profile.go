@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// ImportProfile describes one layout of bank statement CSV: which columns
+// hold what, how dates are written and which way round amounts are signed.
+// Built-in profiles cover the common UK banks; users can save their own,
+// which replace a built-in profile of the same name.
+type ImportProfile struct {
+	// Name identifies the profile. It is stored lowercase.
+	Name    string        `json:"name" firestore:"-"`
+	Columns ImportColumns `json:"columns" firestore:"columns"`
+	// DateFormat is how dates are written, using DD, D, MM, M, MMM, YYYY and
+	// YY, such as "DD/MM/YYYY" or "DD MMM YYYY".
+	DateFormat string `json:"dateFormat" firestore:"dateFormat"`
+	// InvertSign is set for exports that show spending as positive amounts,
+	// such as credit card statements.
+	InvertSign bool `json:"invertSign,omitempty" firestore:"invertSign,omitempty"`
+	// Identifiers are further header names that must be present for the
+	// profile to be detected, to tell it apart from profiles with similar
+	// columns.
+	Identifiers []string `json:"identifiers,omitempty" firestore:"identifiers,omitempty"`
+	// Header names the columns of exports that have no header row. Such
+	// files are recognised by their column count and date format.
+	Header    []string  `json:"header,omitempty" firestore:"header,omitempty"`
+	BuiltIn   bool      `json:"builtIn" firestore:"-"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty" firestore:"updatedAt"`
+}
+
+// ImportColumns names the header of each column an import reads. Either
+// Amount, or one or both of Debit and Credit, must be set; Debit holds money
+// out and Credit money in, each as a positive amount. Reference and Balance
+// are optional.
+type ImportColumns struct {
+	Date        string `json:"date" firestore:"date"`
+	Description string `json:"description" firestore:"description"`
+	Amount      string `json:"amount,omitempty" firestore:"amount,omitempty"`
+	Debit       string `json:"debit,omitempty" firestore:"debit,omitempty"`
+	Credit      string `json:"credit,omitempty" firestore:"credit,omitempty"`
+	Reference   string `json:"reference,omitempty" firestore:"reference,omitempty"`
+	Balance     string `json:"balance,omitempty" firestore:"balance,omitempty"`
+}
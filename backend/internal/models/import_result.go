@@ -0,0 +1,9 @@
+package models
+
+// ImportResult reports an import. Transactions lists those added; Skipped
+// counts those left out because the account already had them from an
+// earlier import.
+type ImportResult struct {
+	Transactions []Transaction `json:"transactions"`
+	Skipped      int           `json:"skipped"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// MerchantAlias names the merchant for descriptions containing Pattern,
+// taking precedence over the built-in merchant table. Pattern is stored as
+// lowercase words separated by single spaces.
+type MerchantAlias struct {
+	Pattern   string    `json:"pattern" firestore:"-"`
+	Merchant  string    `json:"merchant" firestore:"merchant"`
+	UpdatedAt time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// MerchantAliasResult reports how many transactions took a new merchant name
+// after an alias was set or deleted.
+type MerchantAliasResult struct {
+	Alias               *MerchantAlias `json:"alias,omitempty"`
+	TransactionsUpdated int            `json:"transactionsUpdated"`
+}
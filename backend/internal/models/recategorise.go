@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// CategoryChange is a category the categoriser would now give an existing
+// transaction in place of the one it has.
+type CategoryChange struct {
+	TransactionID       string        `json:"transactionId"`
+	Description         string        `json:"description"`
+	TransactionDateTime time.Time     `json:"transactionDateTime"`
+	From                string        `json:"from"`
+	To                  string        `json:"to"`
+	Match               CategoryMatch `json:"match"`
+}
+
+// RecategoriseResult reports a run of the categoriser over existing
+// transactions. Examined counts the transactions that were eligible for a new
+// category; Changes lists those whose category changed, or would have in a
+// dry run.
+type RecategoriseResult struct {
+	DryRun   bool             `json:"dryRun"`
+	Examined int              `json:"examined"`
+	Changes  []CategoryChange `json:"changes"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// CategoryRule sets Category on transactions that meet every one of its
+// Conditions. Rules are tried in ascending Priority, so priority 1 runs
+// first; the first rule that matches wins. The category can be given by
+// CategoryID or by name; both are stored.
+type CategoryRule struct {
+	ID         string         `json:"id" firestore:"-"`
+	Name       string         `json:"name" firestore:"name"`
+	Priority   int            `json:"priority" firestore:"priority"`
+	Category   string         `json:"category" firestore:"category"`
+	CategoryID string         `json:"categoryId,omitempty" firestore:"categoryId,omitempty"`
+	Conditions RuleConditions `json:"conditions" firestore:"conditions"`
+	CreatedAt  time.Time      `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// RuleConditions are the tests a transaction must pass for a rule to match.
+// Unset conditions always pass. Description tests ignore case.
+type RuleConditions struct {
+	DescriptionContains string `json:"descriptionContains,omitempty" firestore:"descriptionContains,omitempty"`
+	DescriptionExact    string `json:"descriptionExact,omitempty" firestore:"descriptionExact,omitempty"`
+	DescriptionRegex    string `json:"descriptionRegex,omitempty" firestore:"descriptionRegex,omitempty"`
+	// MinAmount and MaxAmount bound the size of the amount in minor units,
+	// inclusive, whichever direction the money moved. Use Type to tell
+	// spending from income.
+	MinAmount *int64 `json:"minAmount,omitempty" firestore:"minAmount,omitempty"`
+	MaxAmount *int64 `json:"maxAmount,omitempty" firestore:"maxAmount,omitempty"`
+	// Type is "Debit" or "Credit".
+	Type      string `json:"type,omitempty" firestore:"type,omitempty"`
+	AccountID string `json:"accountId,omitempty" firestore:"accountId,omitempty"`
+	// DateFrom and DateTo bound the transaction date, inclusive of both days.
+	DateFrom *time.Time `json:"dateFrom,omitempty" firestore:"dateFrom,omitempty"`
+	DateTo   *time.Time `json:"dateTo,omitempty" firestore:"dateTo,omitempty"`
+}
+
+type CategoryRuleUpdate struct {
+	Name       *string         `json:"name,omitempty" firestore:"name,omitempty"`
+	Priority   *int            `json:"priority,omitempty" firestore:"priority,omitempty"`
+	Category   *string         `json:"category,omitempty" firestore:"category,omitempty"`
+	CategoryID *string         `json:"categoryId,omitempty" firestore:"categoryId,omitempty"`
+	Conditions *RuleConditions `json:"conditions,omitempty" firestore:"conditions,omitempty"`
+}
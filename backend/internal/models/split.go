@@ -0,0 +1,11 @@
+package models
+
+// Split is one line of a transaction divided across categories, such as the
+// household items in a supermarket shop. Amount is in the transaction's
+// currency.
+type Split struct {
+	Amount     int64  `json:"amount" firestore:"amount"`
+	Category   string `json:"category,omitempty" firestore:"category"`
+	CategoryID string `json:"categoryId,omitempty" firestore:"categoryId,omitempty"`
+	Note       string `json:"note,omitempty" firestore:"note,omitempty"`
+}
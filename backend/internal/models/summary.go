@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// ConvertedAmount is an amount converted into a user's base currency using
+// the rate for the transaction date.
+type ConvertedAmount struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Rate     string `json:"rate"`
+}
+
+// ConvertedTransaction is a transaction alongside its base currency value.
+// Converted is nil when no rate was available.
+type ConvertedTransaction struct {
+	Transaction
+	Converted *ConvertedAmount `json:"converted,omitempty"`
+}
+
+type CurrencyTotal struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+type CategorySummary struct {
+	Category string `json:"category"`
+	// Parent is the name of the category's parent, if it is a subcategory.
+	Parent string `json:"parent,omitempty"`
+	// Total is in the summary's base currency.
+	Total int64 `json:"total"`
+	Count int   `json:"count"`
+	// Original holds the unconverted totals per transaction currency.
+	Original []CurrencyTotal `json:"original"`
+}
+
+type TransactionSummary struct {
+	BaseCurrency string            `json:"baseCurrency"`
+	From         *time.Time        `json:"from,omitempty"`
+	To           *time.Time        `json:"to,omitempty"`
+	Income       int64             `json:"income"`
+	Spending     int64             `json:"spending"`
+	Net          int64             `json:"net"`
+	Categories   []CategorySummary `json:"categories"`
+	// Rollups totals each category together with its subcategories, so
+	// Groceries and Dining both count towards Food. It includes every
+	// category in Categories and every parent of one.
+	Rollups []CategorySummary `json:"rollups,omitempty"`
+	// Unconverted holds totals that could not be converted for lack of a rate
+	// and are excluded from the base currency figures.
+	Unconverted []CurrencyTotal `json:"unconverted,omitempty"`
+}
+
+type MerchantSummary struct {
+	Merchant string `json:"merchant"`
+	// Total is in the report's base currency.
+	Total int64 `json:"total"`
+	Count int   `json:"count"`
+	// Original holds the unconverted totals per transaction currency.
+	Original []CurrencyTotal `json:"original"`
+}
+
+type MerchantReport struct {
+	BaseCurrency string            `json:"baseCurrency"`
+	From         *time.Time        `json:"from,omitempty"`
+	To           *time.Time        `json:"to,omitempty"`
+	Merchants    []MerchantSummary `json:"merchants"`
+	// Unconverted holds totals that could not be converted for lack of a rate
+	// and are excluded from the base currency figures.
+	Unconverted []CurrencyTotal `json:"unconverted,omitempty"`
+}
@@ -4,15 +4,41 @@ import (
 	"time"
 )
 
+// Transaction amounts are held in minor units (e.g. pence) of Currency, an
+// ISO 4217 code.
 type Transaction struct {
 	ID                  string    `json:"id" firestore:"-"`
 	UserID              string    `json:"userId" firestore:"userId"`
+	AccountID           string    `json:"accountId,omitempty" firestore:"accountId,omitempty"`
 	TransactionDateTime time.Time `json:"transactionDateTime" firestore:"transactionDateTime"`
 	Description         string    `json:"description,omitempty" firestore:"description"`
-	Amount              int32     `json:"amount" firestore:"amount"`
-	Category            string    `json:"category,omitempty" firestore:"category"`
-	Type                string    `json:"type" firestore:"type"`
-	BankReference       string    `json:"bankReference,omitempty" firestore:"bankReference,omitempty"`
-	InsertedAt          time.Time `json:"insertedAt" firestore:"insertedAt"`
-	UpdatedAt           time.Time `json:"updatedAt" firestore:"updatedAt"`
+	// Merchant is the clean merchant name derived from Description. It is
+	// set by the server and ignored on input.
+	Merchant string `json:"merchant,omitempty" firestore:"merchant,omitempty"`
+	Amount   int64  `json:"amount" firestore:"amount"`
+	Currency string `json:"currency" firestore:"currency"`
+	Category string `json:"category,omitempty" firestore:"category"`
+	// CategoryID identifies the category; Category holds its name.
+	CategoryID    string `json:"categoryId,omitempty" firestore:"categoryId,omitempty"`
+	Type          string `json:"type" firestore:"type"`
+	BankReference string `json:"bankReference,omitempty" firestore:"bankReference,omitempty"`
+	// ValueDate is when the bank counted the money as moved, for statements
+	// that give it apart from the booking date in TransactionDateTime.
+	ValueDate *time.Time `json:"valueDate,omitempty" firestore:"valueDate,omitempty"`
+	// Remittance is the payment's remittance information, such as an
+	// invoice number, for statements that keep it apart from the
+	// counterparty's name in Description.
+	Remittance string `json:"remittance,omitempty" firestore:"remittance,omitempty"`
+	// CategoryMatch records why the transaction has its category. It is set
+	// by the server and ignored on input.
+	CategoryMatch *CategoryMatch `json:"categoryMatch,omitempty" firestore:"categoryMatch,omitempty"`
+	// Splits divides the transaction across categories. The lines' amounts
+	// sum to Amount, and reports use them in place of Category. They are
+	// managed through the split endpoints and ignored on input elsewhere.
+	Splits []Split `json:"splits,omitempty" firestore:"splits,omitempty"`
+	// TransferID links the two sides of a transfer between the user's own
+	// accounts. Linked transactions are left out of spending and income.
+	TransferID string    `json:"transferId,omitempty" firestore:"transferId,omitempty"`
+	InsertedAt time.Time `json:"insertedAt" firestore:"insertedAt"`
+	UpdatedAt  time.Time `json:"updatedAt" firestore:"updatedAt"`
 }
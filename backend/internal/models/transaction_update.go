@@ -3,9 +3,23 @@ package models
 import "time"
 
 type TransactionUpdate struct {
-	Description *string   `json:"description,omitempty" firestore:"description,omitempty"`
-	Amount      *int32    `json:"amount,omitempty" firestore:"amount,omitempty"`
-	Category    *string   `json:"category,omitempty" firestore:"category,omitempty"`
-	Type        *string   `json:"type,omitempty" firestore:"type,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
+	AccountID   *string `json:"accountId,omitempty" firestore:"accountId,omitempty"`
+	Description *string `json:"description,omitempty" firestore:"description,omitempty"`
+	Amount      *int64  `json:"amount,omitempty" firestore:"amount,omitempty"`
+	Currency    *string `json:"currency,omitempty" firestore:"currency,omitempty"`
+	Category    *string `json:"category,omitempty" firestore:"category,omitempty"`
+	// CategoryID, when given, takes precedence over Category.
+	CategoryID *string `json:"categoryId,omitempty" firestore:"categoryId,omitempty"`
+	// CategoryMatch is set by the server alongside Category.
+	CategoryMatch *CategoryMatch `json:"-" firestore:"categoryMatch,omitempty"`
+	// Merchant is set by the server whenever Description changes.
+	Merchant *string `json:"-" firestore:"merchant,omitempty"`
+	Type     *string `json:"type,omitempty" firestore:"type,omitempty"`
+	// Splits is managed through the split endpoints; an empty slice removes
+	// the splits.
+	Splits *[]Split `json:"-" firestore:"splits,omitempty"`
+	// TransferID is managed through the transfer endpoints; an empty string
+	// unlinks the transaction.
+	TransferID *string   `json:"-" firestore:"transferId,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
 }
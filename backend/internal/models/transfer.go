@@ -0,0 +1,8 @@
+package models
+
+// TransferPair is the outgoing and incoming side of a transfer between two
+// of a user's accounts.
+type TransferPair struct {
+	Debit  Transaction `json:"debit"`
+	Credit Transaction `json:"credit"`
+}
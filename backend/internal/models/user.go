@@ -1,10 +1,19 @@
 package models
 
+import "time"
+
 type User struct {
-	ID        string `json:"id,omitempty" firestore:"-"`
-	Email     string `json:"email" firestore:"email"`
-	FirstName string `json:"firstName" firestore:"firstName"`
-	LastName  string `json:"lastName" firestore:"lastName"`
-	CreatedAt string `json:"createdAt" firestore:"createdAt"`
-	UpdatedAt string `json:"updatedAt" firestore:"updatedAt"`
+	ID           string    `json:"id,omitempty" firestore:"-"`
+	Email        string    `json:"email" firestore:"email"`
+	FirstName    string    `json:"firstName" firestore:"firstName"`
+	LastName     string    `json:"lastName" firestore:"lastName"`
+	BaseCurrency string    `json:"baseCurrency" firestore:"baseCurrency"`
+	CreatedAt    time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+type UserUpdate struct {
+	FirstName    *string `json:"firstName,omitempty" firestore:"firstName,omitempty"`
+	LastName     *string `json:"lastName,omitempty" firestore:"lastName,omitempty"`
+	BaseCurrency *string `json:"baseCurrency,omitempty" firestore:"baseCurrency,omitempty"`
 }
@@ -1,6 +1,17 @@
 package models
 
+// UserCategory is one of a user's spending categories. With ParentID set it
+// is a subcategory, such as Groceries under Food, and rolls up into its
+// parent in summaries.
 type UserCategory struct {
+	ID       string   `json:"id" firestore:"-"`
 	Name     string   `json:"name" firestore:"name"`
+	ParentID string   `json:"parentId,omitempty" firestore:"parentId,omitempty"`
 	Keywords []string `json:"keywords" firestore:"keywords"`
 }
+
+// CategoryChangeResult reports how many transactions were moved by renaming
+// or deleting a category.
+type CategoryChangeResult struct {
+	TransactionsUpdated int `json:"transactionsUpdated"`
+}
@@ -0,0 +1,128 @@
+// Package money parses and validates amounts held as int64 minor units of an
+// ISO 4217 currency, without going through floating point.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultCurrency is assumed for amounts recorded before currencies were stored.
+const DefaultCurrency = "GBP"
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// minorUnitExponents lists currencies that do not use two decimal places.
+var minorUnitExponents = map[string]int{
+	"BHD": 3, "CLP": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+// Exponent returns the number of decimal places used by currency's minor unit.
+func Exponent(currency string) int {
+	if exp, ok := minorUnitExponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+// NormaliseCurrency upper-cases code and checks it has the shape of an ISO
+// 4217 alphabetic code.
+func NormaliseCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency code %q", code)
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q", code)
+		}
+	}
+	return code, nil
+}
+
+// ParseMinorUnits converts a decimal string such as "-1,234.56", "(12.00)" or
+// "£19.99" into minor units of currency. Fractional digits beyond the
+// currency's exponent are only accepted when they are zero, so no value is
+// ever rounded.
+func ParseMinorUnits(amount, currency string) (int64, error) {
+	return parseMinorUnits(amount, currency, '.', ',')
+}
+
+// ParseMinorUnitsDecimalComma is ParseMinorUnits for amounts written with a
+// decimal comma, such as "1.234,56".
+func ParseMinorUnitsDecimalComma(amount, currency string) (int64, error) {
+	return parseMinorUnits(amount, currency, ',', '.')
+}
+
+func parseMinorUnits(amount, currency string, decimalSep, groupSep rune) (int64, error) {
+	s := strings.TrimSpace(amount)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = !negative
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasSuffix(s, "-"):
+		negative = !negative
+		s = s[:len(s)-1]
+	}
+	s = strings.TrimLeft(strings.TrimSpace(s), "£$€¥")
+	if strings.HasPrefix(s, "-") {
+		negative = !negative
+		s = s[1:]
+	}
+
+	whole, fraction, hasFraction := strings.Cut(s, string(decimalSep))
+	whole = strings.ReplaceAll(whole, string(groupSep), "")
+	if whole == "" && (!hasFraction || fraction == "") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	exp := Exponent(currency)
+	trimmed := strings.TrimRight(fraction, "0")
+	if len(trimmed) > exp {
+		return 0, fmt.Errorf("%w: %q has more than %d decimal places for %s", ErrInvalidAmount, amount, exp, currency)
+	}
+	fraction = trimmed + strings.Repeat("0", exp-len(trimmed))
+
+	var value int64
+	for _, c := range whole + fraction {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+		}
+		digit := int64(c - '0')
+		if value > (math.MaxInt64-digit)/10 {
+			return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, amount)
+		}
+		value = value*10 + digit
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// FormatMinorUnits writes amount, in minor units of currency, as a plain
+// decimal such as "-1234.56", the inverse of ParseMinorUnits.
+func FormatMinorUnits(amount int64, currency string) string {
+	sign := ""
+	magnitude := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		magnitude = uint64(-(amount + 1)) + 1
+	}
+	exp := Exponent(currency)
+	digits := fmt.Sprintf("%0*d", exp+1, magnitude)
+	if exp == 0 {
+		return sign + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
@@ -0,0 +1,95 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+	}{
+		{"19.99", "GBP", 1999},
+		{"-19.99", "GBP", -1999},
+		{"0.29", "GBP", 29},
+		{"1.1", "GBP", 110},
+		{"12", "GBP", 1200},
+		{"+12.00", "USD", 1200},
+		{" 1,234.56 ", "GBP", 123456},
+		{"(45.10)", "GBP", -4510},
+		{"45.10-", "GBP", -4510},
+		{"£19.99", "GBP", 1999},
+		{"-£19.99", "GBP", -1999},
+		{"21474837.00", "GBP", 2147483700},
+		{"92233720368547758.07", "GBP", 9223372036854775807},
+		{"1500", "JPY", 1500},
+		{"1.500", "KWD", 1500},
+		{"19.990", "GBP", 1999},
+		{".5", "GBP", 50},
+	}
+	for _, tt := range tests {
+		got, err := ParseMinorUnits(tt.amount, tt.currency)
+		if err != nil {
+			t.Errorf("ParseMinorUnits(%q, %s) returned error: %v", tt.amount, tt.currency, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMinorUnits(%q, %s) = %d, want %d", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestParseMinorUnitsRejectsInvalid(t *testing.T) {
+	for _, amount := range []string{"", "-", ".", "abc", "1.2.3", "19.999", "12.5", "92233720368547758.08"} {
+		currency := "GBP"
+		if amount == "12.5" {
+			currency = "JPY"
+		}
+		if _, err := ParseMinorUnits(amount, currency); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("ParseMinorUnits(%q, %s) error = %v, want ErrInvalidAmount", amount, currency, err)
+		}
+	}
+}
+
+func TestParseMinorUnitsDecimalComma(t *testing.T) {
+	got, err := ParseMinorUnitsDecimalComma("1.234,56", "EUR")
+	if err != nil || got != 123456 {
+		t.Fatalf("ParseMinorUnitsDecimalComma = %d, %v, want 123456", got, err)
+	}
+}
+
+func TestNormaliseCurrency(t *testing.T) {
+	if got, err := NormaliseCurrency(" gbp "); err != nil || got != "GBP" {
+		t.Errorf("NormaliseCurrency(gbp) = %q, %v", got, err)
+	}
+	for _, code := range []string{"", "GB", "GBPX", "G1P"} {
+		if _, err := NormaliseCurrency(code); err == nil {
+			t.Errorf("NormaliseCurrency(%q) should fail", code)
+		}
+	}
+}
+
+func TestFormatMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{-123456, "GBP", "-1234.56"},
+		{5, "GBP", "0.05"},
+		{0, "GBP", "0.00"},
+		{1500, "JPY", "1500"},
+		{-1, "KWD", "-0.001"},
+	}
+	for _, tt := range tests {
+		got := FormatMinorUnits(tt.amount, tt.currency)
+		if got != tt.want {
+			t.Errorf("FormatMinorUnits(%d, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+		if back, err := ParseMinorUnits(got, tt.currency); err != nil || back != tt.amount {
+			t.Errorf("ParseMinorUnits(%q) = %d, %v, want %d", got, back, err, tt.amount)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package reports
+
+import (
+	"backend/internal/models"
+	"sort"
+	"time"
+)
+
+// RunningBalances walks an account's transactions in date order from its
+// opening balance. Entries are limited to [from, to] but the balances always
+// account for every earlier transaction. Transactions in a currency other
+// than the account's are excluded.
+func RunningBalances(account models.Account, transactions []models.Transaction, from, to *time.Time) models.AccountBalances {
+	ordered, excluded := balanceOrder(account, transactions)
+	result := models.AccountBalances{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		OpeningBalance: account.OpeningBalance,
+		Entries:        []models.BalanceEntry{},
+		Excluded:       excluded,
+	}
+
+	balance := account.OpeningBalance
+	for _, transaction := range ordered {
+		balance += transaction.Amount
+		if !InRange(transaction.TransactionDateTime, from, to) {
+			continue
+		}
+		result.Entries = append(result.Entries, models.BalanceEntry{
+			TransactionID:       transaction.ID,
+			TransactionDateTime: transaction.TransactionDateTime,
+			Description:         transaction.Description,
+			Amount:              transaction.Amount,
+			Balance:             balance,
+		})
+	}
+	result.ClosingBalance = balance
+	return result
+}
+
+// Reconcile compares each checkpoint with the balance computed at the end of
+// its day. A checkpoint is flagged when a new difference appeared since the
+// previous one, which points at transactions missing from, or duplicated
+// within, that period.
+func Reconcile(account models.Account, transactions []models.Transaction, checkpoints []models.BalanceCheckpoint) models.ReconciliationReport {
+	ordered, excluded := balanceOrder(account, transactions)
+	report := models.ReconciliationReport{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		OpeningBalance: account.OpeningBalance,
+		Reconciled:     true,
+		Checkpoints:    []models.ReconciliationEntry{},
+		Excluded:       excluded,
+	}
+
+	checkpoints = append([]models.BalanceCheckpoint(nil), checkpoints...)
+	sort.SliceStable(checkpoints, func(i, j int) bool { return checkpoints[i].Date.Before(checkpoints[j].Date) })
+
+	balance := account.OpeningBalance
+	next := 0
+	var previousDifference int64
+	var previousDate *time.Time
+	for _, checkpoint := range checkpoints {
+		endOfDay := truncateToDay(checkpoint.Date).AddDate(0, 0, 1)
+		for next < len(ordered) && ordered[next].TransactionDateTime.Before(endOfDay) {
+			balance += ordered[next].Amount
+			next++
+		}
+
+		difference := checkpoint.Balance - balance
+		entry := models.ReconciliationEntry{
+			CheckpointID:    checkpoint.ID,
+			Date:            checkpoint.Date,
+			Source:          checkpoint.Source,
+			StatedBalance:   checkpoint.Balance,
+			ComputedBalance: balance,
+			Difference:      difference,
+			Gap:             difference - previousDifference,
+			PeriodStart:     previousDate,
+		}
+		entry.Flagged = entry.Gap != 0
+		if difference != 0 {
+			report.Reconciled = false
+		}
+		report.Checkpoints = append(report.Checkpoints, entry)
+
+		previousDifference = difference
+		date := checkpoint.Date
+		previousDate = &date
+	}
+	return report
+}
+
+// balanceOrder returns the account's transactions sorted by date, with ties
+// kept in insertion order, and the number left out for their currency.
+func balanceOrder(account models.Account, transactions []models.Transaction) ([]models.Transaction, int) {
+	ordered := make([]models.Transaction, 0, len(transactions))
+	excluded := 0
+	for _, transaction := range transactions {
+		if transaction.Currency != account.Currency {
+			excluded++
+			continue
+		}
+		ordered = append(ordered, transaction)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if !a.TransactionDateTime.Equal(b.TransactionDateTime) {
+			return a.TransactionDateTime.Before(b.TransactionDateTime)
+		}
+		return a.InsertedAt.Before(b.InsertedAt)
+	})
+	return ordered, excluded
+}
+
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
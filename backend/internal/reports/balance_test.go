@@ -0,0 +1,87 @@
+package reports
+
+import (
+	"backend/internal/models"
+	"testing"
+	"time"
+)
+
+func day(d int) time.Time {
+	return time.Date(2025, time.June, d, 0, 0, 0, 0, time.UTC)
+}
+
+func balanceTransaction(id string, d int, amount int64) models.Transaction {
+	return models.Transaction{ID: id, TransactionDateTime: day(d), Amount: amount, Currency: "GBP"}
+}
+
+func TestRunningBalances(t *testing.T) {
+	account := models.Account{ID: "acc", Currency: "GBP", OpeningBalance: 10000}
+	transactions := []models.Transaction{
+		balanceTransaction("c", 3, -2500),
+		balanceTransaction("a", 1, -1000),
+		{ID: "eur", TransactionDateTime: day(2), Amount: -999, Currency: "EUR"},
+		balanceTransaction("b", 2, 5000),
+	}
+
+	from := day(2)
+	got := RunningBalances(account, transactions, &from, nil)
+	if got.ClosingBalance != 11500 || got.Excluded != 1 {
+		t.Errorf("ClosingBalance = %d, Excluded = %d, want 11500 and 1", got.ClosingBalance, got.Excluded)
+	}
+	want := []struct {
+		id      string
+		balance int64
+	}{{"b", 14000}, {"c", 11500}}
+	if len(got.Entries) != len(want) {
+		t.Fatalf("Entries = %+v", got.Entries)
+	}
+	for i, entry := range got.Entries {
+		if entry.TransactionID != want[i].id || entry.Balance != want[i].balance {
+			t.Errorf("entry %d = %s %d, want %s %d", i, entry.TransactionID, entry.Balance, want[i].id, want[i].balance)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	account := models.Account{ID: "acc", Currency: "GBP", OpeningBalance: 0}
+	transactions := []models.Transaction{
+		balanceTransaction("a", 1, 10000),
+		balanceTransaction("b", 5, -2000),
+		balanceTransaction("c", 20, -3000),
+	}
+	checkpoints := []models.BalanceCheckpoint{
+		// A £5 payment between the 5th and 10th is missing from the data.
+		{ID: "june10", Date: day(10), Balance: 7500},
+		{ID: "june5", Date: day(5), Balance: 8000},
+		{ID: "june30", Date: day(30), Balance: 4500},
+	}
+
+	report := Reconcile(account, transactions, checkpoints)
+	if report.Reconciled {
+		t.Error("Reconciled = true, want false")
+	}
+	want := []struct {
+		id         string
+		computed   int64
+		difference int64
+		gap        int64
+		flagged    bool
+	}{
+		{"june5", 8000, 0, 0, false},
+		{"june10", 8000, -500, -500, true},
+		{"june30", 5000, -500, 0, false},
+	}
+	if len(report.Checkpoints) != len(want) {
+		t.Fatalf("Checkpoints = %+v", report.Checkpoints)
+	}
+	for i, entry := range report.Checkpoints {
+		w := want[i]
+		if entry.CheckpointID != w.id || entry.ComputedBalance != w.computed || entry.Difference != w.difference ||
+			entry.Gap != w.gap || entry.Flagged != w.flagged {
+			t.Errorf("checkpoint %d = %+v, want %+v", i, entry, w)
+		}
+	}
+	if start := report.Checkpoints[1].PeriodStart; start == nil || !start.Equal(day(5)) {
+		t.Errorf("PeriodStart = %v, want %v", start, day(5))
+	}
+}
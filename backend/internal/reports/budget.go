@@ -0,0 +1,119 @@
+package reports
+
+import (
+	"backend/internal/fx"
+	"backend/internal/models"
+	"context"
+	"time"
+)
+
+// PeriodBounds returns the calendar period of the given kind containing on,
+// as a half-open interval [start, end) in UTC. Weeks start on Monday.
+func PeriodBounds(period string, on time.Time) (start, end time.Time) {
+	day := truncateToDay(on)
+	switch period {
+	case models.BudgetPeriodWeekly:
+		offset := (int(day.Weekday()) + 6) % 7
+		start = day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	case models.BudgetPeriodYearly:
+		start = time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0)
+	default:
+		start = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// BudgetProgress measures spending against budget in the period containing
+// on. Spending is the net outflow of the budget's category, so refunds
+// reduce it, and transfers are ignored. Split transactions count only their
+// lines in the budget's category.
+func BudgetProgress(ctx context.Context, rates fx.RateProvider, budget models.Budget, transactions []models.Transaction, on time.Time) (*models.BudgetProgress, error) {
+	transactions = ExpandSplits(transactions)
+	start, end := PeriodBounds(budget.Period, on)
+	spent, unconverted, err := categorySpending(ctx, rates, budget, transactions, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var rolledOver int64
+	if budget.Rollover {
+		periodStart, periodEnd := PeriodBounds(budget.Period, budget.StartDate)
+		for periodStart.Before(start) {
+			earlier, _, err := categorySpending(ctx, rates, budget, transactions, periodStart, periodEnd)
+			if err != nil {
+				return nil, err
+			}
+			rolledOver += budget.Amount - earlier
+			periodStart, periodEnd = PeriodBounds(budget.Period, periodEnd)
+		}
+	}
+
+	progress := &models.BudgetProgress{
+		BudgetID:    budget.ID,
+		Category:    budget.Category,
+		CategoryID:  budget.CategoryID,
+		Currency:    budget.Currency,
+		PeriodStart: start,
+		PeriodEnd:   end.Add(-time.Nanosecond),
+		Budgeted:    budget.Amount,
+		RolledOver:  rolledOver,
+		Available:   budget.Amount + rolledOver,
+		Spent:       spent,
+		Projected:   projectSpending(spent, start, end, on),
+		Unconverted: currencyTotals(unconverted),
+	}
+	progress.Remaining = progress.Available - progress.Spent
+	progress.ProjectedOver = progress.Projected > progress.Available
+	return progress, nil
+}
+
+// categorySpending totals the budget category's net outflow in [start, end)
+// in the budget's currency, along with any amounts that had no rate.
+func categorySpending(ctx context.Context, rates fx.RateProvider, budget models.Budget, transactions []models.Transaction, start, end time.Time) (int64, map[string]int64, error) {
+	var spent int64
+	unconverted := make(map[string]int64)
+	for _, transaction := range transactions {
+		if !inBudgetCategory(transaction, budget) || transaction.TransferID != "" {
+			continue
+		}
+		if transaction.TransactionDateTime.Before(start) || !transaction.TransactionDateTime.Before(end) {
+			continue
+		}
+		converted, err := ConvertTransaction(ctx, rates, budget.Currency, transaction)
+		if err != nil {
+			return 0, nil, err
+		}
+		if converted == nil {
+			unconverted[transaction.Currency] -= transaction.Amount
+			continue
+		}
+		spent -= converted.Amount
+	}
+	return spent, unconverted, nil
+}
+
+// inBudgetCategory reports whether transaction is in budget's category,
+// comparing IDs when both have one and names otherwise.
+func inBudgetCategory(transaction models.Transaction, budget models.Budget) bool {
+	if transaction.CategoryID != "" && budget.CategoryID != "" {
+		return transaction.CategoryID == budget.CategoryID
+	}
+	return transaction.Category == budget.Category
+}
+
+// projectSpending extrapolates spending so far to the end of the period,
+// treating the day of on as elapsed.
+func projectSpending(spent int64, start, end, on time.Time) int64 {
+	day := truncateToDay(on)
+	if !day.Before(end) {
+		return spent
+	}
+	elapsed := int64(day.Sub(start)/(24*time.Hour)) + 1
+	total := int64(end.Sub(start) / (24 * time.Hour))
+	if spent < 0 {
+		return -((-spent*total + elapsed/2) / elapsed)
+	}
+	return (spent*total + elapsed/2) / elapsed
+}
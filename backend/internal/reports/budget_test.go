@@ -0,0 +1,117 @@
+package reports
+
+import (
+	"backend/internal/fx"
+	"backend/internal/models"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	on := time.Date(2025, time.June, 18, 15, 30, 0, 0, time.UTC) // a Wednesday
+	tests := []struct {
+		period     string
+		start, end time.Time
+	}{
+		{models.BudgetPeriodWeekly, time.Date(2025, time.June, 16, 0, 0, 0, 0, time.UTC), time.Date(2025, time.June, 23, 0, 0, 0, 0, time.UTC)},
+		{models.BudgetPeriodMonthly, time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{models.BudgetPeriodYearly, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		start, end := PeriodBounds(tt.period, on)
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("PeriodBounds(%s) = %v, %v, want %v, %v", tt.period, start, end, tt.start, tt.end)
+		}
+	}
+
+	sunday := time.Date(2025, time.June, 22, 0, 0, 0, 0, time.UTC)
+	if start, _ := PeriodBounds(models.BudgetPeriodWeekly, sunday); !start.Equal(time.Date(2025, time.June, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("week containing Sunday starts %v, want the previous Monday", start)
+	}
+}
+
+func spend(category string, date time.Time, amount int64, currency string) models.Transaction {
+	return models.Transaction{Category: category, TransactionDateTime: date, Amount: amount, Currency: currency}
+}
+
+func TestBudgetProgress(t *testing.T) {
+	rates := fx.NewTable()
+	if err := rates.Add(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), "EUR", "GBP", big.NewRat(1, 2)); err != nil {
+		t.Fatal(err)
+	}
+	budget := models.Budget{
+		ID:        "groceries",
+		Category:  "Groceries",
+		Period:    models.BudgetPeriodMonthly,
+		Amount:    30000,
+		Currency:  "GBP",
+		Rollover:  true,
+		StartDate: time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+	}
+	transfer := spend("Groceries", time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC), -99999, "GBP")
+	transfer.TransferID = "other"
+	transactions := []models.Transaction{
+		// Before the budget started: ignored.
+		spend("Groceries", time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC), -50000, "GBP"),
+		// April underspent by 100.00, May overspent by 150.00.
+		spend("Groceries", time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC), -20000, "GBP"),
+		spend("Groceries", time.Date(2025, time.May, 10, 0, 0, 0, 0, time.UTC), -45000, "GBP"),
+		// June so far: 100.00 + 20.00 in euros - 10.00 refund.
+		spend("Groceries", time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC), -10000, "GBP"),
+		spend("Groceries", time.Date(2025, time.June, 5, 0, 0, 0, 0, time.UTC), -4000, "EUR"),
+		spend("Groceries", time.Date(2025, time.June, 6, 0, 0, 0, 0, time.UTC), 1000, "GBP"),
+		spend("Groceries", time.Date(2025, time.June, 7, 0, 0, 0, 0, time.UTC), -700, "USD"),
+		spend("Dining", time.Date(2025, time.June, 4, 0, 0, 0, 0, time.UTC), -8000, "GBP"),
+		transfer,
+	}
+
+	progress, err := BudgetProgress(context.Background(), rates, budget, transactions, time.Date(2025, time.June, 10, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("BudgetProgress: %v", err)
+	}
+	if progress.RolledOver != -5000 || progress.Available != 25000 {
+		t.Errorf("RolledOver = %d, Available = %d, want -5000 and 25000", progress.RolledOver, progress.Available)
+	}
+	if progress.Spent != 11000 || progress.Remaining != 14000 {
+		t.Errorf("Spent = %d, Remaining = %d, want 11000 and 14000", progress.Spent, progress.Remaining)
+	}
+	// 110.00 over 10 of June's 30 days.
+	if progress.Projected != 33000 || !progress.ProjectedOver {
+		t.Errorf("Projected = %d, ProjectedOver = %v, want 33000 and true", progress.Projected, progress.ProjectedOver)
+	}
+	if len(progress.Unconverted) != 1 || progress.Unconverted[0].Currency != "USD" || progress.Unconverted[0].Amount != 700 {
+		t.Errorf("Unconverted = %+v", progress.Unconverted)
+	}
+	if !progress.PeriodStart.Equal(time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PeriodStart = %v", progress.PeriodStart)
+	}
+
+	budget.Rollover = false
+	progress, err = BudgetProgress(context.Background(), rates, budget, transactions, time.Date(2025, time.July, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("BudgetProgress: %v", err)
+	}
+	if progress.RolledOver != 0 || progress.Spent != 0 || progress.Projected != 0 {
+		t.Errorf("July progress = %+v", progress)
+	}
+}
+
+func TestBudgetProgressMatchesCategoryID(t *testing.T) {
+	budget := models.Budget{Category: "Groceries", CategoryID: "groceries", Period: models.BudgetPeriodMonthly, Amount: 30000, Currency: "GBP"}
+	june := time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC)
+	byID := spend("Food shopping", june, -1000, "GBP")
+	byID.CategoryID = "groceries"
+	otherID := spend("Groceries", june, -2000, "GBP")
+	otherID.CategoryID = "household"
+	legacy := spend("Groceries", june, -4000, "GBP")
+
+	progress, err := BudgetProgress(context.Background(), fx.NewTable(), budget, []models.Transaction{byID, otherID, legacy}, june)
+	if err != nil {
+		t.Fatalf("BudgetProgress: %v", err)
+	}
+	if progress.Spent != 5000 || progress.CategoryID != "groceries" {
+		t.Errorf("Spent = %d, CategoryID = %q, want 5000 from the matching ID and the legacy name", progress.Spent, progress.CategoryID)
+	}
+}
@@ -0,0 +1,95 @@
+package reports
+
+import (
+	"backend/internal/fx"
+	"backend/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+// MonthLayout is the format of envelope months, e.g. "2025-06".
+const MonthLayout = "2006-01"
+
+// EnvelopeMonth works out each envelope's position in month from the
+// allocation ledger up to and including that month. Credit transactions are
+// income to be assigned; everything else is activity against its category's
+// envelope, or each split line's. Transactions before the first allocated month are ignored, as
+// are transfers.
+func EnvelopeMonth(ctx context.Context, rates fx.RateProvider, currency, month string, allocations []models.Allocation, transactions []models.Transaction) (*models.EnvelopeMonth, error) {
+	monthStart, err := time.Parse(MonthLayout, month)
+	if err != nil {
+		return nil, err
+	}
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	ledgerStart := monthStart
+	if len(allocations) > 0 && allocations[0].Month < month {
+		if ledgerStart, err = time.Parse(MonthLayout, allocations[0].Month); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &models.EnvelopeMonth{Month: month, Currency: currency, Envelopes: []models.Envelope{}}
+	envelopes := make(map[string]*models.Envelope)
+	envelope := func(category string) *models.Envelope {
+		if category == "" {
+			category = "Other"
+		}
+		e, ok := envelopes[category]
+		if !ok {
+			e = &models.Envelope{Category: category}
+			envelopes[category] = e
+		}
+		return e
+	}
+
+	var assignedBefore int64
+	for _, allocation := range allocations {
+		switch {
+		case allocation.Month < month:
+			envelope(allocation.Category).CarriedOver += allocation.Amount
+			assignedBefore += allocation.Amount
+		case allocation.Month == month:
+			envelope(allocation.Category).Assigned += allocation.Amount
+			result.Assigned += allocation.Amount
+		}
+	}
+
+	var incomeBefore int64
+	unconverted := make(map[string]int64)
+	for _, transaction := range ExpandSplits(transactions) {
+		date := transaction.TransactionDateTime
+		if transaction.TransferID != "" || date.Before(ledgerStart) || !date.Before(monthEnd) {
+			continue
+		}
+		converted, err := ConvertTransaction(ctx, rates, currency, transaction)
+		if err != nil {
+			return nil, err
+		}
+		if converted == nil {
+			unconverted[transaction.Currency] += transaction.Amount
+			continue
+		}
+
+		before := date.Before(monthStart)
+		switch {
+		case transaction.Type == "Credit" && before:
+			incomeBefore += converted.Amount
+		case transaction.Type == "Credit":
+			result.Income += converted.Amount
+		case before:
+			envelope(transaction.Category).CarriedOver += converted.Amount
+		default:
+			envelope(transaction.Category).Activity += converted.Amount
+		}
+	}
+
+	result.ReadyToAssign = incomeBefore + result.Income - assignedBefore - result.Assigned
+	for _, e := range envelopes {
+		e.Available = e.CarriedOver + e.Assigned + e.Activity
+		result.Envelopes = append(result.Envelopes, *e)
+	}
+	sort.Slice(result.Envelopes, func(i, j int) bool { return result.Envelopes[i].Category < result.Envelopes[j].Category })
+	result.Unconverted = currencyTotals(unconverted)
+	return result, nil
+}
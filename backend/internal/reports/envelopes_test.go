@@ -0,0 +1,75 @@
+package reports
+
+import (
+	"backend/internal/fx"
+	"backend/internal/models"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEnvelopeMonth(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	credit := func(d time.Time, amount int64) models.Transaction {
+		return models.Transaction{TransactionDateTime: d, Amount: amount, Currency: "GBP", Type: "Credit", Category: "Income"}
+	}
+	debit := func(d time.Time, category string, amount int64) models.Transaction {
+		return models.Transaction{TransactionDateTime: d, Amount: amount, Currency: "GBP", Type: "Debit", Category: category}
+	}
+	transfer := credit(date(time.June, 3), 99999)
+	transfer.TransferID = "other"
+
+	allocations := []models.Allocation{
+		{Month: "2025-05", Category: "Groceries", Amount: 30000},
+		{Month: "2025-05", Category: "Dining", Amount: 10000},
+		{Month: "2025-06", Category: "Groceries", Amount: 25000},
+		{Month: "2025-06", Category: "Dining", Amount: -3000},
+		{Month: "2025-06", Category: "Rent", Amount: 3000},
+	}
+	transactions := []models.Transaction{
+		// Before envelopes were used.
+		credit(date(time.April, 28), 500000),
+		debit(date(time.April, 29), "Groceries", -1000),
+		credit(date(time.May, 1), 200000),
+		debit(date(time.May, 10), "Groceries", -32000),
+		debit(date(time.May, 12), "Dining", -4000),
+		credit(date(time.June, 1), 100000),
+		debit(date(time.June, 4), "Groceries", -5000),
+		debit(date(time.June, 5), "Rent", -8000),
+		// After the month.
+		debit(date(time.July, 1), "Groceries", -7000),
+		transfer,
+	}
+
+	month, err := EnvelopeMonth(context.Background(), fx.NewTable(), "GBP", "2025-06", allocations, transactions)
+	if err != nil {
+		t.Fatalf("EnvelopeMonth: %v", err)
+	}
+	if month.Income != 100000 || month.Assigned != 25000 {
+		t.Errorf("Income = %d, Assigned = %d, want 100000 and 25000", month.Income, month.Assigned)
+	}
+	// 2000.00 + 1000.00 in, 400.00 assigned in May and 250.00 in June.
+	if month.ReadyToAssign != 235000 {
+		t.Errorf("ReadyToAssign = %d, want 235000", month.ReadyToAssign)
+	}
+
+	want := []models.Envelope{
+		{Category: "Dining", CarriedOver: 6000, Assigned: -3000, Activity: 0, Available: 3000},
+		{Category: "Groceries", CarriedOver: -2000, Assigned: 25000, Activity: -5000, Available: 18000},
+		{Category: "Rent", CarriedOver: 0, Assigned: 3000, Activity: -8000, Available: -5000},
+	}
+	if len(month.Envelopes) != len(want) {
+		t.Fatalf("Envelopes = %+v", month.Envelopes)
+	}
+	for i, envelope := range month.Envelopes {
+		if envelope != want[i] {
+			t.Errorf("envelope %d = %+v, want %+v", i, envelope, want[i])
+		}
+	}
+
+	if _, err := EnvelopeMonth(context.Background(), fx.NewTable(), "GBP", "June", nil, nil); err == nil {
+		t.Error("EnvelopeMonth with an invalid month should fail")
+	}
+}
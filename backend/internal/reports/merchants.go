@@ -0,0 +1,71 @@
+package reports
+
+import (
+	"backend/internal/fx"
+	"backend/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+// UnknownMerchant groups transactions without a merchant name.
+const UnknownMerchant = "Unknown"
+
+// SummariseMerchants totals transactions dated within [from, to] by merchant
+// name in baseCurrency, biggest spend first. A nil bound is open-ended.
+// Transfers between the user's own accounts are left out.
+func SummariseMerchants(ctx context.Context, rates fx.RateProvider, baseCurrency string, transactions []models.Transaction, from, to *time.Time) (*models.MerchantReport, error) {
+	report := &models.MerchantReport{
+		BaseCurrency: baseCurrency,
+		From:         from,
+		To:           to,
+		Merchants:    []models.MerchantSummary{},
+	}
+
+	byMerchant := make(map[string]*models.MerchantSummary)
+	originals := make(map[string]map[string]int64)
+	unconverted := make(map[string]int64)
+
+	for _, transaction := range transactions {
+		if !InRange(transaction.TransactionDateTime, from, to) || transaction.TransferID != "" {
+			continue
+		}
+
+		merchant := transaction.Merchant
+		if merchant == "" {
+			merchant = UnknownMerchant
+		}
+		summary, ok := byMerchant[merchant]
+		if !ok {
+			summary = &models.MerchantSummary{Merchant: merchant}
+			byMerchant[merchant] = summary
+			originals[merchant] = make(map[string]int64)
+		}
+		summary.Count++
+		originals[merchant][transaction.Currency] += transaction.Amount
+
+		converted, err := ConvertTransaction(ctx, rates, baseCurrency, transaction)
+		if err != nil {
+			return nil, err
+		}
+		if converted == nil {
+			unconverted[transaction.Currency] += transaction.Amount
+			continue
+		}
+		summary.Total += converted.Amount
+	}
+
+	for merchant, summary := range byMerchant {
+		summary.Original = currencyTotals(originals[merchant])
+		report.Merchants = append(report.Merchants, *summary)
+	}
+	sort.Slice(report.Merchants, func(i, j int) bool {
+		a, b := report.Merchants[i], report.Merchants[j]
+		if a.Total != b.Total {
+			return a.Total < b.Total
+		}
+		return a.Merchant < b.Merchant
+	})
+	report.Unconverted = currencyTotals(unconverted)
+	return report, nil
+}
@@ -0,0 +1,59 @@
+package reports
+
+import (
+	"backend/internal/fx"
+	"backend/internal/models"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestSummariseMerchants(t *testing.T) {
+	rates := fx.NewTable()
+	if err := rates.Add(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), "EUR", "GBP", big.NewRat(1, 2)); err != nil {
+		t.Fatal(err)
+	}
+	on := time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC)
+	at := func(merchant string, date time.Time, amount int64, currency string) models.Transaction {
+		transaction := spend("Groceries", date, amount, currency)
+		transaction.Merchant = merchant
+		return transaction
+	}
+	transfer := at("", on, -5000, "GBP")
+	transfer.TransferID = "t1"
+	split := at("Tesco", on, -1000, "GBP")
+	split.Splits = []models.Split{{Amount: -600, Category: "Groceries"}, {Amount: -400, Category: "Household"}}
+	transactions := []models.Transaction{
+		at("Tesco", on, -2000, "GBP"),
+		split,
+		at("Pret A Manger", on, -800, "EUR"),
+		at("", on, -300, "GBP"),
+		at("Tesco", on.AddDate(0, 1, 0), -9900, "GBP"),
+		at("Cafe", on, -100, "USD"),
+		transfer,
+	}
+	to := on.AddDate(0, 0, 1)
+
+	report, err := SummariseMerchants(context.Background(), rates, "GBP", transactions, nil, &to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.MerchantSummary{
+		{Merchant: "Tesco", Total: -3000, Count: 2},
+		{Merchant: "Pret A Manger", Total: -400, Count: 1},
+		{Merchant: UnknownMerchant, Total: -300, Count: 1},
+		{Merchant: "Cafe", Total: 0, Count: 1},
+	}
+	if len(report.Merchants) != len(want) {
+		t.Fatalf("Merchants = %+v, want %d entries", report.Merchants, len(want))
+	}
+	for i, got := range report.Merchants {
+		if got.Merchant != want[i].Merchant || got.Total != want[i].Total || got.Count != want[i].Count {
+			t.Errorf("Merchants[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+	if len(report.Unconverted) != 1 || report.Unconverted[0] != (models.CurrencyTotal{Currency: "USD", Amount: -100}) {
+		t.Errorf("Unconverted = %v, want the USD spend", report.Unconverted)
+	}
+}
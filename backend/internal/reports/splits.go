@@ -0,0 +1,33 @@
+package reports
+
+import "backend/internal/models"
+
+// ExpandSplits replaces each split transaction with one transaction per
+// line, carrying the line's amount and category, so that reports count the
+// lines rather than the whole transaction against a single category.
+func ExpandSplits(transactions []models.Transaction) []models.Transaction {
+	lines := 0
+	for _, transaction := range transactions {
+		lines += len(transaction.Splits)
+	}
+	if lines == 0 {
+		return transactions
+	}
+
+	expanded := make([]models.Transaction, 0, len(transactions)+lines)
+	for _, transaction := range transactions {
+		if len(transaction.Splits) == 0 {
+			expanded = append(expanded, transaction)
+			continue
+		}
+		for _, split := range transaction.Splits {
+			line := transaction
+			line.Amount = split.Amount
+			line.Category = split.Category
+			line.CategoryID = split.CategoryID
+			line.Splits = nil
+			expanded = append(expanded, line)
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,49 @@
+package reports
+
+import (
+	"backend/internal/fx"
+	"backend/internal/models"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSplitsInReports(t *testing.T) {
+	on := time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC)
+	shop := spend("Groceries", on, -6000, "GBP")
+	shop.ID = "shop"
+	shop.Splits = []models.Split{
+		{Amount: -4500, Category: "Groceries"},
+		{Amount: -1500, Category: "Household", Note: "cleaning"},
+	}
+	transactions := []models.Transaction{shop, spend("Household", on, -500, "GBP")}
+
+	expanded := ExpandSplits(transactions)
+	if len(expanded) != 3 || expanded[1].ID != "shop" || expanded[1].Category != "Household" || expanded[1].Amount != -1500 || expanded[1].Splits != nil {
+		t.Fatalf("ExpandSplits() = %+v", expanded)
+	}
+	if len(shop.Splits) != 2 || shop.Amount != -6000 {
+		t.Errorf("ExpandSplits modified its input: %+v", shop)
+	}
+
+	summary, err := Summarise(context.Background(), fx.NewTable(), "GBP", transactions, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	totals := map[string]int64{}
+	for _, cat := range summary.Categories {
+		totals[cat.Category] = cat.Total
+	}
+	if len(totals) != 2 || totals["Groceries"] != -4500 || totals["Household"] != -2000 || summary.Spending != 6500 {
+		t.Errorf("summary totals = %v, spending %d", totals, summary.Spending)
+	}
+
+	budget := models.Budget{Category: "Household", Period: models.BudgetPeriodMonthly, Amount: 5000, Currency: "GBP", StartDate: on}
+	progress, err := BudgetProgress(context.Background(), fx.NewTable(), budget, transactions, on)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.Spent != 2000 {
+		t.Errorf("Household budget spent = %d, want 2000", progress.Spent)
+	}
+}
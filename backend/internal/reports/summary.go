@@ -0,0 +1,158 @@
+// Package reports builds aggregated views over a user's transactions.
+package reports
+
+import (
+	"backend/internal/fx"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ConvertTransaction converts a transaction's amount into baseCurrency using
+// the rate on its transaction date. It returns nil when no rate is known.
+func ConvertTransaction(ctx context.Context, rates fx.RateProvider, baseCurrency string, transaction models.Transaction) (*models.ConvertedAmount, error) {
+	amount, rate, err := fx.Convert(ctx, rates, transaction.Amount, transaction.Currency, baseCurrency, transaction.TransactionDateTime)
+	if err != nil {
+		if errors.Is(err, fx.ErrRateNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &models.ConvertedAmount{Amount: amount, Currency: baseCurrency, Rate: fx.FormatRate(rate)}, nil
+}
+
+// ConvertTransactions pairs each transaction with its base currency value.
+func ConvertTransactions(ctx context.Context, rates fx.RateProvider, baseCurrency string, transactions []models.Transaction) ([]models.ConvertedTransaction, error) {
+	converted := make([]models.ConvertedTransaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		amount, err := ConvertTransaction(ctx, rates, baseCurrency, transaction)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, models.ConvertedTransaction{Transaction: transaction, Converted: amount})
+	}
+	return converted, nil
+}
+
+// Summarise totals transactions dated within [from, to] by category in
+// baseCurrency, counting each line of a split transaction in its own
+// category. A nil bound is open-ended. Transfers between the user's own
+// accounts are neither spending nor income and are left out.
+func Summarise(ctx context.Context, rates fx.RateProvider, baseCurrency string, transactions []models.Transaction, from, to *time.Time) (*models.TransactionSummary, error) {
+	summary := &models.TransactionSummary{
+		BaseCurrency: baseCurrency,
+		From:         from,
+		To:           to,
+		Categories:   []models.CategorySummary{},
+	}
+
+	byCategory := make(map[string]*models.CategorySummary)
+	originals := make(map[string]map[string]int64)
+	unconverted := make(map[string]int64)
+
+	for _, transaction := range ExpandSplits(transactions) {
+		if !InRange(transaction.TransactionDateTime, from, to) || transaction.TransferID != "" {
+			continue
+		}
+
+		category := transaction.Category
+		if category == "" {
+			category = "Other"
+		}
+		cat, ok := byCategory[category]
+		if !ok {
+			cat = &models.CategorySummary{Category: category}
+			byCategory[category] = cat
+			originals[category] = make(map[string]int64)
+		}
+		cat.Count++
+		originals[category][transaction.Currency] += transaction.Amount
+
+		converted, err := ConvertTransaction(ctx, rates, baseCurrency, transaction)
+		if err != nil {
+			return nil, err
+		}
+		if converted == nil {
+			unconverted[transaction.Currency] += transaction.Amount
+			continue
+		}
+		cat.Total += converted.Amount
+		if converted.Amount > 0 {
+			summary.Income += converted.Amount
+		} else {
+			summary.Spending += -converted.Amount
+		}
+	}
+	summary.Net = summary.Income - summary.Spending
+
+	for category, cat := range byCategory {
+		cat.Original = currencyTotals(originals[category])
+		summary.Categories = append(summary.Categories, *cat)
+	}
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		return summary.Categories[i].Category < summary.Categories[j].Category
+	})
+	summary.Unconverted = currencyTotals(unconverted)
+	return summary, nil
+}
+
+// InRange reports whether t falls within [from, to]. A nil bound is open-ended.
+func InRange(t time.Time, from, to *time.Time) bool {
+	if from != nil && t.Before(*from) {
+		return false
+	}
+	if to != nil && t.After(*to) {
+		return false
+	}
+	return true
+}
+
+func currencyTotals(totals map[string]int64) []models.CurrencyTotal {
+	if len(totals) == 0 {
+		return nil
+	}
+	result := make([]models.CurrencyTotal, 0, len(totals))
+	for currency, amount := range totals {
+		result = append(result, models.CurrencyTotal{Currency: currency, Amount: amount})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Currency < result[j].Currency })
+	return result
+}
+
+// RollUp fills in summary's category parents and adds rollup totals that
+// include subcategories. parents maps a category's name to its parent's.
+func RollUp(summary *models.TransactionSummary, parents map[string]string) {
+	rollups := make(map[string]*models.CategorySummary)
+	originals := make(map[string]map[string]int64)
+	for i := range summary.Categories {
+		cat := &summary.Categories[i]
+		cat.Parent = parents[cat.Category]
+
+		seen := make(map[string]bool)
+		for name := cat.Category; name != "" && !seen[name]; name = parents[name] {
+			seen[name] = true
+			rollup, ok := rollups[name]
+			if !ok {
+				rollup = &models.CategorySummary{Category: name, Parent: parents[name]}
+				rollups[name] = rollup
+				originals[name] = make(map[string]int64)
+			}
+			rollup.Total += cat.Total
+			rollup.Count += cat.Count
+			for _, original := range cat.Original {
+				originals[name][original.Currency] += original.Amount
+			}
+		}
+	}
+
+	summary.Rollups = make([]models.CategorySummary, 0, len(rollups))
+	for name, rollup := range rollups {
+		rollup.Original = currencyTotals(originals[name])
+		summary.Rollups = append(summary.Rollups, *rollup)
+	}
+	sort.Slice(summary.Rollups, func(i, j int) bool {
+		return summary.Rollups[i].Category < summary.Rollups[j].Category
+	})
+}
@@ -0,0 +1,63 @@
+package reports
+
+import (
+	"backend/internal/fx"
+	"backend/internal/models"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestRollUp(t *testing.T) {
+	rates := fx.NewTable()
+	if err := rates.Add(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), "EUR", "GBP", big.NewRat(1, 2)); err != nil {
+		t.Fatal(err)
+	}
+	on := time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC)
+	transactions := []models.Transaction{
+		spend("Groceries", on, -4000, "GBP"),
+		spend("Dining", on, -2000, "EUR"),
+		spend("Coffee", on, -300, "GBP"),
+		spend("Food", on, -500, "GBP"),
+		spend("Transport", on, -1000, "GBP"),
+	}
+	summary, err := Summarise(context.Background(), rates, "GBP", transactions, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	RollUp(summary, map[string]string{"Groceries": "Food", "Dining": "Food", "Coffee": "Dining"})
+
+	for _, cat := range summary.Categories {
+		if cat.Category == "Coffee" && cat.Parent != "Dining" {
+			t.Errorf("Coffee parent = %q, want Dining", cat.Parent)
+		}
+	}
+	want := map[string]models.CategorySummary{
+		"Coffee":    {Category: "Coffee", Parent: "Dining", Total: -300, Count: 1},
+		"Dining":    {Category: "Dining", Parent: "Food", Total: -1300, Count: 2},
+		"Food":      {Category: "Food", Total: -5800, Count: 4},
+		"Groceries": {Category: "Groceries", Parent: "Food", Total: -4000, Count: 1},
+		"Transport": {Category: "Transport", Total: -1000, Count: 1},
+	}
+	if len(summary.Rollups) != len(want) {
+		t.Fatalf("Rollups = %+v, want %d entries", summary.Rollups, len(want))
+	}
+	for _, got := range summary.Rollups {
+		w := want[got.Category]
+		if got.Parent != w.Parent || got.Total != w.Total || got.Count != w.Count {
+			t.Errorf("rollup %s = %+v, want %+v", got.Category, got, w)
+		}
+	}
+	if food := summary.Rollups[2]; len(food.Original) != 2 || food.Original[0] != (models.CurrencyTotal{Currency: "EUR", Amount: -2000}) {
+		t.Errorf("Food originals = %v, want the EUR and GBP totals", food.Original)
+	}
+}
+
+func TestRollUpIgnoresCycles(t *testing.T) {
+	summary := &models.TransactionSummary{Categories: []models.CategorySummary{{Category: "A", Total: -100, Count: 1}}}
+	RollUp(summary, map[string]string{"A": "B", "B": "A"})
+	if len(summary.Rollups) != 2 || summary.Rollups[0].Total != -100 || summary.Rollups[1].Total != -100 {
+		t.Errorf("Rollups = %+v, want A and B each counted once", summary.Rollups)
+	}
+}
@@ -8,11 +8,30 @@ import (
 	"github.com/joho/godotenv"
 )
 
+const (
+	StorageDriverFirestore = "firestore"
+	StorageDriverMemory    = "memory"
+	StorageDriverPostgres  = "postgres"
+	StorageDriverSQLite    = "sqlite"
+
+	AuthProviderFirebase = "firebase"
+	// AuthProviderHeader trusts the user-id header and is only allowed in development.
+	AuthProviderHeader = "header"
+)
+
 type AppConfig struct {
 	ProjectID            string
 	LocalCredentialsPath string
 	CorsAllowedOrigins   []string
 	Environment          string
+	StorageDriver        string
+	DatabaseURL          string
+	AuthProvider         string
+	FXRatesPath          string
+	// CategoryPack names the category pack new users are seeded from, and
+	// CategoryPacksPath is a directory of extra packs to load.
+	CategoryPack      string
+	CategoryPacksPath string
 }
 
 func LoadConfig() *AppConfig {
@@ -26,12 +45,32 @@ func LoadConfig() *AppConfig {
 		LocalCredentialsPath: getEnv("LOCAL_CREDENTIAL_PATH", ""),
 		CorsAllowedOrigins:   parseCSVEnv("CORS_ALLOWED_ORIGINS", "http://localhost:8080,http://localhost:5173"),
 		Environment:          getEnv("ENVIRONMENT", "development"),
+		StorageDriver:        getEnv("STORAGE_DRIVER", StorageDriverFirestore),
+		DatabaseURL:          getEnv("DATABASE_URL", ""),
+		AuthProvider:         getEnv("AUTH_PROVIDER", AuthProviderFirebase),
+		FXRatesPath:          getEnv("FX_RATES_PATH", ""),
+		CategoryPack:         getEnv("CATEGORY_PACK", "uk"),
+		CategoryPacksPath:    getEnv("CATEGORY_PACKS_PATH", ""),
 	}
 
-	if cfg.ProjectID == "" {
+	if cfg.ProjectID == "" && cfg.StorageDriver == StorageDriverFirestore {
 		log.Fatal("Project ID is not set in the environment variables or .env file")
 	}
 
+	if cfg.DatabaseURL == "" && (cfg.StorageDriver == StorageDriverPostgres || cfg.StorageDriver == StorageDriverSQLite) {
+		log.Fatalf("DATABASE_URL must be set when using the %s storage driver", cfg.StorageDriver)
+	}
+
+	switch cfg.AuthProvider {
+	case AuthProviderFirebase:
+	case AuthProviderHeader:
+		if cfg.Environment != "development" {
+			log.Fatalf("Auth provider %q is only allowed in the development environment", AuthProviderHeader)
+		}
+	default:
+		log.Fatalf("Unsupported auth provider %q", cfg.AuthProvider)
+	}
+
 	return cfg
 }
 
@@ -0,0 +1,95 @@
+// Package transfers pairs up the two sides of money moved between a user's
+// own accounts.
+package transfers
+
+import (
+	"backend/internal/models"
+	"errors"
+	"sort"
+	"time"
+)
+
+// DefaultWindow is how far apart the two sides of a transfer may be dated
+// when no window is given. Faster Payments usually land the same day but
+// card repayments and weekends can take a few.
+const DefaultWindow = 3 * 24 * time.Hour
+
+var (
+	ErrSameTransaction = errors.New("a transfer needs two different transactions")
+	ErrAlreadyLinked   = errors.New("transaction is already part of a transfer")
+	ErrNotOpposite     = errors.New("transfer amounts must be equal and opposite in the same currency")
+	ErrSameAccount     = errors.New("both sides of a transfer are in the same account")
+)
+
+// Validate checks that a and b can be linked as a transfer. Transactions
+// without an account are allowed so that transfers to accounts the user
+// hasn't set up can still be excluded by hand.
+func Validate(a, b models.Transaction) error {
+	if a.ID == b.ID {
+		return ErrSameTransaction
+	}
+	if a.TransferID != "" || b.TransferID != "" {
+		return ErrAlreadyLinked
+	}
+	if a.Amount == 0 || a.Amount != -b.Amount || a.Currency != b.Currency {
+		return ErrNotOpposite
+	}
+	if a.AccountID != "" && a.AccountID == b.AccountID {
+		return ErrSameAccount
+	}
+	return nil
+}
+
+// Detect proposes transfer pairs among transactions that are not yet linked.
+// Both sides must be assigned to different accounts, have equal and opposite
+// amounts in the same currency and be dated no more than window apart.
+// Closer dates are paired first and each transaction is used at most once.
+func Detect(transactions []models.Transaction, window time.Duration) []models.TransferPair {
+	var debits, credits []models.Transaction
+	for _, transaction := range transactions {
+		if transaction.TransferID != "" || transaction.AccountID == "" {
+			continue
+		}
+		switch {
+		case transaction.Amount < 0:
+			debits = append(debits, transaction)
+		case transaction.Amount > 0:
+			credits = append(credits, transaction)
+		}
+	}
+
+	type candidate struct {
+		debit, credit int
+		gap           time.Duration
+	}
+	var candidates []candidate
+	for i, debit := range debits {
+		for j, credit := range credits {
+			if debit.AccountID == credit.AccountID || debit.Currency != credit.Currency || debit.Amount != -credit.Amount {
+				continue
+			}
+			gap := debit.TransactionDateTime.Sub(credit.TransactionDateTime).Abs()
+			if gap > window {
+				continue
+			}
+			candidates = append(candidates, candidate{debit: i, credit: j, gap: gap})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].gap < candidates[j].gap })
+
+	usedDebits := make(map[int]bool)
+	usedCredits := make(map[int]bool)
+	pairs := []models.TransferPair{}
+	for _, c := range candidates {
+		if usedDebits[c.debit] || usedCredits[c.credit] {
+			continue
+		}
+		usedDebits[c.debit] = true
+		usedCredits[c.credit] = true
+		pairs = append(pairs, models.TransferPair{Debit: debits[c.debit], Credit: credits[c.credit]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Debit.TransactionDateTime.Before(pairs[j].Debit.TransactionDateTime)
+	})
+	return pairs
+}
@@ -0,0 +1,77 @@
+package transfers
+
+import (
+	"backend/internal/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func transaction(id, accountID string, day int, amount int64) models.Transaction {
+	return models.Transaction{
+		ID:                  id,
+		AccountID:           accountID,
+		TransactionDateTime: time.Date(2025, time.June, day, 0, 0, 0, 0, time.UTC),
+		Amount:              amount,
+		Currency:            "GBP",
+	}
+}
+
+func TestDetect(t *testing.T) {
+	linked := transaction("linked", "current", 1, -5000)
+	linked.TransferID = "elsewhere"
+	euro := transaction("euro-in", "savings", 2, 5000)
+	euro.Currency = "EUR"
+
+	transactions := []models.Transaction{
+		transaction("to-savings", "current", 2, -5000),
+		transaction("from-current-early", "savings", 4, 5000),
+		transaction("from-current", "savings", 2, 5000),
+		transaction("same-account", "current", 2, 5000),
+		transaction("unassigned", "", 2, 5000),
+		transaction("too-late", "current", 20, -12000),
+		transaction("rent-in", "savings", 10, 12000),
+		linked,
+		euro,
+	}
+
+	pairs := Detect(transactions, DefaultWindow)
+	if len(pairs) != 1 {
+		t.Fatalf("Detect returned %d pairs, want 1: %+v", len(pairs), pairs)
+	}
+	if pairs[0].Debit.ID != "to-savings" || pairs[0].Credit.ID != "from-current" {
+		t.Errorf("Detect paired %s with %s, want to-savings with the same-day from-current", pairs[0].Debit.ID, pairs[0].Credit.ID)
+	}
+
+	if pairs := Detect(transactions, 10*24*time.Hour); len(pairs) != 2 {
+		t.Errorf("Detect with a wider window returned %d pairs, want 2", len(pairs))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	out := transaction("out", "current", 1, -1000)
+	in := transaction("in", "savings", 1, 1000)
+	linked := in
+	linked.TransferID = "other"
+
+	tests := []struct {
+		name string
+		a, b models.Transaction
+		want error
+	}{
+		{"valid", out, in, nil},
+		{"unassigned account", out, transaction("in", "", 5, 1000), nil},
+		{"same transaction", out, out, ErrSameTransaction},
+		{"already linked", out, linked, ErrAlreadyLinked},
+		{"not opposite", out, transaction("in", "savings", 1, 999), ErrNotOpposite},
+		{"same sign", out, transaction("in", "savings", 1, -1000), ErrNotOpposite},
+		{"same account", out, transaction("in", "current", 1, 1000), ErrSameAccount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.a, tt.b); !errors.Is(err, tt.want) {
+				t.Errorf("Validate = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
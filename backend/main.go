@@ -3,16 +3,17 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"time"
 
-	"cloud.google.com/go/firestore"
-
 	"backend/internal/api"
+	"backend/internal/categories"
 	"backend/internal/db"
+	"backend/internal/fx"
 	config "backend/internal/setup"
 )
 
@@ -34,25 +35,27 @@ func main() {
 
 	cfg := config.LoadConfig()
 
-	firestoreClient, err := db.NewFirestoreClient(ctx, cfg.ProjectID, cfg.LocalCredentialsPath, cfg.Environment)
+	repo, closeRepo, err := newRepository(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to create repository: %v", err)
+	}
+
+	rates, err := newRateProvider(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load exchange rates: %v", err)
+	}
+
+	packs, err := newCategoryPacks(cfg)
 	if err != nil {
-		log.Fatalf("Failed to create Firestore client: %v", err)
+		log.Fatalf("Failed to load category packs: %v", err)
 	}
-	repo := db.NewFirestoreRepository(firestoreClient)
 
-	router := api.NewRouter(repo, cfg)
+	router := api.NewRouter(repo, rates, packs, cfg)
 	if router == nil {
 		log.Fatal("Failed to create router")
 	}
 
-	defer func(firestoreClient *firestore.Client) {
-		err := firestoreClient.Close()
-		if err != nil {
-			log.Printf("Error closing Firestore client: %v", err)
-		} else {
-			log.Println("Firestore client closed successfully.")
-		}
-	}(firestoreClient)
+	defer closeRepo()
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -85,3 +88,74 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 }
+
+// newRateProvider loads the exchange rate table from cfg.FXRatesPath. With no
+// file configured only same-currency conversions are available.
+func newRateProvider(cfg *config.AppConfig) (fx.RateProvider, error) {
+	if cfg.FXRatesPath == "" {
+		log.Println("FX_RATES_PATH is not set, foreign currency amounts will not be converted")
+		return fx.NewTable(), nil
+	}
+	return fx.LoadTableFile(cfg.FXRatesPath)
+}
+
+// newCategoryPacks loads the built-in category packs and any in
+// cfg.CategoryPacksPath, and checks that new users' pack is among them.
+func newCategoryPacks(cfg *config.AppConfig) (*categories.Packs, error) {
+	packs, err := categories.LoadPacks(cfg.CategoryPacksPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := packs.Get(cfg.CategoryPack); !ok {
+		return nil, fmt.Errorf("CATEGORY_PACK %q is not a known category pack", cfg.CategoryPack)
+	}
+	return packs, nil
+}
+
+// newRepository builds the repository selected by cfg.StorageDriver along with
+// a function that releases any resources it holds.
+func newRepository(ctx context.Context, cfg *config.AppConfig) (db.Repository, func(), error) {
+	switch cfg.StorageDriver {
+	case config.StorageDriverMemory:
+		log.Println("Using in-memory repository, data will not be persisted")
+		return db.NewMemoryRepository(), func() {}, nil
+	case config.StorageDriverFirestore:
+		firestoreClient, err := db.NewFirestoreClient(ctx, cfg.ProjectID, cfg.LocalCredentialsPath, cfg.Environment)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create Firestore client: %w", err)
+		}
+		closeClient := func() {
+			if err := firestoreClient.Close(); err != nil {
+				log.Printf("Error closing Firestore client: %v", err)
+			} else {
+				log.Println("Firestore client closed successfully.")
+			}
+		}
+		repo := db.NewFirestoreRepository(firestoreClient)
+		if err := repo.Migrate(ctx); err != nil {
+			closeClient()
+			return nil, nil, fmt.Errorf("failed to migrate Firestore data: %w", err)
+		}
+		return repo, closeClient, nil
+	case config.StorageDriverPostgres, config.StorageDriverSQLite:
+		sqlDB, err := db.NewSQLClient(ctx, cfg.StorageDriver, cfg.DatabaseURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to %s: %w", cfg.StorageDriver, err)
+		}
+		repo := db.NewSQLRepository(sqlDB, cfg.StorageDriver)
+		if err := repo.Migrate(ctx); err != nil {
+			sqlDB.Close()
+			return nil, nil, fmt.Errorf("failed to migrate %s schema: %w", cfg.StorageDriver, err)
+		}
+		closeDB := func() {
+			if err := sqlDB.Close(); err != nil {
+				log.Printf("Error closing database: %v", err)
+			} else {
+				log.Println("Database closed successfully.")
+			}
+		}
+		return repo, closeDB, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported storage driver %q", cfg.StorageDriver)
+	}
+}
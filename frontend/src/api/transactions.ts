@@ -1,4 +1,4 @@
-import type { Transaction } from '../models/transaction';
+import type { ImportResult, Transaction } from '../models/transaction';
 import { getAuth } from 'firebase/auth';
 
 export async function getTransactions(): Promise<Transaction[]> {
@@ -72,7 +72,7 @@ export async function addTransaction(transaction: Transaction) {
   }
 }
 
-export async function importTransactions(file: File): Promise<Transaction[]> {
+export async function importTransactions(file: File): Promise<ImportResult> {
   const user = getAuth().currentUser;
   if (!user) throw new Error('Not authenticated');
   const idToken = await user.getIdToken();
@@ -91,7 +91,7 @@ export async function importTransactions(file: File): Promise<Transaction[]> {
     throw new Error(`Failed to import transactions: ${await res.text()}`);
   }
 
-  return (await res.json()) as Transaction[];
+  return (await res.json()) as ImportResult;
 }
 
 export async function deleteTransaction(id: string): Promise<void> {
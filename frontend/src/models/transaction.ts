@@ -7,3 +7,8 @@ export interface Transaction {
   transactionDateTime: string;
   bankReference?: string;
 }
+
+export interface ImportResult {
+  transactions: Transaction[];
+  skipped: number;
+}